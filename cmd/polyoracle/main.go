@@ -2,48 +2,164 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"math/rand"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"sort"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/rewired-gh/polyoracle/internal/config"
 	"github.com/rewired-gh/polyoracle/internal/logger"
+	"github.com/rewired-gh/polyoracle/internal/metrics"
 	"github.com/rewired-gh/polyoracle/internal/models"
 	"github.com/rewired-gh/polyoracle/internal/monitor"
 	"github.com/rewired-gh/polyoracle/internal/polymarket"
 	"github.com/rewired-gh/polyoracle/internal/storage"
+	"github.com/rewired-gh/polyoracle/internal/stream"
 	"github.com/rewired-gh/polyoracle/internal/telegram"
+	"github.com/rewired-gh/polyoracle/internal/telemetry"
+	"github.com/rewired-gh/polyoracle/internal/webhook"
 )
 
-var configPath = flag.String("config", "configs/config.yaml", "Path to configuration file")
+// defaultConfigPath is loaded when no -config flag is given.
+const defaultConfigPath = "configs/config.yaml"
+
+// shutdownAbortGrace bounds how long shutdown waits after cancelling an
+// overrunning cycle before exiting regardless.
+const shutdownAbortGrace = 5 * time.Second
+
+// configPaths collects repeated -config flags in order.
+type configPaths []string
+
+func (p *configPaths) String() string { return strings.Join(*p, ",") }
+
+func (p *configPaths) Set(path string) error {
+	*p = append(*p, path)
+	return nil
+}
+
+// configFiles lists the configuration files; later files overlay earlier ones.
+var configFiles configPaths
+
+func init() {
+	flag.Var(&configFiles, "config", "Path to a configuration file; repeat to overlay later files on earlier ones (default "+defaultConfigPath+")")
+}
+
+// Overrides for high-churn settings, applied on top of the loaded config.
+// Precedence: flag > environment > config file > default.
+var (
+	categoriesFlag  = flag.String("categories", "", "Comma-separated categories to monitor (overrides polymarket.categories)")
+	sensitivityFlag = flag.Float64("sensitivity", 0, "Alert sensitivity 0.0-1.0 (overrides monitor.sensitivity)")
+	topKFlag        = flag.Int("top-k", 0, "Max event groups per alert (overrides monitor.top_k)")
+	seedFlag        = flag.Int64("seed", 0, "Seed for randomized behavior, for reproducible runs (overrides monitor.random_seed)")
+)
 
 func main() {
 	flag.Parse()
+	if len(configFiles) == 0 {
+		configFiles = configPaths{defaultConfigPath}
+	}
 
-	// Load configuration
-	cfg, err := config.Load(*configPath)
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+	// validate checks the config and exits without starting anything
+	if flag.Arg(0) == "validate" {
+		if err := runValidate(flag.Args()[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Validation failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		log.Fatalf("Invalid configuration: %v", err)
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatal(err)
 	}
 
+	// Accept minor clock skew in stored and imported timestamps
+	models.SetFutureTimestampTolerance(cfg.Monitor.FutureTimestampTolerance)
+
 	// Setup logging with level support
 	logger.Init(cfg.Logging.Level, cfg.Logging.Format)
-	logger.Info("Configuration loaded from %s", *configPath)
+	logger.Info("Configuration loaded from %s", configFiles.String())
+
+	// Initialize OpenTelemetry tracing (spans are no-ops when disabled)
+	if cfg.OTel.Enabled {
+		if err := telemetry.Init(cfg.OTel.Endpoint); err != nil {
+			logger.Fatal("Failed to initialize OpenTelemetry tracing: %v", err)
+		}
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := telemetry.Shutdown(shutdownCtx); err != nil {
+				logger.Warn("Failed to flush traces: %v", err)
+			}
+		}()
+		logger.Info("OpenTelemetry tracing enabled (endpoint: %s)", cfg.OTel.Endpoint)
+	}
+
+	// Maintenance subcommands run against the database and exit
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "vacuum":
+			if err := runVacuum(cfg); err != nil {
+				logger.Fatal("Vacuum failed: %v", err)
+			}
+			return
+		case "export":
+			if err := runExport(cfg, flag.Args()[1:]); err != nil {
+				logger.Fatal("Export failed: %v", err)
+			}
+			return
+		case "import":
+			if err := runImport(cfg, flag.Args()[1:]); err != nil {
+				logger.Fatal("Import failed: %v", err)
+			}
+			return
+		case "dump-state":
+			if err := runDumpState(cfg, flag.Args()[1:]); err != nil {
+				logger.Fatal("Dump state failed: %v", err)
+			}
+			return
+		case "rerank":
+			if err := runRerank(cfg, flag.Args()[1:]); err != nil {
+				logger.Fatal("Rerank failed: %v", err)
+			}
+			return
+		case "calibration":
+			if err := runCalibration(cfg, flag.Args()[1:]); err != nil {
+				logger.Fatal("Calibration failed: %v", err)
+			}
+			return
+		case "backtest":
+			if err := runBacktest(cfg, flag.Args()[1:]); err != nil {
+				logger.Fatal("Backtest failed: %v", err)
+			}
+			return
+		default:
+			logger.Fatal("Unknown command %q (available: validate, vacuum, export, import, dump-state, rerank, calibration, backtest)", flag.Arg(0))
+		}
+	}
 
 	// Initialize storage
 	store, err := storage.New(
 		cfg.Storage.MaxEvents,
 		cfg.Storage.MaxSnapshotsPerEvent,
 		cfg.Storage.DBPath,
+		storage.Config{
+			ProbabilityEncoding: cfg.Storage.ProbabilityEncoding,
+			MaxMarketAge:        cfg.Storage.MaxMarketAge,
+			ReadConnections:     cfg.Storage.ReadConnections,
+		},
 	)
 	if err != nil {
 		logger.Fatal("Failed to initialize storage: %v", err)
@@ -54,6 +170,13 @@ func main() {
 		}
 	}()
 
+	// One seed drives every randomized component, so it reproduces the whole run
+	seed := cfg.Monitor.RandomSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	logger.Debug("Random seed: %d (set monitor.random_seed or --seed to reproduce)", seed)
+
 	// Initialize Polymarket client
 	polyClient := polymarket.NewClient(
 		cfg.Polymarket.GammaAPIURL,
@@ -65,40 +188,117 @@ func main() {
 			MaxIdleConns:        cfg.Polymarket.MaxIdleConns,
 			MaxIdleConnsPerHost: cfg.Polymarket.MaxIdleConnsPerHost,
 			IdleConnTimeout:     cfg.Polymarket.IdleConnTimeout,
+			SchemaDriftFraction: cfg.Polymarket.SchemaDriftFraction,
+			RetryOnEmpty:        cfg.Polymarket.RetryOnEmpty,
+			EventURLTemplate:    cfg.Polymarket.EventURLTemplate,
+			PageSize:            cfg.Polymarket.PageSize,
+			FetchConcurrency:    cfg.Polymarket.FetchConcurrency,
+			PerCategoryFetch:    cfg.Polymarket.PerCategoryFetch,
+			IncludeClosed:       cfg.Polymarket.IncludeClosed,
+			DefaultCategory:     cfg.Polymarket.DefaultCategory,
+			SingleMarketVolume:  cfg.Polymarket.SingleMarketEventVolume,
+			ResidualMode:        cfg.Polymarket.ResidualOutcomeMode,
+			CategoryVolumeMin:   categoryVolumeMin(cfg.Monitor.CategoryVolumeMin),
+			PositionalFallback:  cfg.Polymarket.PositionalOutcomeFallback,
+			DepthBand:           cfg.Polymarket.OrderBookDepthBand,
+			MultiOutcome:        cfg.Polymarket.MultiOutcomeMarkets,
+			Rand:                rand.New(rand.NewSource(seed)),
 		},
 	)
 
 	// Initialize monitor
-	mon := monitor.New(store)
+	monCfg, err := newMonitorConfig(cfg)
+	if err != nil {
+		logger.Fatal("Invalid monitor configuration: %v", err)
+	}
+	mon := monitor.New(store, monCfg)
+	if cfg.Monitor.RestoreEventCooldown && cfg.Monitor.EventCooldownMultiplier > 0 {
+		cooldown := time.Duration(cfg.Monitor.DetectionIntervals+1) * cfg.Polymarket.PollInterval
+		window := time.Duration(cfg.Monitor.EventCooldownMultiplier * float64(cooldown))
+		if n, err := mon.RestoreEventCooldowns(window); err != nil {
+			logger.Warn("Failed to restore event cooldowns from alert history: %v", err)
+		} else if n > 0 {
+			logger.Info("Restored %d event cooldowns from the last %v of alerts", n, window)
+		}
+	}
 
 	// Initialize Telegram client
 	var telegramClient *telegram.Client
+	var telegramInitAt time.Time // last failed init attempt (fail-open mode)
 	if cfg.Telegram.Enabled {
-		telegramClient, err = telegram.NewClient(cfg.Telegram.BotToken, cfg.Telegram.ChatID, cfg.Telegram.MaxRetries, cfg.Telegram.RetryDelayBase)
+		telegramClient, err = newTelegramClient(cfg, store)
 		if err != nil {
-			logger.Fatal("Failed to initialize Telegram client: %v", err)
+			if !cfg.Telegram.FailOpen {
+				logger.Fatal("Failed to initialize Telegram client: %v", err)
+			}
+			// Monitoring doesn't depend on Telegram; keep running without
+			// notifications and retry init between cycles.
+			logger.Error("Failed to initialize Telegram client, continuing without notifications (retry every %v): %v",
+				cfg.Telegram.InitRetryInterval, err)
+			telegramInitAt = time.Now()
+		} else {
+			logger.Info("Telegram client initialized successfully")
 		}
-		logger.Info("Telegram client initialized successfully")
 	} else {
 		logger.Debug("Telegram notifications disabled")
 	}
 
-	// Setup graceful shutdown
+	// Initialize webhook notifier
+	var webhookClient *webhook.Client
+	if cfg.Webhook.URL != "" {
+		webhookClient, err = webhook.NewClient(cfg.Webhook.URL, cfg.Webhook.MaxRetries, cfg.Webhook.RetryDelayBase, cfg.Webhook.Timeout)
+		if err != nil {
+			logger.Fatal("Failed to initialize webhook notifier: %v", err)
+		}
+		logger.Info("Webhook notifier initialized")
+	}
+
+	// Setup graceful shutdown: the first signal closes stopping, so no new cycle
+	// starts and an in-flight one finishes; ctx is cancelled, aborting that
+	// cycle's requests, once it overruns monitor.shutdown_timeout or on a second
+	// signal.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	stopping := make(chan struct{})
 
-	sigChan := make(chan os.Signal, 1)
+	sigChan := make(chan os.Signal, 2)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		<-sigChan
-		logger.Info("Shutdown signal received, cleaning up...")
+		logger.Info("Shutdown signal received, finishing the current cycle (up to %v)...", cfg.Monitor.ShutdownTimeout)
+		close(stopping)
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigChan:
+			logger.Warn("Second shutdown signal received, aborting the current cycle")
+		case <-time.After(cfg.Monitor.ShutdownTimeout):
+			logger.Warn("Cycle still running after %v, aborting it", cfg.Monitor.ShutdownTimeout)
+		}
 		cancel()
+		// Work that doesn't observe ctx gets a last grace period
+		time.Sleep(shutdownAbortGrace)
+		logger.Error("Shutdown still incomplete %v after aborting; exiting", shutdownAbortGrace)
+		os.Exit(1)
 	}()
 
+	// Start Prometheus metrics endpoint (with /readyz)
+	ready := &readiness{}
+	if cfg.Metrics.ListenAddr != "" {
+		startMetricsServer(ctx, cfg.Metrics.ListenAddr, ready)
+	}
+
+	// Start live alert stream (nil broker = publishing is a no-op)
+	var alertStream *stream.Broker
+	if cfg.Stream.ListenAddr != "" {
+		alertStream = stream.NewBroker(cfg.Stream.ClientBuffer)
+		startStreamServer(ctx, cfg.Stream.ListenAddr, alertStream)
+	}
+
 	// Start Telegram command listener
 	if cfg.Telegram.Enabled && telegramClient != nil {
-		telegramClient.ListenForCommands(ctx)
+		telegramClient.ListenForCommands(ctx, mon)
 	}
 
 	// Start monitoring loop
@@ -118,69 +318,255 @@ func main() {
 
 	ticker := time.NewTicker(cfg.Polymarket.PollInterval)
 	defer ticker.Stop()
+	interval := newAdaptiveInterval(cfg.Polymarket)
 
+	notices := &errorNotices{cooldown: cfg.Telegram.ErrorCooldown}
+	coalescer := &alertCoalescer{window: cfg.Telegram.CoalesceWindow}
+	summaryOut, closeSummary, err := openCycleSummaryOutput(cfg.Monitor.CycleSummaryOutput)
+	if err != nil {
+		logger.Fatal("Failed to open monitor.cycle_summary_output: %v", err)
+	}
+	defer closeSummary()
+	lastVacuum := time.Now()
 	consecutiveFailures := 0
 
-	handleCycleResult := func(err error) {
+	handleCycleResult := func(alerts int, err error) {
+		if err != nil && ctx.Err() != nil {
+			// Aborted by shutdown, not a failure worth a notice
+			logger.Warn("Monitoring cycle aborted by shutdown: %v", err)
+			return
+		}
 		if err != nil {
 			consecutiveFailures++
+		} else {
+			consecutiveFailures = 0
+		}
+		metrics.ConsecutiveFailures.Set(float64(consecutiveFailures))
+		if err == nil {
+			if next, changed := interval.observe(alerts); changed {
+				logger.Info("Poll interval now %v (%d alert groups this cycle)", next, alerts)
+				ticker.Reset(next)
+			}
+		}
+		if err != nil {
 			logger.Error("Monitoring cycle failed: %v", err)
-			if consecutiveFailures == 1 && cfg.Telegram.Enabled && telegramClient != nil {
-				if sendErr := telegramClient.SendError(err); sendErr != nil {
-					logger.Warn("Failed to send error notification to Telegram: %v", sendErr)
+			if notices.failure(time.Now()) {
+				for _, n := range alertNotifiers(cfg, telegramClient, webhookClient) {
+					if sendErr := n.SendError(err); sendErr != nil {
+						logger.Warn("Failed to send error notification: %v", sendErr)
+					}
 				}
 			}
 		} else {
-			if consecutiveFailures > 0 && cfg.Telegram.Enabled && telegramClient != nil {
-				if sendErr := telegramClient.SendRecovery(consecutiveFailures); sendErr != nil {
-					logger.Warn("Failed to send recovery notification to Telegram: %v", sendErr)
+			if failures, ok := notices.success(time.Now()); ok {
+				for _, n := range alertNotifiers(cfg, telegramClient, webhookClient) {
+					if sendErr := n.SendRecovery(failures); sendErr != nil {
+						logger.Warn("Failed to send recovery notification: %v", sendErr)
+					}
 				}
 			}
-			consecutiveFailures = 0
+		}
+	}
+
+	// Warn once per drift episode; re-arm after a healthy fetch.
+	schemaDriftNotified := false
+	checkSchemaDrift := func() {
+		fraction, drifted := polyClient.SchemaDrift()
+		if !drifted {
+			schemaDriftNotified = false
+			return
+		}
+		if schemaDriftNotified || !cfg.Polymarket.SchemaDriftNotify || !cfg.Telegram.Enabled || telegramClient == nil {
+			return
+		}
+		if err := telegramClient.SendSchemaDriftWarning(fraction); err != nil {
+			logger.Warn("Failed to send schema drift warning to Telegram: %v", err)
+			return
+		}
+		schemaDriftNotified = true
+	}
+
+	// retryTelegramInit re-attempts a failed fail-open Telegram init once the retry
+	// interval has elapsed, starting the command listener on success.
+	retryTelegramInit := func() {
+		if !cfg.Telegram.Enabled || telegramClient != nil || time.Since(telegramInitAt) < cfg.Telegram.InitRetryInterval {
+			return
+		}
+		client, err := newTelegramClient(cfg, store)
+		if err != nil {
+			telegramInitAt = time.Now()
+			logger.Warn("Telegram client still unavailable: %v", err)
+			return
+		}
+		telegramClient = client
+		telegramClient.ListenForCommands(ctx, mon)
+		logger.Info("Telegram client initialized after retry; notifications enabled")
+	}
+
+	// runScheduledCycle runs one cycle followed by between-cycle maintenance.
+	runScheduledCycle := func(tickTime time.Time) {
+		retryTelegramInit()
+		logger.Debug("Starting scheduled monitoring cycle")
+		handleCycleResult(runMonitoringCycle(ctx, polyClient, mon, store, telegramClient, webhookClient, alertStream, coalescer, ready, summaryOut, cfg, interval.current, tickTime))
+		checkSchemaDrift()
+
+		// Rotate old data
+		if err := store.RotateSnapshots(); err != nil {
+			logger.Warn("Failed to rotate snapshots: %v", err)
+		}
+		if err := store.RotateMarkets(); err != nil {
+			logger.Warn("Failed to rotate markets: %v", err)
+		}
+
+		// Vacuum between cycles: the loop is idle and holds the only connection.
+		if cfg.Storage.AutoVacuumInterval > 0 && time.Since(lastVacuum) >= cfg.Storage.AutoVacuumInterval {
+			lastVacuum = time.Now()
+			if before, after, err := store.Vacuum(); err != nil {
+				logger.Warn("Failed to vacuum storage: %v", err)
+			} else {
+				logger.Info("Vacuumed storage: %d → %d bytes (%d reclaimed)", before, after, before-after)
+			}
 		}
 	}
 
 	// Run initial poll immediately
+	floor := &pollFloor{min: cfg.Polymarket.MinEffectiveInterval}
+	floor.admit(time.Now())
 	logger.Debug("Running initial monitoring cycle")
-	handleCycleResult(runMonitoringCycle(ctx, polyClient, mon, store, telegramClient, cfg, time.Now()))
+	handleCycleResult(runMonitoringCycle(ctx, polyClient, mon, store, telegramClient, webhookClient, alertStream, coalescer, ready, summaryOut, cfg, interval.current, time.Now()))
+	checkSchemaDrift()
 
 	for {
+		// A shutdown takes precedence over ticks that are due at the same time
 		select {
-		case <-ctx.Done():
+		case <-stopping:
+			if groups := coalescer.take(); len(groups) > 0 {
+				notifyGroups(ctx, alertNotifiers(cfg, telegramClient, webhookClient), mon, store, groups)
+			}
 			logger.Info("Service stopped")
 			return
+		default:
+		}
 
-		case tickTime := <-ticker.C:
-			logger.Debug("Starting scheduled monitoring cycle")
-			handleCycleResult(runMonitoringCycle(ctx, polyClient, mon, store, telegramClient, cfg, tickTime))
+		select {
+		case <-stopping:
+			continue
+
+		case <-coalescer.ready():
+			notifyGroups(ctx, alertNotifiers(cfg, telegramClient, webhookClient), mon, store, coalescer.take())
 
-			// Rotate old data
-			if err := store.RotateSnapshots(); err != nil {
-				logger.Warn("Failed to rotate snapshots: %v", err)
+		case tickTime := <-ticker.C:
+			if mon.Paused() {
+				logger.Debug("Monitoring paused, skipping scheduled cycle")
+				continue
+			}
+			if !floor.admit(time.Now()) {
+				logger.Debug("Deferring cycle: less than %v since the last one started", floor.min)
+				continue
 			}
-			if err := store.RotateMarkets(); err != nil {
-				logger.Warn("Failed to rotate markets: %v", err)
+			runScheduledCycle(tickTime)
+
+		case tickTime := <-floor.ready():
+			floor.release(time.Now())
+			if mon.Paused() {
+				logger.Debug("Monitoring paused, skipping deferred cycle")
+				continue
 			}
+			runScheduledCycle(tickTime)
 		}
 	}
 }
 
+// pollFloor enforces polymarket.min_effective_interval between cycle starts,
+// whatever triggered them. A trigger arriving sooner is deferred until the floor
+// has passed; further early triggers meanwhile coalesce into that one cycle.
+type pollFloor struct {
+	min      time.Duration
+	last     time.Time   // start of the most recent cycle
+	deferred *time.Timer // pending deferred cycle; nil if none
+}
+
+// admit reports whether a cycle may start at now, recording it as the latest
+// start if so. Otherwise it arms a deferred cycle, unless one is already pending.
+func (p *pollFloor) admit(now time.Time) bool {
+	if p.deferred != nil {
+		return false
+	}
+	if wait := p.min - now.Sub(p.last); !p.last.IsZero() && wait > 0 {
+		p.deferred = time.NewTimer(wait)
+		return false
+	}
+	p.last = now
+	return true
+}
+
+// ready fires when the deferred cycle is due. It is nil, and so never ready,
+// while none is pending.
+func (p *pollFloor) ready() <-chan time.Time {
+	if p.deferred == nil {
+		return nil
+	}
+	return p.deferred.C
+}
+
+// release starts the deferred cycle at now.
+func (p *pollFloor) release(now time.Time) {
+	p.deferred = nil
+	p.last = now
+}
+
 func runMonitoringCycle(
 	ctx context.Context,
 	polyClient *polymarket.Client,
 	mon *monitor.Monitor,
-	store *storage.Storage,
+	store storage.Store,
 	telegramClient *telegram.Client,
+	webhookClient *webhook.Client,
+	alertStream *stream.Broker,
+	coalescer *alertCoalescer,
+	ready *readiness,
+	summaryOut io.Writer, // per-cycle JSON summary destination; nil = off
 	cfg *config.Config,
+	pollInterval time.Duration, // current (possibly adaptive) interval; sizes the detection window
 	cycleTime time.Time, // tick time (or startup time for the initial cycle)
-) error {
+) (alertGroups int, err error) {
 	startTime := time.Now()
 	logger.Info("Starting monitoring cycle")
 
+	ctx, span := telemetry.Start(ctx, "monitoring_cycle")
+	defer func() {
+		metrics.CycleDuration.Observe(time.Since(startTime).Seconds())
+		span.SetAttributes(telemetry.Int("cycle.duration_ms", int(time.Since(startTime).Milliseconds())))
+		span.RecordError(err)
+		span.End()
+	}()
+
+	summary := models.CycleSummary{Version: models.CycleSummaryVersion, StartedAt: startTime}
+	defer func() {
+		if summaryOut == nil {
+			return
+		}
+		summary.DurationMs = time.Since(startTime).Milliseconds()
+		if err != nil {
+			summary.Error = err.Error()
+		}
+		writeCycleSummary(summaryOut, summary)
+	}()
+
+	// Inside a maintenance window detection still runs, but nothing is sent
+	notify := cfg.Telegram.Enabled && telegramClient != nil
+	maintenance, inMaintenance := mon.ActiveMaintenanceWindow()
+	summary.Maintenance = inMaintenance
+	if inMaintenance {
+		logger.Info("Maintenance window %q active; notifications suppressed", maintenance)
+		notify = false
+	}
+
 	// Fetch events from Polymarket
 	logger.Debug("Fetching events from Polymarket API (categories: %v, limit: %d)", cfg.Polymarket.Categories, cfg.Polymarket.Limit)
+	fetchCtx, fetchSpan := telemetry.Start(ctx, "fetch")
 	events, err := polyClient.FetchEvents(
-		ctx,
+		fetchCtx,
 		cfg.Polymarket.Categories,
 		cfg.Polymarket.Volume24hrMin,
 		cfg.Polymarket.Volume1wkMin,
@@ -188,15 +574,28 @@ func runMonitoringCycle(
 		cfg.Polymarket.VolumeFilterOR,
 		cfg.Polymarket.Limit,
 	)
+	fetchSpan.End()
 	if err != nil {
-		return fmt.Errorf("failed to fetch events: %w", err)
+		return 0, fmt.Errorf("failed to fetch events: %w", err)
 	}
 	logger.Info("Fetched %d events from %d categories", len(events), len(cfg.Polymarket.Categories))
+	summary.MarketsFetched = len(events)
+	metrics.MarketsFetched.Add(float64(len(events)))
+	span.SetAttributes(telemetry.Int("markets.fetched", len(events)))
 
 	// Update storage with new events and create snapshots
 	logger.Debug("Processing fetched events and creating snapshots")
+	_, processSpan := telemetry.Start(ctx, "process")
 	newEvents := 0
 	updatedEvents := 0
+	var newListings []models.Market
+	var closed []monitor.ClosedMarket
+	var snapshotWrite time.Duration
+	snapshotFailures := 0
+	processedByCategory := make(map[string]int, len(cfg.Polymarket.Categories))
+	for _, c := range cfg.Polymarket.Categories {
+		processedByCategory[c] = 0
+	}
 	for i := range events {
 		event := &events[i]
 
@@ -206,14 +605,31 @@ func runMonitoringCycle(
 			// Event doesn't exist, create it
 			if err := store.AddMarket(event); err != nil {
 				logger.Warn("Failed to add event %s: %v", event.ID, err)
+				summary.Skip(models.SkipStoreError, 1)
 				continue
 			}
 			newEvents++
+			if cfg.Monitor.NotifyNewMarkets && event.Volume24hr >= cfg.Monitor.NewMarketVolumeMin {
+				newListings = append(newListings, *event)
+			}
 		} else {
 			// Update existing event
 			event.CreatedAt = existingEvent.CreatedAt
+			// Score and announce a closing on the raw final price, before smoothing
+			if event.Closed && !existingEvent.Closed {
+				if cfg.Monitor.Calibration {
+					recordCalibration(store, *event, cycleTime, cfg.Monitor.CalibrationLeadTime)
+				}
+				closed = append(closed, monitor.ClosedMarket{Last: *existingEvent, Final: *event})
+			}
+			// Smooth against the stored (already smoothed) price; applied to both
+			// sides so Yes + No still sums to 1.
+			alpha := cfg.Monitor.PriceSmoothingAlpha
+			event.YesProbability = monitor.SmoothProbability(existingEvent.YesProbability, event.YesProbability, alpha)
+			event.NoProbability = monitor.SmoothProbability(existingEvent.NoProbability, event.NoProbability, alpha)
 			if err := store.UpdateMarket(event); err != nil {
 				logger.Warn("Failed to update event %s: %v", event.ID, err)
+				summary.Skip(models.SkipStoreError, 1)
 				continue
 			}
 			updatedEvents++
@@ -228,32 +644,114 @@ func runMonitoringCycle(
 			EventID:        event.ID,
 			YesProbability: event.YesProbability,
 			NoProbability:  event.NoProbability,
+			Volume24hr:     event.Volume24hr,
+			Liquidity:      event.Liquidity,
 			Timestamp:      cycleTime,
 			Source:         "polymarket-gamma-api",
 		}
 
+		writeStart := time.Now()
 		if err := store.AddSnapshot(snapshot); err != nil {
 			logger.Warn("Failed to add snapshot for event %s: %v", event.ID, err)
+			snapshotFailures++
 		}
+		snapshotWrite += time.Since(writeStart)
+		processedByCategory[metrics.CategoryLabel(event.Category, cfg.Polymarket.Categories)]++
+	}
+	for category, n := range processedByCategory {
+		metrics.MarketsProcessed.Set(category, float64(n))
 	}
 	logger.Debug("Event processing complete: %d new, %d updated", newEvents, updatedEvents)
+	checkSnapshotWrites(ready, telegramClient, cfg, snapshotWrite, snapshotFailures, newEvents+updatedEvents)
+	summary.MarketsNew, summary.MarketsUpdated = newEvents, updatedEvents
+	summary.Skip(models.SkipSnapshotError, snapshotFailures)
+	processSpan.SetAttributes(telemetry.Int("markets.new", newEvents), telemetry.Int("markets.updated", updatedEvents))
+	processSpan.End()
+
+	// Announce newly listed high-volume markets. When nothing was updated the store
+	// was empty (first run or fresh DB), so every market looks new — skip that cycle.
+	if len(newListings) > 0 && updatedEvents > 0 {
+		logger.Info("Detected %d new markets above $%.0f 24hr volume", len(newListings), cfg.Monitor.NewMarketVolumeMin)
+		if !inMaintenance {
+			announceNewMarkets(alertNotifiers(cfg, telegramClient, webhookClient), store, newListings, cycleTime)
+		}
+	}
+
+	// Guard against silent coverage loss (category renames, upstream filtering, partial outages)
+	processed := newEvents + updatedEvents
+	if baseline, dropped := mon.ObserveCoverage(processed); dropped {
+		logger.Warn("Coverage drop: processed %d markets this cycle vs rolling average %.0f", processed, baseline)
+		if notify {
+			if err := telegramClient.SendCoverageDrop(processed, baseline); err != nil {
+				logger.Warn("Failed to send coverage drop warning to Telegram: %v", err)
+			}
+		}
+	}
+
+	// Keep in-memory monitor state aligned with the markets still being fetched
+	if evicted := mon.ForgetMissing(events); evicted > 0 {
+		logger.Debug("Forgot %d markets absent for %d consecutive cycles", evicted, cfg.Monitor.MissingCyclesBeforeCleanup)
+	}
+
+	// Markets pinned at 0/1 awaiting resolution are excluded from detection below
+	if resolving := mon.ObserveResolution(events); len(resolving) > 0 {
+		summary.Skip(models.SkipResolving, len(resolving))
+		logger.Info("%d markets pinned at an extreme for over %d polls; treating as resolving", len(resolving), cfg.Monitor.ResolutionPendingCycles)
+		if cfg.Monitor.ResolutionNotify && notify {
+			if err := telegramClient.SendResolving(resolving); err != nil {
+				logger.Warn("Failed to send resolving notification to Telegram: %v", err)
+			}
+		}
+	}
+
+	// Markets that closed since the last poll: announce the outcome, then drop
+	// their monitor state
+	if resolved := mon.Resolutions(closed); len(resolved) > 0 {
+		logger.Info("%d tracked markets closed this poll", countMarkets(resolved))
+		if cfg.Monitor.NotifyResolved && !inMaintenance {
+			notifyGroups(ctx, alertNotifiers(cfg, telegramClient, webhookClient), mon, store, resolved)
+		}
+		mon.ForgetResolved(resolved)
+	}
+
+	// Threshold ladders priced out of order (opt-in): mispricing or bad data
+	if inconsistent := mon.DetectLadderInconsistencies(events); len(inconsistent) > 0 {
+		logger.Info("Detected %d ladder inconsistencies", len(inconsistent))
+		if notify {
+			if err := telegramClient.SendInconsistencies(inconsistent); err != nil {
+				logger.Warn("Failed to send ladder inconsistency notification to Telegram: %v", err)
+			}
+		}
+	}
+
+	// Liquidity collapse alerts (opt-in), reported separately from odds movements
+	if drops := mon.DetectLiquidityDrops(events); len(drops) > 0 {
+		logger.Info("Detected %d liquidity drops", len(drops))
+		if notify {
+			if err := telegramClient.SendLiquidityDrops(drops); err != nil {
+				logger.Warn("Failed to send liquidity drop notification to Telegram: %v", err)
+			}
+		}
+	}
 
 	// Detect significant changes
+	_, detectSpan := telemetry.Start(ctx, "detect")
+	defer detectSpan.End() // covers early returns; ending twice is a no-op
 	allEvents, err := store.GetAllMarkets()
 	if err != nil {
-		return fmt.Errorf("failed to get events: %w", err)
+		return 0, fmt.Errorf("failed to get events: %w", err)
 	}
 	// Window = (N+1) × pollInterval, not N × pollInterval.
 	// With cycleTime-stamped snapshots, the oldest snapshot from N cycles ago is
 	// exactly N×pollInterval old at tick time, but GetSnapshotsInWindow runs after
 	// processing completes (tick + τ), making it N×pollInterval + τ old. The extra
 	// interval absorbs τ so the boundary snapshot is never accidentally excluded.
-	detectionWindow := time.Duration(cfg.Monitor.DetectionIntervals+1) * cfg.Polymarket.PollInterval
+	detectionWindow := time.Duration(cfg.Monitor.DetectionIntervals+1) * pollInterval
 	logger.Debug("Detecting changes across %d total events (window: %v = (%d+1) × %v)",
-		len(allEvents), detectionWindow, cfg.Monitor.DetectionIntervals, cfg.Polymarket.PollInterval)
-	changes, detectionErrors, err := mon.DetectChanges(convertMarkets(allEvents), detectionWindow)
+		len(allEvents), detectionWindow, cfg.Monitor.DetectionIntervals, pollInterval)
+	changes, detectionErrors, err := mon.DetectChanges(mon.ExcludeResolving(convertMarkets(allEvents)), detectionWindow)
 	if err != nil {
-		return fmt.Errorf("failed to detect changes: %w", err)
+		return 0, fmt.Errorf("failed to detect changes: %w", err)
 	}
 	for _, detErr := range detectionErrors {
 		logger.Warn("Failed to detect changes for event %s: %v", detErr.EventID, detErr.Err)
@@ -270,6 +768,17 @@ func runMonitoringCycle(
 	}
 
 	logger.Info("Detected %d changes above floor", len(changes))
+	summary.Changes = len(changes)
+
+	// Coin-flip convergence alerts (opt-in), independent of composite scoring
+	if uncertain := mon.DetectUncertainty(changes); len(uncertain) > 0 {
+		logger.Info("Detected %d markets converging toward 50%%", len(uncertain))
+		if notify {
+			if err := telegramClient.SendUncertainty(uncertain); err != nil {
+				logger.Warn("Failed to send uncertainty notification to Telegram: %v", err)
+			}
+		}
+	}
 
 	// Score and rank changes using composite signal quality.
 	// The four factors (KL, volume, SNR, trajectory) are already window-agnostic:
@@ -280,26 +789,62 @@ func runMonitoringCycle(
 	topGroups := mon.ScoreAndRank(changes, marketsMap, minScore, cfg.Monitor.TopK, cfg.Polymarket.Volume24hrMin, cfg.Monitor.MinAbsChange, cfg.Monitor.MinBaseProb)
 
 	// Suppress recently-sent markets (same direction, within cooldown window)
+	scored := countMarkets(topGroups)
 	topGroups = mon.FilterRecentlySent(topGroups, detectionWindow)
+	cooled := countMarkets(topGroups)
+	summary.Skip(models.SkipCooldown, scored-cooled)
+	// Drop markets that already used their daily alert budget
+	topGroups = mon.FilterAlertBudget(topGroups)
+	summary.Skip(models.SkipAlertBudget, cooled-countMarkets(topGroups))
+	summary.AlertGroups, summary.AlertMarkets = len(topGroups), countMarkets(topGroups)
+	detectSpan.SetAttributes(telemetry.Int("changes.detected", len(changes)))
+	detectSpan.End()
 
 	if len(topGroups) > 0 {
-		totalMarkets := 0
+		totalMarkets := summary.AlertMarkets
+		// Stored changes predate scoring; record the score inputs of those that alert
+		var alerted []models.Change
 		for _, g := range topGroups {
-			totalMarkets += len(g.Markets)
+			alerted = append(alerted, g.Markets...)
 		}
+		if err := store.UpdateChangeScores(alerted); err != nil {
+			logger.Warn("Failed to record alert scores: %v", err)
+		}
+
+		span.SetAttributes(telemetry.Int("alerts", totalMarkets))
+		telemetry.RecordAlerts(span, topGroups)
 		logger.Info("Scored changes: %d detected, %d groups (%d markets) passed quality bar (min_score=%.4f)",
 			len(changes), len(topGroups), totalMarkets, minScore)
 
-		if cfg.Telegram.Enabled && telegramClient != nil {
-			logger.Debug("Sending top %d event groups to Telegram", len(topGroups))
-			if err := telegramClient.Send(topGroups); err != nil {
-				logger.Error("Failed to send Telegram notification: %v", err)
-			} else {
-				logger.Info("Sent Telegram notification with top %d event groups", len(topGroups))
-				mon.RecordNotified(topGroups)
+		for _, g := range topGroups {
+			for _, change := range g.Markets {
+				category := ""
+				if market, ok := marketsMap[change.EventID]; ok {
+					category = market.Category
+				}
+				metrics.AlertsTotal.Inc(metrics.CategoryLabel(category, cfg.Polymarket.Categories))
 			}
+		}
+
+		// Live subscribers see every alert, independent of Telegram delivery
+		alertStream.Publish(topGroups)
+		metrics.AlertsEmitted.Add(float64(len(topGroups)))
+
+		if cfg.Telegram.ShowSparkline {
+			attachTrends(store, topGroups, cfg.Telegram.SparklinePoints)
+		}
+		if cfg.Telegram.ShowLastAlert {
+			attachLastAlerts(store, topGroups)
+		}
+
+		if inMaintenance {
+			logger.Info("Not sending %d event groups during maintenance window %q", len(topGroups), maintenance)
+			summary.Skip(models.SkipMaintenance, countMarkets(topGroups))
+		} else if notifiers := alertNotifiers(cfg, telegramClient, webhookClient); coalescer.window > 0 && len(notifiers) > 0 {
+			coalescer.add(mon, topGroups)
+			logger.Info("Holding %d event groups for up to %v to coalesce with later cycles", len(topGroups), coalescer.window)
 		} else {
-			logger.Debug("Changes detected but Telegram notifications disabled or client not initialized")
+			notifyGroups(ctx, notifiers, mon, store, topGroups)
 		}
 	} else {
 		logger.Info("No changes above quality bar this cycle (min_score=%.4f)", minScore)
@@ -308,11 +853,1118 @@ func runMonitoringCycle(
 	duration := time.Since(startTime)
 	logger.Info("Monitoring cycle completed in %v", duration)
 
-	return nil
+	return len(topGroups), nil
 }
 
-func generateID() string {
-	return fmt.Sprintf("%d", time.Now().UnixNano())
+// openCycleSummaryOutput resolves monitor.cycle_summary_output: "" = off (nil
+// writer), "stdout", "stderr", or a file path opened for appending. The
+// returned func closes the file, if one was opened.
+func openCycleSummaryOutput(dest string) (io.Writer, func(), error) {
+	switch dest {
+	case "":
+		return nil, func() {}, nil
+	case "stdout":
+		return os.Stdout, func() {}, nil
+	case "stderr":
+		return os.Stderr, func() {}, nil
+	}
+	f, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, func() { _ = f.Close() }, nil
+}
+
+// countMarkets returns the number of markets across groups.
+func countMarkets(groups []models.Event) int {
+	n := 0
+	for _, g := range groups {
+		n += len(g.Markets)
+	}
+	return n
+}
+
+// writeCycleSummary writes s to w as one JSON line.
+func writeCycleSummary(w io.Writer, s models.CycleSummary) {
+	line, err := json.Marshal(s)
+	if err != nil {
+		logger.Warn("Failed to encode cycle summary: %v", err)
+		return
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		logger.Warn("Failed to write cycle summary: %v", err)
+	}
+}
+
+// notifier delivers alert groups, new market announcements and cycle
+// error/recovery notices to one destination. *telegram.Client and
+// *webhook.Client implement it.
+type notifier interface {
+	Send(groups []models.Event) error
+	SendNewMarkets(markets []models.Market) error
+	SendError(cycleErr error) error
+	SendRecovery(failureCount int) error
+}
+
+// alertNotifiers returns the enabled notifiers that are ready to send. Telegram
+// is left out while a fail-open init is still failing.
+func alertNotifiers(cfg *config.Config, telegramClient *telegram.Client, webhookClient *webhook.Client) []notifier {
+	var notifiers []notifier
+	if cfg.Telegram.Enabled && telegramClient != nil {
+		notifiers = append(notifiers, telegramClient)
+	}
+	if webhookClient != nil {
+		notifiers = append(notifiers, webhookClient)
+	}
+	return notifiers
+}
+
+// notifyGroups sends alert groups to every notifier and, once any delivered
+// them, records them for cooldowns, alert budgets and /history.
+func notifyGroups(
+	ctx context.Context,
+	notifiers []notifier,
+	mon *monitor.Monitor,
+	store storage.Store,
+	groups []models.Event,
+) {
+	if len(notifiers) == 0 {
+		logger.Debug("Changes detected but no notifiers enabled or initialized")
+		return
+	}
+
+	logger.Debug("Sending top %d event groups to %d notifier(s)", len(groups), len(notifiers))
+	_, notifySpan := telemetry.Start(ctx, "notify")
+	delivered := 0
+	for _, n := range notifiers {
+		if err := n.Send(groups); err != nil {
+			logger.Error("Failed to send alert notification: %v", err)
+			continue
+		}
+		delivered++
+	}
+	notifySpan.End()
+	if delivered == 0 {
+		return
+	}
+	logger.Info("Sent top %d event groups to %d of %d notifier(s)", len(groups), delivered, len(notifiers))
+	mon.RecordNotified(groups)
+	var alerted []models.Change
+	for _, g := range groups {
+		alerted = append(alerted, g.Markets...)
+	}
+	if err := store.AddAlerts(alerted); err != nil {
+		logger.Warn("Failed to record alert history: %v", err)
+	}
+}
+
+// announceNewMarkets sends first-seen markets that were never announced before
+// to every notifier and, once any delivered them, records them so a market
+// rotated out of storage and fetched again isn't announced twice.
+func announceNewMarkets(notifiers []notifier, store storage.Store, markets []models.Market, now time.Time) {
+	ids := make([]string, len(markets))
+	for i, m := range markets {
+		ids[i] = m.ID
+	}
+	announced, err := store.Announced(ids)
+	if err != nil {
+		logger.Warn("Failed to read new market announcements: %v", err)
+		return
+	}
+	var fresh []models.Market
+	ids = ids[:0]
+	for _, m := range markets {
+		if !announced[m.ID] {
+			fresh = append(fresh, m)
+			ids = append(ids, m.ID)
+		}
+	}
+	if len(fresh) == 0 || len(notifiers) == 0 {
+		return
+	}
+
+	delivered := 0
+	for _, n := range notifiers {
+		if err := n.SendNewMarkets(fresh); err != nil {
+			logger.Warn("Failed to send new market notification: %v", err)
+			continue
+		}
+		delivered++
+	}
+	if delivered == 0 {
+		return
+	}
+	if err := store.MarkAnnounced(ids, now); err != nil {
+		logger.Warn("Failed to record new market announcements: %v", err)
+	}
+}
+
+// checkSnapshotWrites exports the cycle's snapshot write time and failures and,
+// with storage.snapshot_failure_threshold set, flips readiness while failures
+// reach it. A Telegram warning (opt-in) goes out once per failing streak.
+func checkSnapshotWrites(ready *readiness, telegramClient *telegram.Client, cfg *config.Config, elapsed time.Duration, failed, total int) {
+	metrics.SnapshotWriteSeconds.Set(elapsed.Seconds())
+	metrics.SnapshotWriteFailures.Set(float64(failed))
+	logger.Debug("Wrote %d snapshots in %v (%d failed)", total, elapsed, failed)
+
+	threshold := cfg.Storage.SnapshotFailureThreshold
+	if threshold <= 0 {
+		return
+	}
+	if failed < threshold {
+		if ready.set("") {
+			logger.Info("Snapshot writes healthy again; ready")
+		}
+		return
+	}
+	logger.Warn("%d of %d snapshot writes failed this cycle (threshold %d); not ready", failed, total, threshold)
+	if ready.set(fmt.Sprintf("%d of %d snapshot writes failed in the last cycle", failed, total)) &&
+		cfg.Storage.SnapshotFailureNotify && cfg.Telegram.Enabled && telegramClient != nil {
+		if err := telegramClient.SendSnapshotFailures(failed, total); err != nil {
+			logger.Warn("Failed to send snapshot failure warning to Telegram: %v", err)
+		}
+	}
+}
+
+// readiness backs GET /readyz: ready unless a health check has recorded why not.
+type readiness struct {
+	mu     sync.Mutex
+	reason string // empty = ready
+}
+
+// set records why the service is not ready ("" = ready) and reports whether
+// readiness changed.
+func (r *readiness) set(reason string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := (r.reason == "") != (reason == "")
+	r.reason = reason
+	return changed
+}
+
+func (r *readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.mu.Lock()
+	reason := r.reason
+	r.mu.Unlock()
+	if reason != "" {
+		http.Error(w, "not ready: "+reason, http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// alertCoalescer holds alert groups for telegram.coalesce_window so that
+// back-to-back cycles produce one combined message instead of several.
+type alertCoalescer struct {
+	window  time.Duration
+	pending []models.Event
+	timer   *time.Timer // running while groups are pending
+}
+
+// add merges groups into the pending batch, opening a window if none is open.
+func (c *alertCoalescer) add(mon *monitor.Monitor, groups []models.Event) {
+	c.pending = mon.MergeGroups(c.pending, groups)
+	if c.timer == nil {
+		c.timer = time.NewTimer(c.window)
+	}
+}
+
+// ready fires when the open window closes. It is nil, and so never ready,
+// while nothing is pending.
+func (c *alertCoalescer) ready() <-chan time.Time {
+	if c.timer == nil {
+		return nil
+	}
+	return c.timer.C
+}
+
+// take returns the pending groups and closes the window.
+func (c *alertCoalescer) take() []models.Event {
+	groups := c.pending
+	c.pending = nil
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	return groups
+}
+
+// applyFlagOverrides copies explicitly set command-line flags into cfg. Only flags
+// given on the command line are applied, so an unset flag never masks a value
+// from the environment or config file. Validation runs afterwards as usual.
+func applyFlagOverrides(cfg *config.Config) {
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "categories":
+			var categories []string
+			for _, c := range strings.Split(*categoriesFlag, ",") {
+				if c = strings.TrimSpace(c); c != "" {
+					categories = append(categories, c)
+				}
+			}
+			cfg.Polymarket.Categories = categories
+		case "sensitivity":
+			cfg.Monitor.Sensitivity = *sensitivityFlag
+		case "top-k":
+			cfg.Monitor.TopK = *topKFlag
+		case "seed":
+			cfg.Monitor.RandomSeed = *seedFlag
+		}
+	})
+}
+
+// loadConfig loads the -config files, applies the command-line overrides and
+// validates the result.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(configFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	applyFlagOverrides(cfg)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// runValidate loads and validates the configuration and prints the effective
+// settings, defaults included and secrets redacted, as JSON. With --ping it also
+// checks that the Gamma API answers. Nothing else is started.
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	ping := fs.Bool("ping", false, "Also check that polymarket.gamma_api_url is reachable")
+	_ = fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg.Settings(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if _, err := fmt.Println(string(data)); err != nil {
+		return err
+	}
+
+	if *ping {
+		if err := pingGammaAPI(cfg.Polymarket.GammaAPIURL, cfg.Polymarket.Timeout); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Gamma API reachable at %s\n", cfg.Polymarket.GammaAPIURL)
+	}
+	fmt.Fprintf(os.Stderr, "Configuration OK (%s)\n", configFiles.String())
+	return nil
+}
+
+// pingGammaAPI requests a single event from the Gamma API and fails unless it
+// answers with a 2xx status within timeout.
+func pingGammaAPI(baseURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/events?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("invalid polymarket.gamma_api_url: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("gamma API unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("gamma API returned %s", resp.Status)
+	}
+	return nil
+}
+
+// runVacuum compacts the database and logs the reclaimed space. Run it while the
+// service is stopped: a full VACUUM needs exclusive access to the database.
+func runVacuum(cfg *config.Config) error {
+	store, err := storage.New(cfg.Storage.MaxEvents, cfg.Storage.MaxSnapshotsPerEvent, cfg.Storage.DBPath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("Failed to close storage: %v", err)
+		}
+	}()
+
+	before, after, err := store.Vacuum()
+	if err != nil {
+		return err
+	}
+	logger.Info("Vacuum complete: %d → %d bytes (%d reclaimed)", before, after, before-after)
+	return nil
+}
+
+// runExport writes all markets, snapshots and changes to a JSON file for backup
+// or moving to another host (see runImport).
+func runExport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	file := fs.String("file", "", "Path of the JSON file to write (required)")
+	_ = fs.Parse(args)
+	if *file == "" {
+		return errors.New("--file is required")
+	}
+
+	store, err := storage.New(cfg.Storage.MaxEvents, cfg.Storage.MaxSnapshotsPerEvent, cfg.Storage.DBPath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("Failed to close storage: %v", err)
+		}
+	}()
+
+	dump, err := store.Export()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+	if err := os.WriteFile(*file, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *file, err)
+	}
+	logger.Info("Exported %d markets, %d snapshots, %d changes to %s",
+		len(dump.Markets), len(dump.Snapshots), len(dump.Changes), *file)
+	return nil
+}
+
+// runImport loads a file written by runExport into the configured database.
+// Invalid records and IDs already present are skipped, so importing into a
+// fresh database restores a backup and re-running an import is harmless.
+func runImport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "Path of a JSON file written by the export command (required)")
+	_ = fs.Parse(args)
+	if *file == "" {
+		return errors.New("--file is required")
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *file, err)
+	}
+	var dump storage.Dump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", *file, err)
+	}
+
+	store, err := storage.New(
+		cfg.Storage.MaxEvents,
+		cfg.Storage.MaxSnapshotsPerEvent,
+		cfg.Storage.DBPath,
+		storage.Config{ProbabilityEncoding: cfg.Storage.ProbabilityEncoding},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("Failed to close storage: %v", err)
+		}
+	}()
+
+	res, err := store.Import(&dump)
+	if err != nil {
+		return err
+	}
+	logger.Info("Import complete: markets %d imported/%d skipped, snapshots %d/%d, changes %d/%d",
+		res.MarketsImported, res.MarketsSkipped,
+		res.SnapshotsImported, res.SnapshotsSkipped,
+		res.ChangesImported, res.ChangesSkipped)
+	return nil
+}
+
+// runDumpState prints one market's persisted state (market row, recent snapshots
+// and changes) as indented JSON on stdout. It only reads the database. In-memory
+// monitor state (cooldowns, score and liquidity baselines) is not persisted and
+// is therefore not included.
+func runDumpState(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("dump-state", flag.ExitOnError)
+	marketID := fs.String("market", "", "Composite market ID, EventID:MarketID (required)")
+	limit := fs.Int("limit", 20, "Most recent snapshots and changes to include")
+	_ = fs.Parse(args)
+	if *marketID == "" {
+		return errors.New("--market is required")
+	}
+	if *limit < 1 {
+		return errors.New("--limit must be at least 1")
+	}
+
+	store, err := storage.New(cfg.Storage.MaxEvents, cfg.Storage.MaxSnapshotsPerEvent, cfg.Storage.DBPath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("Failed to close storage: %v", err)
+		}
+	}()
+
+	state, err := store.GetMarketState(*marketID, *limit)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+	_, err = fmt.Println(string(data))
+	return err
+}
+
+// runRerank rescores the most recent sent alerts under the loaded configuration
+// and prints the resulting ranking next to each alert's original score. It only
+// reads the database, so config changes can be compared against past alerts
+// without live market data.
+func runRerank(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("rerank", flag.ExitOnError)
+	limit := fs.Int("limit", 200, "Most recent sent alerts to rescore")
+	_ = fs.Parse(args)
+	if *limit < 1 {
+		return errors.New("--limit must be at least 1")
+	}
+
+	store, err := storage.New(cfg.Storage.MaxEvents, cfg.Storage.MaxSnapshotsPerEvent, cfg.Storage.DBPath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("Failed to close storage: %v", err)
+		}
+	}()
+
+	alerts, err := store.GetAlerts(*limit)
+	if err != nil {
+		return err
+	}
+	original := make(map[string]float64, len(alerts))
+	for _, a := range alerts {
+		original[a.ID] = a.SignalScore
+	}
+	// Alerts don't record their category; the markets table does, and
+	// category_score overrides are keyed by it
+	markets, err := store.GetAllMarkets()
+	if err != nil {
+		return err
+	}
+	categories := make(map[string]string, len(markets))
+	for _, market := range markets {
+		categories[market.ID] = market.Category
+	}
+
+	monCfg, err := newMonitorConfig(cfg)
+	if err != nil {
+		return err
+	}
+	minScore := cfg.Monitor.MinCompositeScore()
+	mon := monitor.New(store, monCfg)
+	groups := mon.Rerank(alerts, categories, minScore, cfg.Monitor.TopK, cfg.Polymarket.Volume24hrMin, cfg.Monitor.MinAbsChange, cfg.Monitor.MinBaseProb)
+
+	fmt.Printf("Rescored %d stored alerts (min_score=%.4f, top_k=%d): %d event groups pass\n",
+		len(alerts), minScore, cfg.Monitor.TopK, len(groups))
+	for i, g := range groups {
+		fmt.Printf("\n%d. %s (best %.4f)\n", i+1, g.Title, g.BestScore)
+		for _, c := range g.Markets {
+			fmt.Printf("   %.4f (was %.4f)  %.1f%% → %.1f%%  %s  [%s]\n",
+				c.SignalScore, original[c.ID], c.OldProbability*100, c.NewProbability*100,
+				c.MarketQuestion, c.DetectedAt.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// replayClock is the monitor clock during a backtest: it reads the simulated
+// poll time instead of the system clock.
+type replayClock struct{ now time.Time }
+
+func (c *replayClock) Now() time.Time { return c.now }
+
+// backtestVariant is one configuration replayed by runBacktest.
+type backtestVariant struct {
+	name string
+	cfg  *config.Config
+}
+
+// backtestResult summarizes the alerts one configuration would have sent.
+type backtestResult struct {
+	name   string
+	cycles int
+	groups int
+	scores []float64 // signal score of every alerted market
+}
+
+// runBacktest replays the stored snapshots of the last --since through the
+// monitor at --interval polls and prints how many alerts the loaded
+// configuration would have sent, with their score distribution. Each extra
+// argument is a config file overlaid on the loaded one and replayed as its own
+// variant, so threshold changes can be compared on the same history. Every
+// variant runs against a scratch database with fresh monitor state; the real
+// database is only read, and nothing is sent.
+func runBacktest(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ExitOnError)
+	since := fs.Duration("since", 7*24*time.Hour, "Replay snapshots taken within this long before now")
+	interval := fs.Duration("interval", cfg.Polymarket.PollInterval, "Simulated poll interval")
+	_ = fs.Parse(args)
+	if *since <= 0 || *interval <= 0 {
+		return errors.New("--since and --interval must be positive")
+	}
+
+	store, err := storage.New(cfg.Storage.MaxEvents, cfg.Storage.MaxSnapshotsPerEvent, cfg.Storage.DBPath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("Failed to close storage: %v", err)
+		}
+	}()
+
+	markets, err := store.GetAllMarkets()
+	if err != nil {
+		return err
+	}
+	start := time.Now().Add(-*since)
+	var snapshots []models.Snapshot
+	for _, m := range markets {
+		snaps, err := store.GetSnapshotsSince(m.ID, start)
+		if err != nil {
+			return err
+		}
+		snapshots = append(snapshots, snaps...)
+	}
+	if len(snapshots) == 0 {
+		fmt.Printf("No snapshots stored in the last %v\n", *since)
+		return nil
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Timestamp.Before(snapshots[j].Timestamp) })
+
+	variants := []backtestVariant{{"base", cfg}}
+	for _, overlay := range fs.Args() {
+		vcfg, err := config.Load(append(append([]string{}, configFiles...), overlay)...)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", overlay, err)
+		}
+		applyFlagOverrides(vcfg)
+		if err := vcfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration %s: %w", overlay, err)
+		}
+		variants = append(variants, backtestVariant{overlay, vcfg})
+	}
+
+	first, last := snapshots[0].Timestamp, snapshots[len(snapshots)-1].Timestamp
+	fmt.Printf("Replaying %d snapshots of %d markets from %s to %s every %v\n",
+		len(snapshots), len(markets), first.Format(time.RFC3339), last.Format(time.RFC3339), *interval)
+	for _, v := range variants {
+		res, err := replaySnapshots(v.cfg, markets, snapshots, *interval)
+		if err != nil {
+			return fmt.Errorf("%s: %w", v.name, err)
+		}
+		res.name = v.name
+		printBacktestResult(res)
+	}
+	return nil
+}
+
+// replaySnapshots runs the monitoring pipeline of cfg over snapshots (sorted by
+// time) at every interval, against a scratch database in a temporary
+// directory.
+func replaySnapshots(cfg *config.Config, markets []*models.Market, snapshots []models.Snapshot, interval time.Duration) (backtestResult, error) {
+	var res backtestResult
+	dir, err := os.MkdirTemp("", "polyoracle-backtest-")
+	if err != nil {
+		return res, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Same encoding as the live database, so replayed prices round as they would live
+	scratch, err := storage.New(
+		max(cfg.Storage.MaxEvents, len(markets)),
+		cfg.Storage.MaxSnapshotsPerEvent,
+		dir+"/backtest.db",
+		storage.Config{ProbabilityEncoding: cfg.Storage.ProbabilityEncoding},
+	)
+	if err != nil {
+		return res, fmt.Errorf("failed to open scratch storage: %w", err)
+	}
+	defer scratch.Close() //nolint:errcheck
+	byID := make(map[string]*models.Market, len(markets))
+	for _, m := range markets {
+		replay := *m
+		if err := scratch.AddMarket(&replay); err != nil {
+			return res, err
+		}
+		byID[m.ID] = &replay
+	}
+
+	monCfg, err := newMonitorConfig(cfg)
+	if err != nil {
+		return res, err
+	}
+	clock := &replayClock{}
+	monCfg.Clock = clock
+	mon := monitor.New(scratch, monCfg)
+	minScore := cfg.Monitor.MinCompositeScore()
+	window := time.Duration(cfg.Monitor.DetectionIntervals+1) * interval
+
+	seen := make(map[string]*models.Market)
+	next := 0
+	for tick := snapshots[0].Timestamp; next < len(snapshots); tick = tick.Add(interval) {
+		for ; next < len(snapshots) && !snapshots[next].Timestamp.After(tick); next++ {
+			snap := snapshots[next]
+			if err := scratch.AddSnapshot(&snap); err != nil {
+				return res, err
+			}
+			// Markets carry the volume and liquidity of their latest snapshot, as
+			// they would have when polled
+			m := byID[snap.EventID]
+			m.YesProbability, m.NoProbability = snap.YesProbability, snap.NoProbability
+			if snap.Volume24hr > 0 {
+				m.Volume24hr = snap.Volume24hr
+			}
+			if snap.Liquidity > 0 {
+				m.Liquidity = snap.Liquidity
+			}
+			seen[m.ID] = m
+		}
+		clock.now = tick
+		res.cycles++
+
+		polled := make([]*models.Market, 0, len(seen))
+		for _, m := range seen {
+			polled = append(polled, m)
+		}
+		changes, _, err := mon.DetectChanges(convertMarkets(polled), window)
+		if err != nil {
+			return res, err
+		}
+		groups := mon.ScoreAndRank(changes, buildMarketsMap(polled), minScore, cfg.Monitor.TopK,
+			cfg.Polymarket.Volume24hrMin, cfg.Monitor.MinAbsChange, cfg.Monitor.MinBaseProb)
+		groups = mon.FilterAlertBudget(mon.FilterRecentlySent(groups, window))
+		mon.RecordNotified(groups)
+
+		res.groups += len(groups)
+		for _, g := range groups {
+			for _, c := range g.Markets {
+				res.scores = append(res.scores, c.SignalScore)
+			}
+		}
+		if err := scratch.RotateSnapshots(); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func printBacktestResult(res backtestResult) {
+	fmt.Printf("\n%s: %d cycles, %d alert groups, %d markets alerted\n", res.name, res.cycles, res.groups, len(res.scores))
+	if len(res.scores) == 0 {
+		return
+	}
+	sort.Float64s(res.scores)
+	quantile := func(q float64) float64 { return res.scores[int(q*float64(len(res.scores)-1))] }
+	fmt.Printf("   score min %.4f  p50 %.4f  p90 %.4f  max %.4f\n",
+		res.scores[0], quantile(0.5), quantile(0.9), res.scores[len(res.scores)-1])
+}
+
+// runCalibration prints the per-category Brier scores recorded by
+// monitor.calibration, as a table or (--json) as JSON. It only reads the
+// database.
+func runCalibration(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("calibration", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the summaries as JSON")
+	_ = fs.Parse(args)
+
+	store, err := storage.New(cfg.Storage.MaxEvents, cfg.Storage.MaxSnapshotsPerEvent, cfg.Storage.DBPath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			logger.Error("Failed to close storage: %v", err)
+		}
+	}()
+
+	summaries, err := store.GetCalibration()
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		data, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode calibration: %w", err)
+		}
+		_, err = fmt.Println(string(data))
+		return err
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("No resolved markets recorded (enable monitor.calibration and polymarket.include_closed)")
+		return nil
+	}
+	fmt.Printf("%-20s %8s %8s %10s %10s\n", "CATEGORY", "MARKETS", "BRIER", "PREDICTED", "RESOLVED")
+	var total int
+	var brier float64
+	for _, s := range summaries {
+		fmt.Printf("%-20s %8d %8.4f %9.1f%% %9.1f%%\n", s.Category, s.Markets, s.Brier, s.MeanPredicted*100, s.ResolvedYes*100)
+		total += s.Markets
+		brier += s.Brier * float64(s.Markets)
+	}
+	fmt.Printf("%-20s %8d %8.4f\n", "all", total, brier/float64(total))
+	return nil
+}
+
+// newMonitorConfig maps the monitor section of the configuration onto
+// monitor.Config. It fails if monitor.score_expr or a maintenance window
+// doesn't parse.
+func newMonitorConfig(cfg *config.Config) (monitor.Config, error) {
+	scoreExpr, err := monitor.ParseScoreExpr(cfg.Monitor.ScoreExpr)
+	if err != nil {
+		return monitor.Config{}, fmt.Errorf("monitor.score_expr: %w", err)
+	}
+	windows, err := monitor.ParseMaintenanceWindows(cfg.Monitor.MaintenanceWindows, cfg.Monitor.MaintenanceTimezone)
+	if err != nil {
+		return monitor.Config{}, fmt.Errorf("monitor.maintenance_windows: %w", err)
+	}
+	return monitor.Config{
+		CoverageDropFraction:  cfg.Monitor.CoverageDropFraction,
+		CoverageWindow:        cfg.Monitor.CoverageWindow,
+		AdaptiveThreshold:     cfg.Monitor.AdaptiveThreshold,
+		AdaptiveAlpha:         cfg.Monitor.AdaptiveAlpha,
+		LiquidityDropFraction: cfg.Monitor.LiquidityDropFraction,
+		PerMarketLiquidity:    cfg.Polymarket.OrderBookDepthBand > 0,
+		AlertOnUncertainty:    cfg.Monitor.AlertOnUncertainty,
+		UncertaintyThreshold:  cfg.Monitor.UncertaintyThreshold,
+		TCClip:                cfg.Monitor.TCClip,
+
+		MissingCyclesBeforeCleanup: cfg.Monitor.MissingCyclesBeforeCleanup,
+		MaxStates:                  cfg.Monitor.MaxStates,
+		MinSnapshotsForSigma:       cfg.Monitor.MinSnapshotsForSigma,
+		RecentSigmaWeight:          cfg.Monitor.RecentSigmaWeight,
+		RecentSigmaSnapshots:       cfg.Monitor.RecentSigmaSnapshots,
+		MinProbability:             cfg.Monitor.MinProbability,
+		MaxProbability:             cfg.Monitor.MaxProbability,
+		TopKTiebreak:               cfg.Monitor.TopKTiebreak,
+		DivergenceMetric:           cfg.Monitor.DivergenceMetric,
+		ResolutionPendingCycles:    cfg.Monitor.ResolutionPendingCycles,
+		DetectLadderInconsistency:  cfg.Monitor.DetectLadderInconsistency,
+		LadderTolerance:            cfg.Monitor.LadderTolerance,
+		MaxAlertsPerMarketPerDay:   cfg.Monitor.MaxAlertsPerMarketPerDay,
+		EventCooldownMultiplier:    cfg.Monitor.EventCooldownMultiplier,
+		AlertBudgetResetHour:       cfg.Monitor.AlertBudgetResetHour,
+		GroupMinBestScore:          cfg.Monitor.GroupMinBestScore,
+		ScoreCeiling:               cfg.Monitor.ScoreCeiling,
+		AlertAboveCeiling:          cfg.Monitor.AlertAboveCeiling,
+		CompactState:               cfg.Monitor.CompactState,
+		ScoreExpr:                  scoreExpr,
+		Weights:                    scoreWeights(cfg.Monitor.Weights),
+		DedupSimilarTitles:         cfg.Monitor.DedupSimilarTitles,
+		TitleSimilarity:            cfg.Monitor.TitleSimilarity,
+		MaintenanceWindows:         windows,
+		FreezeStateInMaintenance:   cfg.Monitor.FreezeStateInMaintenance,
+		TrackSide:                  cfg.Monitor.TrackSide,
+		ScoreTrackedSide:           cfg.Monitor.ScoreTrackedSide,
+		CategoryScore:              categoryScore(cfg.Monitor.CategoryScore),
+	}, nil
+}
+
+// scoreWeights maps monitor.weights onto monitor.ScoreWeights, or nil when
+// every exponent is 1 so scoring stays the plain product.
+func scoreWeights(w config.ScoreWeights) *monitor.ScoreWeights {
+	if w.KLExp == 1 && w.VolumeExp == 1 && w.SNRExp == 1 && w.TCExp == 1 {
+		return nil
+	}
+	return &monitor.ScoreWeights{KL: w.KLExp, Volume: w.VolumeExp, SNR: w.SNRExp, TC: w.TCExp}
+}
+
+// categoryScore converts monitor.category_score for the monitor.
+func categoryScore(overrides map[string]config.ScoreBounds) map[string]monitor.ScoreBounds {
+	if len(overrides) == 0 {
+		return nil
+	}
+	out := make(map[string]monitor.ScoreBounds, len(overrides))
+	for category, b := range overrides {
+		out[strings.ToLower(category)] = monitor.ScoreBounds{MinScore: b.MinScore, ScoreCeiling: b.ScoreCeiling}
+	}
+	return out
+}
+
+// categoryVolumeMin converts monitor.category_volume_min for the Polymarket client.
+func categoryVolumeMin(overrides map[string]config.VolumeThresholds) map[string]polymarket.VolumeThresholds {
+	if len(overrides) == 0 {
+		return nil
+	}
+	out := make(map[string]polymarket.VolumeThresholds, len(overrides))
+	for category, th := range overrides {
+		out[category] = polymarket.VolumeThresholds{
+			Volume24hrMin: th.Volume24hrMin,
+			Volume1wkMin:  th.Volume1wkMin,
+			Volume1moMin:  th.Volume1moMin,
+		}
+	}
+	return out
+}
+
+// newTelegramClient builds the Telegram client from configuration; store answers /history.
+// tgbotapi validates the token with a network call, so this can fail transiently.
+func newTelegramClient(cfg *config.Config, store storage.Store) (*telegram.Client, error) {
+	sendInterval := cfg.Telegram.SendInterval
+	if sendInterval == 0 {
+		sendInterval = -1 // configured 0 = no spacing; the client treats 0 as "default"
+	}
+	return telegram.NewClient(
+		cfg.Telegram.BotToken,
+		cfg.Telegram.ChatID,
+		cfg.Telegram.MaxRetries,
+		cfg.Telegram.RetryDelayBase,
+		telegram.ClientConfig{
+			MaxMarketsPerGroup: cfg.Telegram.MaxMarketsPerGroup,
+			DeltaStyle:         cfg.Telegram.DeltaStyle,
+			ShowMarketID:       cfg.Telegram.ShowMarketID,
+			ShowLiquidity:      cfg.Telegram.ShowLiquidity,
+			ShowSparkline:      cfg.Telegram.ShowSparkline,
+			ShowMargin:         cfg.Telegram.ShowThresholdMultiple,
+			ShowLastAlert:      cfg.Telegram.ShowLastAlert,
+			RetryPermanent:     cfg.Telegram.RetryPermanentErrors,
+			PlainTextFallback:  cfg.Telegram.PlainTextFallback,
+			TestChatID:         cfg.Telegram.TestChatID,
+			ShowDescription:    cfg.Telegram.ShowDescription,
+			DescriptionLength:  cfg.Telegram.DescriptionLength,
+			CategoryEmoji:      cfg.Telegram.CategoryEmoji,
+			SendConcurrency:    cfg.Telegram.SendConcurrency,
+			SendInterval:       sendInterval,
+			AdminUserIDs:       cfg.Telegram.AdminUserIDs,
+			PublicCommands:     cfg.Telegram.PublicCommands,
+			History:            store,
+		},
+	)
+}
+
+// adaptiveInterval tracks the effective poll interval. When enabled it doubles
+// after every idleCycles consecutive alert-free cycles, up to max, and resets to
+// base on the first alert. It never drops below base, so the 1-minute poll floor
+// enforced by config validation still holds.
+type adaptiveInterval struct {
+	enabled    bool
+	base, max  time.Duration
+	idleCycles int
+	current    time.Duration
+	idle       int // consecutive alert-free cycles at the current interval
+}
+
+func newAdaptiveInterval(cfg config.PolymarketConfig) *adaptiveInterval {
+	return &adaptiveInterval{
+		enabled:    cfg.AdaptiveInterval,
+		base:       cfg.PollInterval,
+		max:        cfg.AdaptiveMaxInterval,
+		idleCycles: cfg.AdaptiveIdleCycles,
+		current:    cfg.PollInterval,
+	}
+}
+
+// observe records a completed cycle's alert count and returns the interval for
+// the next cycle, reporting whether it changed.
+func (a *adaptiveInterval) observe(alerts int) (time.Duration, bool) {
+	if !a.enabled {
+		return a.current, false
+	}
+	if alerts > 0 {
+		a.idle = 0
+		if a.current == a.base {
+			return a.current, false
+		}
+		a.current = a.base
+		return a.current, true
+	}
+	a.idle++
+	if a.idle < a.idleCycles || a.current >= a.max {
+		return a.current, false
+	}
+	a.idle = 0
+	a.current = min(2*a.current, a.max)
+	return a.current, true
+}
+
+// errorNotices decides when cycle failures and recoveries are announced. An
+// error is announced on the first failure of a run and a recovery on the first
+// healthy cycle after it. With a cooldown, no notice follows another within
+// the window: a failure run starting too soon after the last notice is only
+// announced if it outlasts the window, and a recovery too soon after its error
+// waits until the window passes (and is dropped if the upstream fails again
+// first). A flapping upstream thus yields one error/recovery pair.
+type errorNotices struct {
+	cooldown time.Duration
+	open     bool      // an error was announced and its recovery hasn't been
+	failing  bool      // the most recent cycle failed
+	failures int       // failed cycles in the current run, or since the open error
+	lastAt   time.Time // time of the last announcement
+}
+
+// failure records a failed cycle and reports whether to announce an error.
+func (n *errorNotices) failure(now time.Time) bool {
+	if !n.failing && !n.open {
+		n.failures = 0
+	}
+	n.failing = true
+	n.failures++
+	if n.open || now.Sub(n.lastAt) < n.cooldown {
+		return false
+	}
+	n.open = true
+	n.lastAt = now
+	return true
+}
+
+// success records a healthy cycle and reports whether to announce a recovery,
+// with the number of failed cycles it covers.
+func (n *errorNotices) success(now time.Time) (int, bool) {
+	n.failing = false
+	if !n.open || now.Sub(n.lastAt) < n.cooldown {
+		return 0, false
+	}
+	n.open = false
+	n.lastAt = now
+	return n.failures, true
+}
+
+// startMetricsServer serves the Prometheus metrics endpoint, and /readyz from
+// ready, on addr until ctx is cancelled.
+func startMetricsServer(ctx context.Context, addr string, ready *readiness) {
+	serveHTTP(ctx, "Metrics", addr, map[string]http.Handler{
+		"/metrics": metrics.Default.Handler(),
+		"/readyz":  ready,
+	})
+}
+
+// startStreamServer serves the live alert stream on addr until ctx is cancelled.
+func startStreamServer(ctx context.Context, addr string, broker *stream.Broker) {
+	serveHTTP(ctx, "Alert stream", addr, map[string]http.Handler{"/alerts/stream": broker})
+}
+
+// serveHTTP serves each route's handler at its path on addr until ctx is
+// cancelled. Request contexts derive from ctx, so long-lived streaming
+// responses end on shutdown.
+func serveHTTP(ctx context.Context, name, addr string, routes map[string]http.Handler) {
+	mux := http.NewServeMux()
+	paths := make([]string, 0, len(routes))
+	for path, handler := range routes {
+		mux.Handle(path, handler)
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	go func() {
+		logger.Info("%s endpoint listening on %s (%s)", name, addr, strings.Join(paths, ", "))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("%s server failed: %v", name, err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Failed to shut down %s server: %v", strings.ToLower(name), err)
+		}
+	}()
+}
+
+func generateID() string {
+	return fmt.Sprintf("%d", time.Now().UnixNano())
+}
+
+// attachTrends sets each alerting change's Trend to the market's last points
+// stored Yes probabilities, oldest first, for the notification sparkline.
+func attachTrends(store storage.Store, groups []models.Event, points int) {
+	for gi := range groups {
+		for ci := range groups[gi].Markets {
+			change := &groups[gi].Markets[ci]
+			snapshots, err := store.GetSnapshots(change.EventID)
+			if err != nil {
+				logger.Warn("Failed to load trend for %s: %v", change.EventID, err)
+				continue
+			}
+			if len(snapshots) > points {
+				snapshots = snapshots[len(snapshots)-points:]
+			}
+			change.Trend = make([]float64, len(snapshots))
+			for i, s := range snapshots {
+				change.Trend[i] = s.YesProbability
+			}
+		}
+	}
+}
+
+// attachLastAlerts fills each change's LastAlertAt from the stored alert history.
+// Must run before the groups are recorded as alerts themselves.
+func attachLastAlerts(store storage.Store, groups []models.Event) {
+	for gi := range groups {
+		for ci := range groups[gi].Markets {
+			change := &groups[gi].Markets[ci]
+			alerts, err := store.GetAlertsForMarket(change.EventID, 1)
+			if err != nil {
+				logger.Warn("Failed to load last alert for %s: %v", change.EventID, err)
+				continue
+			}
+			if len(alerts) > 0 {
+				change.LastAlertAt = alerts[0].DetectedAt
+			}
+		}
+	}
+}
+
+// recordCalibration stores a just-closed market's Yes probability lead before
+// resolution against its outcome. Markets that closed without a clear Yes or
+// No, or whose snapshots don't reach back far enough, are skipped.
+func recordCalibration(store storage.Store, market models.Market, resolvedAt time.Time, lead time.Duration) {
+	outcome, ok := monitor.ResolvedOutcome(market)
+	if !ok {
+		logger.Debug("Market %s closed at %.3f; not scored for calibration", market.ID, market.YesProbability)
+		return
+	}
+	snapshot, err := store.GetSnapshotBefore(market.ID, resolvedAt.Add(-lead))
+	if err != nil {
+		logger.Warn("Failed to load calibration snapshot for %s: %v", market.ID, err)
+		return
+	}
+	if snapshot == nil {
+		logger.Debug("Market %s resolved with no snapshot %v before; not scored for calibration", market.ID, lead)
+		return
+	}
+	added, err := store.AddCalibration(models.CalibrationRecord{
+		MarketID:    market.ID,
+		Category:    market.Category,
+		Predicted:   snapshot.YesProbability,
+		Outcome:     outcome,
+		LeadTime:    lead,
+		PredictedAt: snapshot.Timestamp,
+		ResolvedAt:  resolvedAt,
+	})
+	if err != nil {
+		logger.Warn("Failed to record calibration: %v", err)
+		return
+	}
+	if added {
+		result := "No"
+		if outcome == 1 {
+			result = "Yes"
+		}
+		logger.Info("Calibration: market %s resolved %s, priced %.1f%% Yes %v before",
+			market.ID, result, snapshot.YesProbability*100, lead)
+	}
 }
 
 func convertMarkets(markets []*models.Market) []models.Market {
@@ -9,6 +9,9 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -21,6 +24,10 @@ type Config struct {
 	Telegram   TelegramConfig   `mapstructure:"telegram"`
 	Storage    StorageConfig    `mapstructure:"storage"`
 	Logging    LoggingConfig    `mapstructure:"logging"`
+	Metrics    MetricsConfig    `mapstructure:"metrics"`
+	OTel       OTelConfig       `mapstructure:"otel"`
+	Stream     StreamConfig     `mapstructure:"stream"`
+	Webhook    WebhookConfig    `mapstructure:"webhook"`
 }
 
 // PolymarketConfig holds Polymarket API configuration
@@ -40,6 +47,49 @@ type PolymarketConfig struct {
 	MaxIdleConns        int           `mapstructure:"max_idle_conns"`
 	MaxIdleConnsPerHost int           `mapstructure:"max_idle_conns_per_host"`
 	IdleConnTimeout     time.Duration `mapstructure:"idle_conn_timeout"`
+	SchemaDriftFraction float64       `mapstructure:"schema_drift_fraction"` // fraction of zero-volume, tag-less events that flags API drift; 0 = off
+	SchemaDriftNotify   bool          `mapstructure:"schema_drift_notify"`   // send a one-time Telegram warning when drift is detected
+	RetryOnEmpty        bool          `mapstructure:"retry_on_empty"`        // retry an empty first page once if the last fetch had markets
+	EventURLTemplate    string        `mapstructure:"event_url_template"`    // notification link; {slug} and {marketID} placeholders
+	PageSize            int           `mapstructure:"page_size"`             // events per API request (1–500)
+	FetchConcurrency    int           `mapstructure:"fetch_concurrency"`     // event pages requested at once
+	PerCategoryFetch    bool          `mapstructure:"per_category_fetch"`    // query each category separately for fair representation
+	// AdaptiveInterval doubles the poll interval (up to AdaptiveMaxInterval) after
+	// every AdaptiveIdleCycles consecutive cycles without alerts, and snaps back to
+	// PollInterval as soon as one fires.
+	AdaptiveInterval    bool          `mapstructure:"adaptive_interval"`
+	AdaptiveMaxInterval time.Duration `mapstructure:"adaptive_max_interval"`
+	AdaptiveIdleCycles  int           `mapstructure:"adaptive_idle_cycles"`
+	// MinEffectiveInterval is a hard floor between cycle starts, whatever
+	// scheduled them; a cycle due sooner is deferred until it has passed.
+	MinEffectiveInterval time.Duration `mapstructure:"min_effective_interval"`
+
+	// IncludeClosed also fetches closed markets (with their final prices) for
+	// retrospective analysis. They are recorded but never alerted on.
+	IncludeClosed bool `mapstructure:"include_closed"`
+
+	// DefaultCategory is the category given to markets whose event has no tags,
+	// which would otherwise be dropped for lacking one.
+	DefaultCategory string `mapstructure:"default_category"`
+
+	// SingleMarketEventVolume uses the event's volumes directly for the market of
+	// a single-market event, skipping the proportional 24h estimate.
+	SingleMarketEventVolume bool `mapstructure:"single_market_event_volume"`
+
+	// ResidualOutcomeMode handles markets listing outcomes besides Yes and No
+	// (e.g. a small "Other"): "distribute" rescales Yes/No to sum to 1,
+	// "ignore" keeps the raw prices.
+	ResidualOutcomeMode string `mapstructure:"residual_outcome_mode"`
+	// PositionalOutcomeFallback reads two-price markets whose outcomes aren't
+	// labeled Yes/No as Yes then No rather than skipping them as 0/0.
+	PositionalOutcomeFallback bool `mapstructure:"positional_outcome_fallback"`
+	// MultiOutcomeMarkets tracks each outcome of categorical markets (more than
+	// two outcomes, none labeled Yes/No) as its own series instead of skipping them.
+	MultiOutcomeMarkets bool `mapstructure:"multi_outcome_markets"`
+	// OrderBookDepthBand, when positive, replaces the Gamma liquidity figure with
+	// the dollar depth of each market's CLOB order book within ± this band of the
+	// midpoint (e.g. 0.02 = two cents). One extra request per market per cycle.
+	OrderBookDepthBand float64 `mapstructure:"order_book_depth_band"`
 }
 
 // MonitorConfig holds monitoring behavior configuration
@@ -50,6 +100,167 @@ type MonitorConfig struct {
 	DetectionIntervals int     `mapstructure:"detection_intervals"`
 	MinAbsChange       float64 `mapstructure:"min_abs_change"` // minimum absolute probability change (fraction, e.g. 0.03 = 3pp)
 	MinBaseProb        float64 `mapstructure:"min_base_prob"`  // minimum base probability (fraction, e.g. 0.05 = 5%)
+	// CoverageDropFraction warns when a cycle processes fewer markets than this
+	// fraction of the rolling average over CoverageWindow cycles. 0 = off.
+	CoverageDropFraction float64 `mapstructure:"coverage_drop_fraction"`
+	CoverageWindow       int     `mapstructure:"coverage_window"`
+	// AdaptiveThreshold raises each market's bar to the running p90 of its own
+	// scores (EWMA with weight AdaptiveAlpha); the sensitivity floor still applies.
+	AdaptiveThreshold bool    `mapstructure:"adaptive_threshold"`
+	AdaptiveAlpha     float64 `mapstructure:"adaptive_alpha"`
+	// NotifyNewMarkets announces first-seen markets whose 24hr volume is at least NewMarketVolumeMin.
+	NotifyNewMarkets   bool    `mapstructure:"notify_new_markets"`
+	NewMarketVolumeMin float64 `mapstructure:"new_market_volume_min"`
+	// LiquidityDropFraction alerts when an event's liquidity falls more than this
+	// fraction below its EWMA baseline. 0 disables liquidity-drop alerts.
+	LiquidityDropFraction float64 `mapstructure:"liquidity_drop_fraction"`
+	// AlertOnUncertainty alerts on markets converging toward 50% (e.g. 80%→55%),
+	// when 0.5-|p-0.5| rises by at least UncertaintyThreshold within the window.
+	AlertOnUncertainty   bool    `mapstructure:"alert_on_uncertainty"`
+	UncertaintyThreshold float64 `mapstructure:"uncertainty_threshold"`
+	// TCClip caps each snapshot-to-snapshot move's magnitude in trajectory
+	// consistency, so one outlier poll can't dominate the window. 0 = unclamped.
+	TCClip float64 `mapstructure:"tc_clip"`
+	// MissingCyclesBeforeCleanup drops in-memory state (cooldown records, score
+	// and liquidity baselines) for markets absent from this many consecutive
+	// fetches. 0 = never.
+	MissingCyclesBeforeCleanup int `mapstructure:"missing_cycles_before_cleanup"`
+	// MaxStates caps how many markets keep in-memory state, evicting the least
+	// recently updated past it. A safety bound for high-churn, long-running
+	// instances. 0 = unlimited.
+	MaxStates int `mapstructure:"max_states"`
+	// MinSnapshotsForSigma blends the SNR volatility estimate toward a conservative
+	// default (0.01) for markets with fewer snapshots than this. 0 = off.
+	MinSnapshotsForSigma int `mapstructure:"min_snapshots_for_sigma"`
+	// RecentSigmaWeight blends the all-history SNR volatility with that of the
+	// last RecentSigmaSnapshots snapshots, weighting the recent estimate by this
+	// (0–1), so the noise floor adapts to regime changes. 0 = all history only.
+	RecentSigmaWeight    float64 `mapstructure:"recent_sigma_weight"`
+	RecentSigmaSnapshots int     `mapstructure:"recent_sigma_snapshots"`
+	// MinProbability / MaxProbability restrict alerting to markets whose old and
+	// new probabilities both lie in this band. Defaults 0/1 = no restriction.
+	MinProbability float64 `mapstructure:"min_probability"`
+	MaxProbability float64 `mapstructure:"max_probability"`
+	// TopKTiebreak orders alert groups whose scores agree to 3 significant digits:
+	// "score_only" (exact score, then event ID), "recency" or "volume".
+	TopKTiebreak string `mapstructure:"topk_tiebreak"`
+	// DivergenceMetric measures each move for the composite score: "kl",
+	// "js" (Jensen–Shannon) or "hellinger".
+	DivergenceMetric string `mapstructure:"divergence_metric"`
+	// RandomSeed seeds every randomized component (the Polymarket retry
+	// jitter), so a run can be reproduced. 0 = seeded from the current time.
+	RandomSeed int64 `mapstructure:"random_seed"`
+	// CycleSummaryOutput receives one versioned JSON line per monitoring cycle
+	// (models.CycleSummary): "stdout", "stderr" or a file path to append to.
+	// Empty = off.
+	CycleSummaryOutput string `mapstructure:"cycle_summary_output"`
+	// ShutdownTimeout is how long a shutdown signal waits for an in-flight
+	// cycle to finish before aborting it.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// PriceSmoothingAlpha smooths each polled probability with an EWMA before it
+	// is stored and compared (weight of the new quote). 1 = raw quotes.
+	PriceSmoothingAlpha float64 `mapstructure:"price_smoothing_alpha"`
+	// ResolutionPendingCycles excludes a market priced at 0 or 1 (within 0.5pp)
+	// for more than this many consecutive polls from detection, treating it as
+	// awaiting resolution. 0 = off.
+	ResolutionPendingCycles int `mapstructure:"resolution_pending_cycles"`
+	// ResolutionNotify sends a one-time "resolving" note when a market is excluded.
+	ResolutionNotify bool `mapstructure:"resolution_notify"`
+	// NotifyResolved alerts when a tracked market closes, with its final
+	// price. Requires polymarket.include_closed so closed markets are fetched.
+	NotifyResolved bool `mapstructure:"notify_resolved"`
+	// Calibration records, for each market that closes while tracked, its Yes
+	// probability CalibrationLeadTime before resolution against the outcome,
+	// for per-category Brier scores (the calibration command). Needs
+	// polymarket.include_closed so resolutions are seen.
+	Calibration         bool          `mapstructure:"calibration"`
+	CalibrationLeadTime time.Duration `mapstructure:"calibration_lead_time"`
+	// FutureTimestampTolerance is how far ahead of the local clock a stored or
+	// imported timestamp may be before validation rejects it, so minor clock skew
+	// between hosts doesn't break ingestion or cooldowns.
+	FutureTimestampTolerance time.Duration `mapstructure:"future_timestamp_tolerance"`
+	// DetectLadderInconsistency flags markets of a threshold ladder ("above $100k",
+	// "above $150k", …) priced more than LadderTolerance above a rung they imply.
+	DetectLadderInconsistency bool    `mapstructure:"detect_ladder_inconsistency"`
+	LadderTolerance           float64 `mapstructure:"ladder_tolerance"`
+	// MaxAlertsPerMarketPerDay caps notifications per market per daily window;
+	// further alerts are still stored but not sent. 0 = unlimited.
+	MaxAlertsPerMarketPerDay int `mapstructure:"max_alerts_per_market_per_day"`
+	// AlertBudgetResetHour is the UTC hour (0–23) at which the daily window starts.
+	AlertBudgetResetHour int `mapstructure:"alert_budget_reset_hour"`
+	// EventCooldownMultiplier suppresses every market of an event moving in a
+	// direction already notified for that event, for this many per-market
+	// cooldowns. 0 = off. RestoreEventCooldown reloads it from alert history
+	// at startup.
+	EventCooldownMultiplier float64 `mapstructure:"event_cooldown_multiplier"`
+	RestoreEventCooldown    bool    `mapstructure:"restore_event_cooldown"`
+	// GroupMinBestScore only notifies an event group whose best market score
+	// reaches this bar, on top of the per-market threshold. 0 = off.
+	GroupMinBestScore float64 `mapstructure:"group_min_best_score"`
+	// ScoreCeiling treats composite scores at or above it as extreme outliers:
+	// they never feed the adaptive-threshold baseline, and alert (tagged
+	// "extreme") only when AlertAboveCeiling is set. 0 = no ceiling.
+	ScoreCeiling      float64 `mapstructure:"score_ceiling"`
+	AlertAboveCeiling bool    `mapstructure:"alert_above_ceiling"`
+	// CompactState stores per-market score and liquidity baselines in float32
+	// to save memory when tracking very many markets.
+	CompactState bool `mapstructure:"compact_state"`
+	// ScoreExpr is an optional formula replacing the built-in composite score,
+	// over score, kl, volume_weight, snr, tc, sigma, magnitude, volume24hr and
+	// liquidity. It is parsed at startup. Empty = built-in formula.
+	ScoreExpr string `mapstructure:"score_expr"`
+	// Weights are exponents on the built-in composite score factors
+	// (kl × volume_weight × snr × tc). All 1 = the plain product.
+	Weights ScoreWeights `mapstructure:"weights"`
+	// DedupSimilarTitles collapses alert groups of different events whose
+	// titles are near-identical (token Jaccard similarity ≥ TitleSimilarity)
+	// into the strongest one, noted "+N related".
+	DedupSimilarTitles bool    `mapstructure:"dedup_similar_titles"`
+	TitleSimilarity    float64 `mapstructure:"title_similarity"`
+	// MaintenanceWindows lists spans ("02:00-04:00", "sun 22:00-02:00" or an
+	// RFC 3339 "start/end" range) during which detection runs but alerts are
+	// not sent. Recurring spans use MaintenanceTimezone. Parsed at startup.
+	MaintenanceWindows  []string `mapstructure:"maintenance_windows"`
+	MaintenanceTimezone string   `mapstructure:"maintenance_timezone"`
+	// FreezeStateInMaintenance stops score, liquidity and coverage baselines
+	// from learning during a maintenance window.
+	FreezeStateInMaintenance bool `mapstructure:"freeze_state_in_maintenance"`
+	// TrackSide maps a market ID or category to "yes" or "no", the outcome its
+	// alerts are framed from, so "Will X NOT happen" style markets read
+	// naturally. ScoreTrackedSide applies the side to the probability
+	// prefilters as well (min_base_prob, min/max_probability).
+	TrackSide        map[string]string `mapstructure:"track_side"`
+	ScoreTrackedSide bool              `mapstructure:"score_tracked_side"`
+	// CategoryVolumeMin replaces the polymarket volume_*_min floors for events
+	// in a category; categories without an entry use the global floors.
+	CategoryVolumeMin map[string]VolumeThresholds `mapstructure:"category_volume_min"`
+	// CategoryScore overrides the alert threshold and score ceiling for
+	// markets in a category; unset fields keep the global values.
+	CategoryScore map[string]ScoreBounds `mapstructure:"category_score"`
+}
+
+// ScoreBounds overrides the score a category's markets must reach to alert
+// and its extreme-score ceiling. 0 keeps the global value (the threshold
+// derived from sensitivity, or score_ceiling).
+type ScoreBounds struct {
+	MinScore     float64 `mapstructure:"min_score"`
+	ScoreCeiling float64 `mapstructure:"score_ceiling"`
+}
+
+// VolumeThresholds is a set of minimum volumes an event must meet, combined
+// per polymarket.volume_filter_or. 0 disables a window's floor.
+type VolumeThresholds struct {
+	Volume24hrMin float64 `mapstructure:"volume_24hr_min"`
+	Volume1wkMin  float64 `mapstructure:"volume_1wk_min"`
+	Volume1moMin  float64 `mapstructure:"volume_1mo_min"`
+}
+
+// ScoreWeights are the exponents of the composite score factors.
+type ScoreWeights struct {
+	KLExp     float64 `mapstructure:"kl_exp"`
+	VolumeExp float64 `mapstructure:"volume_exp"`
+	SNRExp    float64 `mapstructure:"snr_exp"`
+	TCExp     float64 `mapstructure:"tc_exp"`
 }
 
 // MinCompositeScore returns the minimum composite score floor derived from sensitivity.
@@ -65,6 +276,66 @@ type TelegramConfig struct {
 	Enabled        bool          `mapstructure:"enabled"`
 	MaxRetries     int           `mapstructure:"max_retries"`
 	RetryDelayBase time.Duration `mapstructure:"retry_delay_base"`
+	// RetryPermanentErrors retries sends Telegram rejected permanently (bad
+	// chat, unauthorized, blocked, unparsable markup), which otherwise fail
+	// fast. Only useful if the error classification misfires.
+	RetryPermanentErrors bool `mapstructure:"retry_permanent_errors"`
+	// PlainTextFallback resends a message once as plain text when Telegram
+	// rejects its MarkdownV2, so an escaping bug costs formatting, not the alert.
+	PlainTextFallback bool `mapstructure:"plain_text_fallback"`
+	// TestChatID, when set, receives a prefixed copy of every notification
+	// before the real chat does, for checking formatting changes in a live
+	// render. "" = off.
+	TestChatID string `mapstructure:"test_chat_id"`
+	// MaxMarketsPerGroup caps how many markets are listed under one event in a
+	// notification (highest-scoring first); the rest collapse into "+N more". 0 = unlimited.
+	MaxMarketsPerGroup int `mapstructure:"max_markets_per_group"`
+	// DeltaStyle selects how probability changes are shown: "points" (percentage
+	// points), "relative" (change vs the old probability) or "both".
+	DeltaStyle string `mapstructure:"delta_style"`
+	// ShowMarketID tags each alerting market with its Polymarket market ID, so
+	// markets of multi-market events are identifiable even when the question
+	// repeats the event title.
+	ShowMarketID bool `mapstructure:"show_market_id"`
+	// ShowLiquidity adds each market's liquidity and 24h volume under its move.
+	ShowLiquidity bool `mapstructure:"show_liquidity"`
+	// ShowSparkline adds a unicode sparkline of each market's last
+	// SparklinePoints stored probabilities, showing the shape of the move.
+	ShowSparkline   bool `mapstructure:"show_sparkline"`
+	SparklinePoints int  `mapstructure:"sparkline_points"`
+	// ShowThresholdMultiple appends each market's score as a multiple of the
+	// threshold it cleared (e.g. "3.2× threshold") to its move.
+	ShowThresholdMultiple bool `mapstructure:"show_threshold_multiple"`
+	// ShowLastAlert notes under each move how long ago the market last alerted
+	// (from the alert history), or that this is its first alert.
+	ShowLastAlert bool `mapstructure:"show_last_alert"`
+	// ShowDescription adds each event's description, cut to DescriptionLength
+	// characters, under its title for context on unfamiliar markets.
+	ShowDescription   bool `mapstructure:"show_description"`
+	DescriptionLength int  `mapstructure:"description_length"`
+	// CategoryEmoji maps a category to an emoji or short label prefixed to each
+	// event group's title (e.g. crypto: "₿"). Unmapped categories get none.
+	CategoryEmoji map[string]string `mapstructure:"category_emoji"`
+	// SendConcurrency bounds simultaneous outbound sends and SendInterval spaces
+	// them, keeping the bot under Telegram's rate limits. A 429 from Telegram
+	// delays every later send by its retry_after regardless.
+	SendConcurrency int           `mapstructure:"send_concurrency"`
+	SendInterval    time.Duration `mapstructure:"send_interval"`
+	// FailOpen keeps monitoring running without notifications when the bot can't be
+	// initialized at startup, retrying every InitRetryInterval instead of exiting.
+	FailOpen          bool          `mapstructure:"fail_open"`
+	InitRetryInterval time.Duration `mapstructure:"init_retry_interval"`
+	// ErrorCooldown is the minimum time between cycle error/recovery notices, so
+	// a flapping upstream doesn't produce a stream of alternating messages.
+	ErrorCooldown time.Duration `mapstructure:"error_cooldown"`
+	// CoalesceWindow holds a cycle's alert groups this long, merging in those of
+	// any cycle that completes meanwhile, before sending one message. 0 = send
+	// each cycle's alerts immediately.
+	CoalesceWindow time.Duration `mapstructure:"coalesce_window"`
+	// AdminUserIDs restricts control commands (/pause, /resume) to these Telegram
+	// user IDs; commands in PublicCommands stay open to everyone. Empty = no gating.
+	AdminUserIDs   []int64  `mapstructure:"admin_user_ids"`
+	PublicCommands []string `mapstructure:"public_commands"`
 }
 
 // StorageConfig holds storage configuration
@@ -72,6 +343,20 @@ type StorageConfig struct {
 	MaxEvents            int    `mapstructure:"max_events"`
 	MaxSnapshotsPerEvent int    `mapstructure:"max_snapshots_per_event"`
 	DBPath               string `mapstructure:"db_path"`
+	ProbabilityEncoding  string `mapstructure:"probability_encoding"` // "real" or "basis_points"
+	// AutoVacuumInterval periodically reclaims free database pages between cycles. 0 = never.
+	AutoVacuumInterval time.Duration `mapstructure:"auto_vacuum_interval"`
+	// MaxMarketAge deletes markets not updated within this duration during
+	// rotation, alongside the max_events cap. 0 = no age limit.
+	MaxMarketAge time.Duration `mapstructure:"max_market_age"`
+	// ReadConnections sizes a separate read-only connection pool for query APIs
+	// (e.g. /history), so they don't queue behind monitoring writes. 0 = shared.
+	ReadConnections int `mapstructure:"read_connections"`
+	// SnapshotFailureThreshold marks the service unready (/readyz) when at
+	// least this many snapshot writes fail in a cycle; SnapshotFailureNotify
+	// also sends a Telegram warning. 0 = off.
+	SnapshotFailureThreshold int  `mapstructure:"snapshot_failure_threshold"`
+	SnapshotFailureNotify    bool `mapstructure:"snapshot_failure_notify"`
 }
 
 // LoggingConfig holds logging configuration
@@ -80,12 +365,40 @@ type LoggingConfig struct {
 	Format string `mapstructure:"format"`
 }
 
-// Load reads configuration from file and environment variables
-func Load(path string) (*Config, error) {
-	v := viper.New()
+// MetricsConfig holds Prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	ListenAddr string `mapstructure:"listen_addr"` // e.g. ":9090"; empty = metrics endpoint disabled
+}
+
+// OTelConfig holds OpenTelemetry tracing configuration
+type OTelConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Endpoint string `mapstructure:"endpoint"` // OTLP/HTTP collector URL, e.g. "http://localhost:4318"
+}
 
-	// Set config file
-	v.SetConfigFile(path)
+// StreamConfig holds the live alert stream (Server-Sent Events) configuration
+type StreamConfig struct {
+	ListenAddr   string `mapstructure:"listen_addr"`   // e.g. ":8080"; empty = alert stream disabled
+	ClientBuffer int    `mapstructure:"client_buffer"` // alerts queued per client before the oldest are dropped
+}
+
+// WebhookConfig holds the generic JSON webhook notifier configuration
+type WebhookConfig struct {
+	URL            string        `mapstructure:"url"` // http(s) endpoint; empty = webhook disabled
+	MaxRetries     int           `mapstructure:"max_retries"`
+	RetryDelayBase time.Duration `mapstructure:"retry_delay_base"`
+	Timeout        time.Duration `mapstructure:"timeout"` // per attempt
+}
+
+// Load reads configuration from one or more files and environment variables.
+// Later files overlay earlier ones key by key, so an environment file need only
+// hold its differences from a shared base. Precedence, lowest first: defaults,
+// the files in order, then environment variables.
+func Load(paths ...string) (*Config, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no config file given")
+	}
+	v := viper.New()
 
 	// Set defaults
 	setDefaults(v)
@@ -111,6 +424,24 @@ func Load(path string) (*Config, error) {
 	_ = v.BindEnv("polymarket.max_idle_conns", "POLY_ORACLE_POLYMARKET_MAX_IDLE_CONNS")
 	_ = v.BindEnv("polymarket.max_idle_conns_per_host", "POLY_ORACLE_POLYMARKET_MAX_IDLE_CONNS_PER_HOST")
 	_ = v.BindEnv("polymarket.idle_conn_timeout", "POLY_ORACLE_POLYMARKET_IDLE_CONN_TIMEOUT")
+	_ = v.BindEnv("polymarket.schema_drift_fraction", "POLY_ORACLE_POLYMARKET_SCHEMA_DRIFT_FRACTION")
+	_ = v.BindEnv("polymarket.schema_drift_notify", "POLY_ORACLE_POLYMARKET_SCHEMA_DRIFT_NOTIFY")
+	_ = v.BindEnv("polymarket.retry_on_empty", "POLY_ORACLE_POLYMARKET_RETRY_ON_EMPTY")
+	_ = v.BindEnv("polymarket.event_url_template", "POLY_ORACLE_POLYMARKET_EVENT_URL_TEMPLATE")
+	_ = v.BindEnv("polymarket.page_size", "POLY_ORACLE_POLYMARKET_PAGE_SIZE")
+	_ = v.BindEnv("polymarket.fetch_concurrency", "POLY_ORACLE_POLYMARKET_FETCH_CONCURRENCY")
+	_ = v.BindEnv("polymarket.per_category_fetch", "POLY_ORACLE_POLYMARKET_PER_CATEGORY_FETCH")
+	_ = v.BindEnv("polymarket.adaptive_interval", "POLY_ORACLE_POLYMARKET_ADAPTIVE_INTERVAL")
+	_ = v.BindEnv("polymarket.adaptive_max_interval", "POLY_ORACLE_POLYMARKET_ADAPTIVE_MAX_INTERVAL")
+	_ = v.BindEnv("polymarket.adaptive_idle_cycles", "POLY_ORACLE_POLYMARKET_ADAPTIVE_IDLE_CYCLES")
+	_ = v.BindEnv("polymarket.min_effective_interval", "POLY_ORACLE_POLYMARKET_MIN_EFFECTIVE_INTERVAL")
+	_ = v.BindEnv("polymarket.include_closed", "POLY_ORACLE_POLYMARKET_INCLUDE_CLOSED")
+	_ = v.BindEnv("polymarket.default_category", "POLY_ORACLE_POLYMARKET_DEFAULT_CATEGORY")
+	_ = v.BindEnv("polymarket.single_market_event_volume", "POLY_ORACLE_POLYMARKET_SINGLE_MARKET_EVENT_VOLUME")
+	_ = v.BindEnv("polymarket.residual_outcome_mode", "POLY_ORACLE_POLYMARKET_RESIDUAL_OUTCOME_MODE")
+	_ = v.BindEnv("polymarket.positional_outcome_fallback", "POLY_ORACLE_POLYMARKET_POSITIONAL_OUTCOME_FALLBACK")
+	_ = v.BindEnv("polymarket.order_book_depth_band", "POLY_ORACLE_POLYMARKET_ORDER_BOOK_DEPTH_BAND")
+	_ = v.BindEnv("polymarket.multi_outcome_markets", "POLY_ORACLE_POLYMARKET_MULTI_OUTCOME_MARKETS")
 
 	// Monitor
 	_ = v.BindEnv("monitor.sensitivity", "POLY_ORACLE_MONITOR_SENSITIVITY")
@@ -119,6 +450,56 @@ func Load(path string) (*Config, error) {
 	_ = v.BindEnv("monitor.detection_intervals", "POLY_ORACLE_MONITOR_DETECTION_INTERVALS")
 	_ = v.BindEnv("monitor.min_abs_change", "POLY_ORACLE_MONITOR_MIN_ABS_CHANGE")
 	_ = v.BindEnv("monitor.min_base_prob", "POLY_ORACLE_MONITOR_MIN_BASE_PROB")
+	_ = v.BindEnv("monitor.coverage_drop_fraction", "POLY_ORACLE_MONITOR_COVERAGE_DROP_FRACTION")
+	_ = v.BindEnv("monitor.coverage_window", "POLY_ORACLE_MONITOR_COVERAGE_WINDOW")
+	_ = v.BindEnv("monitor.adaptive_threshold", "POLY_ORACLE_MONITOR_ADAPTIVE_THRESHOLD")
+	_ = v.BindEnv("monitor.adaptive_alpha", "POLY_ORACLE_MONITOR_ADAPTIVE_ALPHA")
+	_ = v.BindEnv("monitor.notify_new_markets", "POLY_ORACLE_MONITOR_NOTIFY_NEW_MARKETS")
+	_ = v.BindEnv("monitor.new_market_volume_min", "POLY_ORACLE_MONITOR_NEW_MARKET_VOLUME_MIN")
+	_ = v.BindEnv("monitor.liquidity_drop_fraction", "POLY_ORACLE_MONITOR_LIQUIDITY_DROP_FRACTION")
+	_ = v.BindEnv("monitor.alert_on_uncertainty", "POLY_ORACLE_MONITOR_ALERT_ON_UNCERTAINTY")
+	_ = v.BindEnv("monitor.uncertainty_threshold", "POLY_ORACLE_MONITOR_UNCERTAINTY_THRESHOLD")
+	_ = v.BindEnv("monitor.tc_clip", "POLY_ORACLE_MONITOR_TC_CLIP")
+	_ = v.BindEnv("monitor.missing_cycles_before_cleanup", "POLY_ORACLE_MONITOR_MISSING_CYCLES_BEFORE_CLEANUP")
+	_ = v.BindEnv("monitor.max_states", "POLY_ORACLE_MONITOR_MAX_STATES")
+	_ = v.BindEnv("monitor.min_snapshots_for_sigma", "POLY_ORACLE_MONITOR_MIN_SNAPSHOTS_FOR_SIGMA")
+	_ = v.BindEnv("monitor.recent_sigma_weight", "POLY_ORACLE_MONITOR_RECENT_SIGMA_WEIGHT")
+	_ = v.BindEnv("monitor.recent_sigma_snapshots", "POLY_ORACLE_MONITOR_RECENT_SIGMA_SNAPSHOTS")
+	_ = v.BindEnv("monitor.min_probability", "POLY_ORACLE_MONITOR_MIN_PROBABILITY")
+	_ = v.BindEnv("monitor.max_probability", "POLY_ORACLE_MONITOR_MAX_PROBABILITY")
+	_ = v.BindEnv("monitor.topk_tiebreak", "POLY_ORACLE_MONITOR_TOPK_TIEBREAK")
+	_ = v.BindEnv("monitor.divergence_metric", "POLY_ORACLE_MONITOR_DIVERGENCE_METRIC")
+	_ = v.BindEnv("monitor.random_seed", "POLY_ORACLE_MONITOR_RANDOM_SEED")
+	_ = v.BindEnv("monitor.cycle_summary_output", "POLY_ORACLE_MONITOR_CYCLE_SUMMARY_OUTPUT")
+	_ = v.BindEnv("monitor.shutdown_timeout", "POLY_ORACLE_MONITOR_SHUTDOWN_TIMEOUT")
+	_ = v.BindEnv("monitor.price_smoothing_alpha", "POLY_ORACLE_MONITOR_PRICE_SMOOTHING_ALPHA")
+	_ = v.BindEnv("monitor.resolution_pending_cycles", "POLY_ORACLE_MONITOR_RESOLUTION_PENDING_CYCLES")
+	_ = v.BindEnv("monitor.resolution_notify", "POLY_ORACLE_MONITOR_RESOLUTION_NOTIFY")
+	_ = v.BindEnv("monitor.notify_resolved", "POLY_ORACLE_MONITOR_NOTIFY_RESOLVED")
+	_ = v.BindEnv("monitor.calibration", "POLY_ORACLE_MONITOR_CALIBRATION")
+	_ = v.BindEnv("monitor.calibration_lead_time", "POLY_ORACLE_MONITOR_CALIBRATION_LEAD_TIME")
+	_ = v.BindEnv("monitor.future_timestamp_tolerance", "POLY_ORACLE_MONITOR_FUTURE_TIMESTAMP_TOLERANCE")
+	_ = v.BindEnv("monitor.detect_ladder_inconsistency", "POLY_ORACLE_MONITOR_DETECT_LADDER_INCONSISTENCY")
+	_ = v.BindEnv("monitor.ladder_tolerance", "POLY_ORACLE_MONITOR_LADDER_TOLERANCE")
+	_ = v.BindEnv("monitor.max_alerts_per_market_per_day", "POLY_ORACLE_MONITOR_MAX_ALERTS_PER_MARKET_PER_DAY")
+	_ = v.BindEnv("monitor.event_cooldown_multiplier", "POLY_ORACLE_MONITOR_EVENT_COOLDOWN_MULTIPLIER")
+	_ = v.BindEnv("monitor.restore_event_cooldown", "POLY_ORACLE_MONITOR_RESTORE_EVENT_COOLDOWN")
+	_ = v.BindEnv("monitor.alert_budget_reset_hour", "POLY_ORACLE_MONITOR_ALERT_BUDGET_RESET_HOUR")
+	_ = v.BindEnv("monitor.group_min_best_score", "POLY_ORACLE_MONITOR_GROUP_MIN_BEST_SCORE")
+	_ = v.BindEnv("monitor.score_ceiling", "POLY_ORACLE_MONITOR_SCORE_CEILING")
+	_ = v.BindEnv("monitor.alert_above_ceiling", "POLY_ORACLE_MONITOR_ALERT_ABOVE_CEILING")
+	_ = v.BindEnv("monitor.compact_state", "POLY_ORACLE_MONITOR_COMPACT_STATE")
+	_ = v.BindEnv("monitor.score_expr", "POLY_ORACLE_MONITOR_SCORE_EXPR")
+	_ = v.BindEnv("monitor.weights.kl_exp", "POLY_ORACLE_MONITOR_WEIGHTS_KL_EXP")
+	_ = v.BindEnv("monitor.weights.volume_exp", "POLY_ORACLE_MONITOR_WEIGHTS_VOLUME_EXP")
+	_ = v.BindEnv("monitor.weights.snr_exp", "POLY_ORACLE_MONITOR_WEIGHTS_SNR_EXP")
+	_ = v.BindEnv("monitor.weights.tc_exp", "POLY_ORACLE_MONITOR_WEIGHTS_TC_EXP")
+	_ = v.BindEnv("monitor.dedup_similar_titles", "POLY_ORACLE_MONITOR_DEDUP_SIMILAR_TITLES")
+	_ = v.BindEnv("monitor.title_similarity", "POLY_ORACLE_MONITOR_TITLE_SIMILARITY")
+	_ = v.BindEnv("monitor.maintenance_windows", "POLY_ORACLE_MONITOR_MAINTENANCE_WINDOWS")
+	_ = v.BindEnv("monitor.maintenance_timezone", "POLY_ORACLE_MONITOR_MAINTENANCE_TIMEZONE")
+	_ = v.BindEnv("monitor.freeze_state_in_maintenance", "POLY_ORACLE_MONITOR_FREEZE_STATE_IN_MAINTENANCE")
+	_ = v.BindEnv("monitor.score_tracked_side", "POLY_ORACLE_MONITOR_SCORE_TRACKED_SIDE")
 
 	// Telegram
 	_ = v.BindEnv("telegram.bot_token", "POLY_ORACLE_TELEGRAM_BOT_TOKEN")
@@ -126,19 +507,70 @@ func Load(path string) (*Config, error) {
 	_ = v.BindEnv("telegram.enabled", "POLY_ORACLE_TELEGRAM_ENABLED")
 	_ = v.BindEnv("telegram.max_retries", "POLY_ORACLE_TELEGRAM_MAX_RETRIES")
 	_ = v.BindEnv("telegram.retry_delay_base", "POLY_ORACLE_TELEGRAM_RETRY_DELAY_BASE")
+	_ = v.BindEnv("telegram.retry_permanent_errors", "POLY_ORACLE_TELEGRAM_RETRY_PERMANENT_ERRORS")
+	_ = v.BindEnv("telegram.plain_text_fallback", "POLY_ORACLE_TELEGRAM_PLAIN_TEXT_FALLBACK")
+	_ = v.BindEnv("telegram.test_chat_id", "POLY_ORACLE_TELEGRAM_TEST_CHAT_ID")
+	_ = v.BindEnv("telegram.max_markets_per_group", "POLY_ORACLE_TELEGRAM_MAX_MARKETS_PER_GROUP")
+	_ = v.BindEnv("telegram.delta_style", "POLY_ORACLE_TELEGRAM_DELTA_STYLE")
+	_ = v.BindEnv("telegram.fail_open", "POLY_ORACLE_TELEGRAM_FAIL_OPEN")
+	_ = v.BindEnv("telegram.show_market_id", "POLY_ORACLE_TELEGRAM_SHOW_MARKET_ID")
+	_ = v.BindEnv("telegram.show_liquidity", "POLY_ORACLE_TELEGRAM_SHOW_LIQUIDITY")
+	_ = v.BindEnv("telegram.show_sparkline", "POLY_ORACLE_TELEGRAM_SHOW_SPARKLINE")
+	_ = v.BindEnv("telegram.sparkline_points", "POLY_ORACLE_TELEGRAM_SPARKLINE_POINTS")
+	_ = v.BindEnv("telegram.show_threshold_multiple", "POLY_ORACLE_TELEGRAM_SHOW_THRESHOLD_MULTIPLE")
+	_ = v.BindEnv("telegram.show_last_alert", "POLY_ORACLE_TELEGRAM_SHOW_LAST_ALERT")
+	_ = v.BindEnv("telegram.show_description", "POLY_ORACLE_TELEGRAM_SHOW_DESCRIPTION")
+	_ = v.BindEnv("telegram.description_length", "POLY_ORACLE_TELEGRAM_DESCRIPTION_LENGTH")
+	_ = v.BindEnv("telegram.send_concurrency", "POLY_ORACLE_TELEGRAM_SEND_CONCURRENCY")
+	_ = v.BindEnv("telegram.send_interval", "POLY_ORACLE_TELEGRAM_SEND_INTERVAL")
+	_ = v.BindEnv("telegram.init_retry_interval", "POLY_ORACLE_TELEGRAM_INIT_RETRY_INTERVAL")
+	_ = v.BindEnv("telegram.error_cooldown", "POLY_ORACLE_TELEGRAM_ERROR_COOLDOWN")
+	_ = v.BindEnv("telegram.coalesce_window", "POLY_ORACLE_TELEGRAM_COALESCE_WINDOW")
+	_ = v.BindEnv("telegram.admin_user_ids", "POLY_ORACLE_TELEGRAM_ADMIN_USER_IDS")
+	_ = v.BindEnv("telegram.public_commands", "POLY_ORACLE_TELEGRAM_PUBLIC_COMMANDS")
 
 	// Storage
 	_ = v.BindEnv("storage.max_events", "POLY_ORACLE_STORAGE_MAX_EVENTS")
 	_ = v.BindEnv("storage.max_snapshots_per_event", "POLY_ORACLE_STORAGE_MAX_SNAPSHOTS_PER_EVENT")
 	_ = v.BindEnv("storage.db_path", "POLY_ORACLE_STORAGE_DB_PATH")
+	_ = v.BindEnv("storage.probability_encoding", "POLY_ORACLE_STORAGE_PROBABILITY_ENCODING")
+	_ = v.BindEnv("storage.auto_vacuum_interval", "POLY_ORACLE_STORAGE_AUTO_VACUUM_INTERVAL")
+	_ = v.BindEnv("storage.max_market_age", "POLY_ORACLE_STORAGE_MAX_MARKET_AGE")
+	_ = v.BindEnv("storage.read_connections", "POLY_ORACLE_STORAGE_READ_CONNECTIONS")
+	_ = v.BindEnv("storage.snapshot_failure_threshold", "POLY_ORACLE_STORAGE_SNAPSHOT_FAILURE_THRESHOLD")
+	_ = v.BindEnv("storage.snapshot_failure_notify", "POLY_ORACLE_STORAGE_SNAPSHOT_FAILURE_NOTIFY")
 
 	// Logging
 	_ = v.BindEnv("logging.level", "POLY_ORACLE_LOGGING_LEVEL")
 	_ = v.BindEnv("logging.format", "POLY_ORACLE_LOGGING_FORMAT")
 
-	// Read config file
-	if err := v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+	// Metrics
+	_ = v.BindEnv("metrics.listen_addr", "POLY_ORACLE_METRICS_LISTEN_ADDR")
+
+	// OpenTelemetry
+	_ = v.BindEnv("otel.enabled", "POLY_ORACLE_OTEL_ENABLED")
+	_ = v.BindEnv("otel.endpoint", "POLY_ORACLE_OTEL_ENDPOINT")
+
+	// Alert stream
+	_ = v.BindEnv("stream.listen_addr", "POLY_ORACLE_STREAM_LISTEN_ADDR")
+	_ = v.BindEnv("stream.client_buffer", "POLY_ORACLE_STREAM_CLIENT_BUFFER")
+
+	// Webhook
+	_ = v.BindEnv("webhook.url", "POLY_ORACLE_WEBHOOK_URL")
+	_ = v.BindEnv("webhook.max_retries", "POLY_ORACLE_WEBHOOK_MAX_RETRIES")
+	_ = v.BindEnv("webhook.retry_delay_base", "POLY_ORACLE_WEBHOOK_RETRY_DELAY_BASE")
+	_ = v.BindEnv("webhook.timeout", "POLY_ORACLE_WEBHOOK_TIMEOUT")
+
+	// Read the base config file, then merge each overlay over it
+	for i, path := range paths {
+		v.SetConfigFile(path)
+		read := v.MergeInConfig
+		if i == 0 {
+			read = v.ReadInConfig
+		}
+		if err := read(); err != nil {
+			return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+		}
 	}
 
 	// Unmarshal into Config struct
@@ -170,6 +602,43 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("polymarket.max_idle_conns", 100)
 	v.SetDefault("polymarket.max_idle_conns_per_host", 10)
 	v.SetDefault("polymarket.idle_conn_timeout", "90s")
+	v.SetDefault("polymarket.schema_drift_fraction", 0.5) // half a page of empty events = likely renamed fields
+	v.SetDefault("polymarket.schema_drift_notify", true)
+	v.SetDefault("polymarket.retry_on_empty", true)
+	v.SetDefault("polymarket.event_url_template", "https://polymarket.com/event/{slug}")
+	v.SetDefault("polymarket.page_size", 500) // API max per request
+	v.SetDefault("polymarket.fetch_concurrency", 4)
+	v.SetDefault("polymarket.per_category_fetch", false)
+
+	// Adaptive polling: off = fixed poll_interval
+	v.SetDefault("polymarket.adaptive_interval", false)
+	v.SetDefault("polymarket.adaptive_max_interval", "1h")
+	v.SetDefault("polymarket.adaptive_idle_cycles", 6)
+
+	// Runtime poll floor: below the 1m poll_interval minimum, so it only
+	// catches cycles bunched up by a slow previous cycle or a misconfiguration
+	v.SetDefault("polymarket.min_effective_interval", "30s")
+
+	// Live monitoring only needs open markets
+	v.SetDefault("polymarket.include_closed", false)
+
+	// Untagged events are tracked under a catch-all category
+	v.SetDefault("polymarket.default_category", "other")
+
+	// Single-market events: the event volume is the market volume
+	v.SetDefault("polymarket.single_market_event_volume", true)
+
+	// Residual outcomes: fold them out of Yes/No so the pair sums to 1
+	v.SetDefault("polymarket.residual_outcome_mode", "distribute")
+
+	// Two-price markets with unusual outcome labels: read them as Yes, No
+	v.SetDefault("polymarket.positional_outcome_fallback", true)
+
+	// Categorical markets: one series per outcome
+	v.SetDefault("polymarket.multi_outcome_markets", true)
+
+	// Liquidity: Gamma's figure unless order-book depth is requested
+	v.SetDefault("polymarket.order_book_depth_band", 0.0)
 
 	// Monitor defaults
 	v.SetDefault("monitor.sensitivity", 0.5) // medium quality bar
@@ -179,19 +648,195 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("monitor.min_abs_change", 0.03)   // 3pp minimum absolute change
 	v.SetDefault("monitor.min_base_prob", 0.05)    // 5% minimum base probability
 
+	// Coverage guard: disabled by default; rolling average over 12 cycles
+	v.SetDefault("monitor.coverage_drop_fraction", 0.0)
+	v.SetDefault("monitor.coverage_window", 12)
+
+	// Adaptive threshold: off by default (fixed sensitivity-derived bar)
+	v.SetDefault("monitor.adaptive_threshold", false)
+	v.SetDefault("monitor.adaptive_alpha", 0.1)
+
+	// New market announcements: off by default
+	v.SetDefault("monitor.notify_new_markets", false)
+	v.SetDefault("monitor.new_market_volume_min", 500000.0) // $500K 24hr volume
+
+	// Liquidity-drop alerts: off by default
+	v.SetDefault("monitor.liquidity_drop_fraction", 0.0)
+
+	// Uncertainty (coin-flip convergence) alerts: off by default
+	v.SetDefault("monitor.alert_on_uncertainty", false)
+	v.SetDefault("monitor.uncertainty_threshold", 0.15) // e.g. 80% → 65%
+
+	// Trajectory consistency: per-step moves unclamped by default
+	v.SetDefault("monitor.tc_clip", 0.0)
+
+	// Forget markets that stop appearing in fetches after 12 cycles (1h at 5m polls)
+	v.SetDefault("monitor.missing_cycles_before_cleanup", 12)
+	v.SetDefault("monitor.max_states", 0) // no hard cap
+
+	// SNR volatility: trust each market's own sample σ (no blending) by default
+	v.SetDefault("monitor.min_snapshots_for_sigma", 0)
+
+	// Recent-volatility blend: off (all history); a day of 1h polls when enabled
+	v.SetDefault("monitor.recent_sigma_weight", 0.0)
+	v.SetDefault("monitor.recent_sigma_snapshots", 24)
+
+	// Probability band for alerting: the full range (disabled)
+	v.SetDefault("monitor.min_probability", 0.0)
+	v.SetDefault("monitor.max_probability", 1.0)
+
+	// TopK ordering among near-equal scores
+	v.SetDefault("monitor.topk_tiebreak", "score_only")
+
+	// Divergence metric: KL, which the sensitivity thresholds are tuned for
+	v.SetDefault("monitor.divergence_metric", "kl")
+	v.SetDefault("monitor.random_seed", 0) // 0 = time-based
+
+	// Cycle summary: off
+	v.SetDefault("monitor.cycle_summary_output", "")
+
+	// Shutdown: let an in-flight cycle finish for up to 30s
+	v.SetDefault("monitor.shutdown_timeout", 30*time.Second)
+
+	// Price smoothing: raw quotes
+	v.SetDefault("monitor.price_smoothing_alpha", 1.0)
+
+	// Resolution limbo: off, no note
+	v.SetDefault("monitor.resolution_pending_cycles", 0)
+	v.SetDefault("monitor.resolution_notify", false)
+
+	// Resolution alerts: off (closed markets are only fetched with include_closed)
+	v.SetDefault("monitor.notify_resolved", false)
+
+	// Calibration: off; scores the price a day before resolution
+	v.SetDefault("monitor.calibration", false)
+	v.SetDefault("monitor.calibration_lead_time", "24h")
+
+	// Clock skew: accept timestamps up to 5s ahead of the local clock
+	v.SetDefault("monitor.future_timestamp_tolerance", "5s")
+
+	// Ladder inconsistency alerts: off; 2pp of slack for spread noise
+	v.SetDefault("monitor.detect_ladder_inconsistency", false)
+	v.SetDefault("monitor.ladder_tolerance", 0.02)
+
+	// Per-market alert budget: unlimited; the day starts at midnight UTC
+	v.SetDefault("monitor.max_alerts_per_market_per_day", 0)
+	v.SetDefault("monitor.alert_budget_reset_hour", 0)
+
+	// Event-level cooldown: off; when on, survives restarts via alert history
+	v.SetDefault("monitor.event_cooldown_multiplier", 0.0)
+	v.SetDefault("monitor.restore_event_cooldown", true)
+
+	// Group-level score gate: off, the per-market bar alone decides
+	v.SetDefault("monitor.group_min_best_score", 0.0)
+
+	// Score ceiling: off; when set, extreme scores still alert by default
+	v.SetDefault("monitor.score_ceiling", 0.0)
+	v.SetDefault("monitor.alert_above_ceiling", true)
+
+	// Compact state: off (full float64 baselines)
+	v.SetDefault("monitor.compact_state", false)
+
+	// Scoring formula: built-in composite
+	v.SetDefault("monitor.score_expr", "")
+
+	// Score weights: every factor as is, reproducing the plain product
+	v.SetDefault("monitor.weights.kl_exp", 1.0)
+	v.SetDefault("monitor.weights.volume_exp", 1.0)
+	v.SetDefault("monitor.weights.snr_exp", 1.0)
+	v.SetDefault("monitor.weights.tc_exp", 1.0)
+
+	// Similar-title dedup: off (heuristic); 0.6 when enabled
+	v.SetDefault("monitor.dedup_similar_titles", false)
+	v.SetDefault("monitor.title_similarity", 0.6)
+
+	// Maintenance windows: none; UTC; baselines frozen while one is active
+	v.SetDefault("monitor.maintenance_windows", []string{})
+	v.SetDefault("monitor.maintenance_timezone", "UTC")
+	v.SetDefault("monitor.freeze_state_in_maintenance", true)
+
+	// Tracked side: Yes everywhere; prefilters stay on the Yes side
+	v.SetDefault("monitor.track_side", map[string]string{})
+	v.SetDefault("monitor.category_volume_min", map[string]any{})
+	v.SetDefault("monitor.category_score", map[string]any{})
+	v.SetDefault("monitor.score_tracked_side", false)
+
 	// Telegram defaults
 	v.SetDefault("telegram.enabled", false)
 	v.SetDefault("telegram.max_retries", 3)
 	v.SetDefault("telegram.retry_delay_base", "1s")
+	v.SetDefault("telegram.retry_permanent_errors", false)
+	v.SetDefault("telegram.plain_text_fallback", true)
+	v.SetDefault("telegram.test_chat_id", "")
+	v.SetDefault("telegram.max_markets_per_group", 0) // 0 = show every alerting market
+	v.SetDefault("telegram.delta_style", "points")    // percentage-point deltas
+	v.SetDefault("telegram.fail_open", false)         // exit if Telegram is unreachable at startup
+	v.SetDefault("telegram.init_retry_interval", "5m")
+
+	// Error notices: no cooldown, announce every failure run and recovery
+	v.SetDefault("telegram.error_cooldown", 0)
+
+	// Alert coalescing: off, each cycle sends its own message
+	v.SetDefault("telegram.coalesce_window", 0)
+
+	// Market context in alerts: off keeps messages compact
+	v.SetDefault("telegram.show_market_id", false)
+	v.SetDefault("telegram.show_liquidity", false)
+	v.SetDefault("telegram.category_emoji", map[string]string{})
+	v.SetDefault("telegram.show_sparkline", false)
+	v.SetDefault("telegram.sparkline_points", 12)
+	v.SetDefault("telegram.show_threshold_multiple", false)
+	v.SetDefault("telegram.show_last_alert", false)
+	v.SetDefault("telegram.show_description", false)
+	v.SetDefault("telegram.description_length", 200)
+
+	// Outbound rate limiting: one send at a time, 100ms apart
+	v.SetDefault("telegram.send_concurrency", 1)
+	v.SetDefault("telegram.send_interval", "100ms")
+
+	// Command gating: no admins configured = every command open
+	v.SetDefault("telegram.admin_user_ids", []int64{})
+	v.SetDefault("telegram.public_commands", []string{"ping", "status", "top"})
 
 	// Storage defaults
 	v.SetDefault("storage.max_events", 10000)
 	v.SetDefault("storage.max_snapshots_per_event", 672) // 7 days of 15-min snapshots
 	v.SetDefault("storage.db_path", "")                  // empty = OS tmp dir
+	v.SetDefault("storage.probability_encoding", "real")
+
+	// Periodic vacuum: off by default (use `polyoracle vacuum` while stopped)
+	v.SetDefault("storage.auto_vacuum_interval", "0s")
+
+	// Market age limit: off, only the max_events cap applies
+	v.SetDefault("storage.max_market_age", "0s")
+
+	// Read pool: a few read-only connections beside the single writer
+	v.SetDefault("storage.read_connections", 4)
+
+	// Snapshot write health: readiness check off; no Telegram warning
+	v.SetDefault("storage.snapshot_failure_threshold", 0)
+	v.SetDefault("storage.snapshot_failure_notify", false)
 
 	// Logging defaults
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "json")
+
+	// Metrics defaults
+	v.SetDefault("metrics.listen_addr", "") // disabled
+
+	// OpenTelemetry defaults
+	v.SetDefault("otel.enabled", false)
+	v.SetDefault("otel.endpoint", "http://localhost:4318")
+
+	// Alert stream defaults
+	v.SetDefault("stream.listen_addr", "") // disabled
+	v.SetDefault("stream.client_buffer", 64)
+
+	// Webhook defaults
+	v.SetDefault("webhook.url", "") // disabled
+	v.SetDefault("webhook.max_retries", 3)
+	v.SetDefault("webhook.retry_delay_base", "1s")
+	v.SetDefault("webhook.timeout", "10s")
 }
 
 // Validate checks that all configuration values are valid
@@ -206,6 +851,17 @@ func (c *Config) Validate() error {
 	if c.Polymarket.PollInterval < 1*time.Minute {
 		return fmt.Errorf("polymarket.poll_interval must be at least 1 minute")
 	}
+	if c.Polymarket.AdaptiveInterval {
+		if c.Polymarket.AdaptiveMaxInterval < c.Polymarket.PollInterval {
+			return fmt.Errorf("polymarket.adaptive_max_interval must be at least polymarket.poll_interval")
+		}
+		if c.Polymarket.AdaptiveIdleCycles < 1 {
+			return fmt.Errorf("polymarket.adaptive_idle_cycles must be at least 1")
+		}
+	}
+	if c.Polymarket.MinEffectiveInterval < 0 {
+		return fmt.Errorf("polymarket.min_effective_interval must not be negative")
+	}
 	if len(c.Polymarket.Categories) == 0 {
 		return fmt.Errorf("polymarket.categories must contain at least one category")
 	}
@@ -221,6 +877,25 @@ func (c *Config) Validate() error {
 	if c.Polymarket.Limit < 1 || c.Polymarket.Limit > 10000 {
 		return fmt.Errorf("polymarket.limit must be between 1 and 10000")
 	}
+	if c.Polymarket.EventURLTemplate != "" && !strings.Contains(c.Polymarket.EventURLTemplate, "{slug}") &&
+		!strings.Contains(c.Polymarket.EventURLTemplate, "{marketID}") {
+		return fmt.Errorf("polymarket.event_url_template must contain {slug} or {marketID}")
+	}
+	if c.Polymarket.PageSize < 1 || c.Polymarket.PageSize > 500 {
+		return fmt.Errorf("polymarket.page_size must be between 1 and 500")
+	}
+	if c.Polymarket.FetchConcurrency < 1 {
+		return fmt.Errorf("polymarket.fetch_concurrency must be at least 1")
+	}
+	if c.Polymarket.SchemaDriftFraction < 0.0 || c.Polymarket.SchemaDriftFraction > 1.0 {
+		return fmt.Errorf("polymarket.schema_drift_fraction must be between 0.0 and 1.0")
+	}
+	if m := c.Polymarket.ResidualOutcomeMode; m != "" && m != "distribute" && m != "ignore" {
+		return fmt.Errorf("polymarket.residual_outcome_mode must be 'distribute' or 'ignore'")
+	}
+	if c.Polymarket.OrderBookDepthBand < 0 || c.Polymarket.OrderBookDepthBand >= 0.5 {
+		return fmt.Errorf("polymarket.order_book_depth_band must be between 0 and 0.5")
+	}
 
 	// Validate Monitor config
 	if c.Monitor.Sensitivity < 0.0 || c.Monitor.Sensitivity > 1.0 {
@@ -238,6 +913,121 @@ func (c *Config) Validate() error {
 	if c.Monitor.MinBaseProb < 0.0 || c.Monitor.MinBaseProb >= 0.5 {
 		return fmt.Errorf("monitor.min_base_prob must be in [0.0, 0.5)")
 	}
+	if c.Monitor.CoverageDropFraction < 0.0 || c.Monitor.CoverageDropFraction > 1.0 {
+		return fmt.Errorf("monitor.coverage_drop_fraction must be between 0.0 and 1.0")
+	}
+	if c.Monitor.CoverageDropFraction > 0 && c.Monitor.CoverageWindow < 1 {
+		return fmt.Errorf("monitor.coverage_window must be at least 1 when coverage_drop_fraction is set")
+	}
+	if c.Monitor.AdaptiveThreshold && (c.Monitor.AdaptiveAlpha <= 0.0 || c.Monitor.AdaptiveAlpha > 1.0) {
+		return fmt.Errorf("monitor.adaptive_alpha must be in (0.0, 1.0] when adaptive_threshold is enabled")
+	}
+	if c.Monitor.LiquidityDropFraction < 0.0 || c.Monitor.LiquidityDropFraction >= 1.0 {
+		return fmt.Errorf("monitor.liquidity_drop_fraction must be in [0.0, 1.0)")
+	}
+	if c.Monitor.AlertOnUncertainty && (c.Monitor.UncertaintyThreshold <= 0.0 || c.Monitor.UncertaintyThreshold > 0.5) {
+		return fmt.Errorf("monitor.uncertainty_threshold must be in (0.0, 0.5] when alert_on_uncertainty is enabled")
+	}
+	if c.Monitor.TCClip < 0.0 || c.Monitor.TCClip > 1.0 {
+		return fmt.Errorf("monitor.tc_clip must be between 0.0 and 1.0")
+	}
+	if c.Monitor.MissingCyclesBeforeCleanup < 0 {
+		return fmt.Errorf("monitor.missing_cycles_before_cleanup must not be negative")
+	}
+	if c.Monitor.MaxStates < 0 {
+		return fmt.Errorf("monitor.max_states must not be negative")
+	}
+	if c.Monitor.MinSnapshotsForSigma < 0 {
+		return fmt.Errorf("monitor.min_snapshots_for_sigma must not be negative")
+	}
+	if c.Monitor.RecentSigmaWeight < 0.0 || c.Monitor.RecentSigmaWeight > 1.0 {
+		return fmt.Errorf("monitor.recent_sigma_weight must be in [0.0, 1.0]")
+	}
+	if c.Monitor.RecentSigmaWeight > 0 && c.Monitor.RecentSigmaSnapshots < 3 {
+		return fmt.Errorf("monitor.recent_sigma_snapshots must be at least 3 when monitor.recent_sigma_weight is set")
+	}
+	if c.Monitor.MinProbability < 0.0 || c.Monitor.MaxProbability > 1.0 || c.Monitor.MinProbability >= c.Monitor.MaxProbability {
+		return fmt.Errorf("monitor.min_probability and monitor.max_probability must satisfy 0.0 <= min < max <= 1.0")
+	}
+	if c.Monitor.LadderTolerance < 0.0 || c.Monitor.LadderTolerance >= 1.0 {
+		return fmt.Errorf("monitor.ladder_tolerance must be in [0.0, 1.0)")
+	}
+	if c.Monitor.ScoreCeiling < 0 {
+		return fmt.Errorf("monitor.score_ceiling must be >= 0")
+	}
+	if w := c.Monitor.Weights; w.KLExp < 0 || w.VolumeExp < 0 || w.SNRExp < 0 || w.TCExp < 0 {
+		return fmt.Errorf("monitor.weights exponents must be >= 0")
+	}
+	if c.Monitor.GroupMinBestScore < 0 {
+		return fmt.Errorf("monitor.group_min_best_score must be >= 0")
+	}
+	if c.Monitor.DedupSimilarTitles && (c.Monitor.TitleSimilarity <= 0 || c.Monitor.TitleSimilarity > 1) {
+		return fmt.Errorf("monitor.title_similarity must be in (0.0, 1.0] when monitor.dedup_similar_titles is enabled")
+	}
+	if _, err := time.LoadLocation(c.Monitor.MaintenanceTimezone); err != nil {
+		return fmt.Errorf("monitor.maintenance_timezone is not a valid timezone: %w", err)
+	}
+	for key, side := range c.Monitor.TrackSide {
+		if side != "yes" && side != "no" {
+			return fmt.Errorf("monitor.track_side[%s] must be 'yes' or 'no'", key)
+		}
+	}
+	for category, th := range c.Monitor.CategoryVolumeMin {
+		if th.Volume24hrMin < 0 || th.Volume1wkMin < 0 || th.Volume1moMin < 0 {
+			return fmt.Errorf("monitor.category_volume_min[%s] volumes must not be negative", category)
+		}
+	}
+	for category, b := range c.Monitor.CategoryScore {
+		if b.MinScore < 0 || b.ScoreCeiling < 0 {
+			return fmt.Errorf("monitor.category_score[%s] scores must not be negative", category)
+		}
+		if b.MinScore > 0 && b.ScoreCeiling > 0 && b.MinScore >= b.ScoreCeiling {
+			return fmt.Errorf("monitor.category_score[%s] min_score must be below score_ceiling", category)
+		}
+	}
+	if c.Monitor.MaxAlertsPerMarketPerDay < 0 {
+		return fmt.Errorf("monitor.max_alerts_per_market_per_day must not be negative")
+	}
+	if c.Monitor.EventCooldownMultiplier < 0 {
+		return fmt.Errorf("monitor.event_cooldown_multiplier must not be negative")
+	}
+	if c.Monitor.AlertBudgetResetHour < 0 || c.Monitor.AlertBudgetResetHour > 23 {
+		return fmt.Errorf("monitor.alert_budget_reset_hour must be in [0, 23]")
+	}
+	if c.Monitor.FutureTimestampTolerance < 0 {
+		return fmt.Errorf("monitor.future_timestamp_tolerance must not be negative")
+	}
+	if c.Monitor.ResolutionPendingCycles < 0 {
+		return fmt.Errorf("monitor.resolution_pending_cycles must not be negative")
+	}
+	if c.Monitor.NotifyResolved && !c.Polymarket.IncludeClosed {
+		return fmt.Errorf("monitor.notify_resolved requires polymarket.include_closed to observe resolutions")
+	}
+	if c.Monitor.Calibration {
+		if c.Monitor.CalibrationLeadTime <= 0 {
+			return fmt.Errorf("monitor.calibration_lead_time must be positive when monitor.calibration is enabled")
+		}
+		if !c.Polymarket.IncludeClosed {
+			return fmt.Errorf("monitor.calibration requires polymarket.include_closed to observe resolutions")
+		}
+	}
+	if c.Monitor.PriceSmoothingAlpha <= 0.0 || c.Monitor.PriceSmoothingAlpha > 1.0 {
+		return fmt.Errorf("monitor.price_smoothing_alpha must be in (0.0, 1.0]")
+	}
+	validTiebreaks := map[string]bool{"score_only": true, "recency": true, "volume": true}
+	if !validTiebreaks[c.Monitor.TopKTiebreak] {
+		return fmt.Errorf("monitor.topk_tiebreak must be one of: score_only, recency, volume")
+	}
+	if c.Monitor.ShutdownTimeout <= 0 {
+		return fmt.Errorf("monitor.shutdown_timeout must be positive")
+	}
+	validMetrics := map[string]bool{"kl": true, "js": true, "hellinger": true}
+	if !validMetrics[c.Monitor.DivergenceMetric] {
+		return fmt.Errorf("monitor.divergence_metric must be one of: kl, js, hellinger")
+	}
+	if c.Monitor.NewMarketVolumeMin < 0 {
+		return fmt.Errorf("monitor.new_market_volume_min must not be negative")
+	}
 
 	// Validate Telegram config
 	if c.Telegram.Enabled {
@@ -248,6 +1038,40 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("telegram.chat_id is required when telegram is enabled")
 		}
 	}
+	if c.Telegram.ErrorCooldown < 0 {
+		return fmt.Errorf("telegram.error_cooldown must not be negative")
+	}
+	if c.Telegram.CoalesceWindow < 0 {
+		return fmt.Errorf("telegram.coalesce_window must not be negative")
+	}
+	if c.Telegram.FailOpen && c.Telegram.InitRetryInterval <= 0 {
+		return fmt.Errorf("telegram.init_retry_interval must be positive when telegram.fail_open is enabled")
+	}
+	validCommands := map[string]bool{"ping": true, "pause": true, "resume": true, "status": true, "history": true, "top": true}
+	for _, cmd := range c.Telegram.PublicCommands {
+		if !validCommands[strings.TrimPrefix(cmd, "/")] {
+			return fmt.Errorf("telegram.public_commands: unknown command %q (available: ping, pause, resume, status, history, top)", cmd)
+		}
+	}
+	validDeltaStyles := map[string]bool{"points": true, "relative": true, "both": true}
+	if !validDeltaStyles[c.Telegram.DeltaStyle] {
+		return fmt.Errorf("telegram.delta_style must be one of: points, relative, both")
+	}
+	if c.Telegram.MaxMarketsPerGroup < 0 {
+		return fmt.Errorf("telegram.max_markets_per_group must not be negative")
+	}
+	if c.Telegram.ShowSparkline && c.Telegram.SparklinePoints < 2 {
+		return fmt.Errorf("telegram.sparkline_points must be at least 2 when telegram.show_sparkline is enabled")
+	}
+	if c.Telegram.ShowDescription && c.Telegram.DescriptionLength < 10 {
+		return fmt.Errorf("telegram.description_length must be at least 10 when telegram.show_description is enabled")
+	}
+	if c.Telegram.SendConcurrency < 1 {
+		return fmt.Errorf("telegram.send_concurrency must be at least 1")
+	}
+	if c.Telegram.SendInterval < 0 {
+		return fmt.Errorf("telegram.send_interval must not be negative")
+	}
 
 	// Validate Storage config
 	if c.Storage.MaxEvents < 1 {
@@ -257,6 +1081,22 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("storage.max_snapshots_per_event must be at least 10")
 	}
 	// DBPath can be empty — storage layer defaults to OS tmp directory
+	validEncodings := map[string]bool{"real": true, "basis_points": true}
+	if !validEncodings[c.Storage.ProbabilityEncoding] {
+		return fmt.Errorf("storage.probability_encoding must be one of: real, basis_points")
+	}
+	if c.Storage.AutoVacuumInterval < 0 {
+		return fmt.Errorf("storage.auto_vacuum_interval must not be negative")
+	}
+	if c.Storage.MaxMarketAge < 0 {
+		return fmt.Errorf("storage.max_market_age must not be negative")
+	}
+	if c.Storage.ReadConnections < 0 {
+		return fmt.Errorf("storage.read_connections must not be negative")
+	}
+	if c.Storage.SnapshotFailureThreshold < 0 {
+		return fmt.Errorf("storage.snapshot_failure_threshold must not be negative")
+	}
 
 	// Validate Logging config
 	validLogLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
@@ -268,5 +1108,89 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("logging.format must be one of: json, text")
 	}
 
+	// Validate OpenTelemetry config
+	if c.OTel.Enabled && c.OTel.Endpoint == "" {
+		return fmt.Errorf("otel.endpoint is required when otel is enabled")
+	}
+
+	// Validate alert stream config
+	if c.Stream.ClientBuffer < 1 {
+		return fmt.Errorf("stream.client_buffer must be at least 1")
+	}
+
+	// Validate webhook config
+	if c.Webhook.URL != "" {
+		if u, err := url.Parse(c.Webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhook.url must be an http or https URL")
+		}
+		if c.Webhook.MaxRetries < 1 {
+			return fmt.Errorf("webhook.max_retries must be at least 1")
+		}
+		if c.Webhook.RetryDelayBase < 0 {
+			return fmt.Errorf("webhook.retry_delay_base must not be negative")
+		}
+		if c.Webhook.Timeout <= 0 {
+			return fmt.Errorf("webhook.timeout must be positive")
+		}
+	}
+
 	return nil
 }
+
+// redactedValue replaces secrets in Settings output.
+const redactedValue = "REDACTED"
+
+// Settings returns the configuration as nested maps keyed like the config
+// file, with defaults and overrides already applied and secrets redacted, for
+// printing the effective configuration. Durations are rendered as strings
+// ("5m0s").
+func (c *Config) Settings() map[string]any {
+	settings := settingsValue(reflect.ValueOf(*c)).(map[string]any)
+
+	telegram := settings["telegram"].(map[string]any)
+	if c.Telegram.BotToken != "" {
+		telegram["bot_token"] = redactedValue
+	}
+	// Webhook URLs often carry their credential in the path or query
+	if u, err := url.Parse(c.Webhook.URL); err == nil && (u.Path != "" || u.RawQuery != "" || u.User != nil) {
+		settings["webhook"].(map[string]any)["url"] = u.Scheme + "://" + u.Host + "/" + redactedValue
+	}
+	return settings
+}
+
+// settingsValue converts v to plain maps, slices and scalars, naming struct
+// fields by their mapstructure tags.
+func settingsValue(v reflect.Value) any {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		m := make(map[string]any, v.NumField())
+		for i := range v.NumField() {
+			if key := v.Type().Field(i).Tag.Get("mapstructure"); key != "" {
+				m[key] = settingsValue(v.Field(i))
+			}
+		}
+		return m
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			m[fmt.Sprint(iter.Key().Interface())] = settingsValue(iter.Value())
+		}
+		return m
+	case reflect.Slice:
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = settingsValue(v.Index(i))
+		}
+		return s
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return settingsValue(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -143,3 +145,155 @@ func TestValidateErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_Overlays(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.yaml")
+	override := filepath.Join(dir, "prod.yaml")
+	if err := os.WriteFile(base, []byte(`
+polymarket:
+  poll_interval: 5m
+  categories: [politics, sports]
+monitor:
+  sensitivity: 0.5
+  top_k: 5
+telegram:
+  enabled: false
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(override, []byte(`
+polymarket:
+  categories: [crypto]
+monitor:
+  top_k: 3
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(base, override)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Polymarket.PollInterval != 5*time.Minute {
+		t.Errorf("poll_interval = %v, want 5m kept from the base file", cfg.Polymarket.PollInterval)
+	}
+	if len(cfg.Polymarket.Categories) != 1 || cfg.Polymarket.Categories[0] != "crypto" {
+		t.Errorf("categories = %v, want [crypto] replaced by the overlay", cfg.Polymarket.Categories)
+	}
+	if cfg.Monitor.TopK != 3 || cfg.Monitor.Sensitivity != 0.5 {
+		t.Errorf("top_k=%d sensitivity=%v, want 3 from the overlay and 0.5 from the base", cfg.Monitor.TopK, cfg.Monitor.Sensitivity)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	t.Setenv("POLY_ORACLE_MONITOR_TOP_K", "7")
+	cfg, err = Load(base, override)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Monitor.TopK != 7 {
+		t.Errorf("top_k = %d, want 7 from the environment over every file", cfg.Monitor.TopK)
+	}
+
+	if _, err := Load(base, filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing overlay file")
+	}
+}
+
+func TestLoad_ScoreWeights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+polymarket:
+  poll_interval: 5m
+  categories: [politics]
+monitor:
+  weights:
+    volume_exp: 2
+telegram:
+  enabled: false
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if want := (ScoreWeights{KLExp: 1, VolumeExp: 2, SNRExp: 1, TCExp: 1}); cfg.Monitor.Weights != want {
+		t.Errorf("weights = %+v, want %+v (unset exponents default to 1)", cfg.Monitor.Weights, want)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	cfg.Monitor.Weights.TCExp = -0.5
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "monitor.weights") {
+		t.Errorf("Validate() = %v, want a monitor.weights error", err)
+	}
+}
+
+func TestValidate_NotifyResolvedNeedsIncludeClosed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+polymarket:
+  poll_interval: 5m
+  categories: [politics]
+monitor:
+  notify_resolved: true
+telegram:
+  enabled: false
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "polymarket.include_closed") {
+		t.Errorf("Validate() = %v, want a polymarket.include_closed error", err)
+	}
+	cfg.Polymarket.IncludeClosed = true
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate failed with include_closed set: %v", err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(`
+polymarket:
+  poll_interval: 5m
+telegram:
+  bot_token: "123:secret"
+webhook:
+  url: https://hooks.example.com/services/T00/secret
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	settings := cfg.Settings()
+
+	polymarket := settings["polymarket"].(map[string]any)
+	if got := polymarket["poll_interval"]; got != "5m0s" {
+		t.Errorf("poll_interval = %v, want 5m0s", got)
+	}
+	if got := polymarket["gamma_api_url"]; got != "https://gamma-api.polymarket.com" {
+		t.Errorf("gamma_api_url = %v, want the default", got)
+	}
+	if got := settings["telegram"].(map[string]any)["bot_token"]; got != "REDACTED" {
+		t.Errorf("bot_token = %v, want REDACTED", got)
+	}
+	if got := settings["webhook"].(map[string]any)["url"]; got != "https://hooks.example.com/REDACTED" {
+		t.Errorf("webhook url = %v, want its path redacted", got)
+	}
+	if cfg.Telegram.BotToken != "123:secret" {
+		t.Error("Settings must not modify the config")
+	}
+}
@@ -1,12 +1,20 @@
 // Package logger provides leveled logging with support for debug, info, warn, and error levels.
-// It wraps the standard log package to provide level-based filtering and formatted output.
+// The text format wraps the standard log package; the json format writes one
+// object per line with ts, level, msg and caller fields for log pipelines.
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Level represents a logging level
@@ -26,7 +34,11 @@ const (
 // Logger provides leveled logging
 type Logger struct {
 	level  Level
-	logger *log.Logger
+	logger *log.Logger // text format
+
+	json bool // one JSON object per line instead of text
+	mu   sync.Mutex
+	out  io.Writer
 }
 
 var (
@@ -36,6 +48,10 @@ var (
 
 // Init initializes the default logger with the specified level and format
 func Init(level string, format string) {
+	defaultLogger = newLogger(level, format, os.Stderr)
+}
+
+func newLogger(level, format string, out io.Writer) *Logger {
 	var l Level
 	switch strings.ToLower(level) {
 	case "debug":
@@ -50,55 +66,78 @@ func Init(level string, format string) {
 		l = InfoLevel
 	}
 
-	// Set log flags based on format
-	flags := log.LstdFlags | log.Lmicroseconds
-	if strings.ToLower(format) == "text" {
-		flags |= log.Lshortfile
+	return &Logger{
+		level:  l,
+		logger: log.New(out, "", log.LstdFlags|log.Lmicroseconds|log.Lshortfile),
+		json:   strings.ToLower(format) == "json",
+		out:    out,
 	}
+}
 
-	defaultLogger = &Logger{
-		level:  l,
-		logger: log.New(os.Stderr, "", flags),
+// jsonEntry is one line of json-format output.
+type jsonEntry struct {
+	TS     string `json:"ts"`
+	Level  string `json:"level"`
+	Msg    string `json:"msg"`
+	Caller string `json:"caller,omitempty"`
+}
+
+// output writes msg at the named level. calldepth counts frames above
+// output, as in log.Logger.Output: 2 is the caller of the public function.
+func (l *Logger) output(calldepth int, level, msg string) {
+	if !l.json {
+		_ = l.logger.Output(calldepth+1, "["+level+"] "+msg)
+		return
+	}
+	e := jsonEntry{
+		TS:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level: strings.ToLower(level),
+		Msg:   msg,
+	}
+	if _, file, line, ok := runtime.Caller(calldepth); ok {
+		e.Caller = filepath.Base(file) + ":" + strconv.Itoa(line)
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.out.Write(append(b, '\n'))
 }
 
 // Debug logs a message at DebugLevel
 func Debug(format string, args ...interface{}) {
 	if defaultLogger != nil && defaultLogger.level <= DebugLevel {
-		msg := fmt.Sprintf("[DEBUG] "+format, args...)
-		_ = defaultLogger.logger.Output(2, msg)
+		defaultLogger.output(2, "DEBUG", fmt.Sprintf(format, args...))
 	}
 }
 
 // Info logs a message at InfoLevel
 func Info(format string, args ...interface{}) {
 	if defaultLogger != nil && defaultLogger.level <= InfoLevel {
-		msg := fmt.Sprintf("[INFO] "+format, args...)
-		_ = defaultLogger.logger.Output(2, msg)
+		defaultLogger.output(2, "INFO", fmt.Sprintf(format, args...))
 	}
 }
 
 // Warn logs a message at WarnLevel
 func Warn(format string, args ...interface{}) {
 	if defaultLogger != nil && defaultLogger.level <= WarnLevel {
-		msg := fmt.Sprintf("[WARN] "+format, args...)
-		_ = defaultLogger.logger.Output(2, msg)
+		defaultLogger.output(2, "WARN", fmt.Sprintf(format, args...))
 	}
 }
 
 // Error logs a message at ErrorLevel
 func Error(format string, args ...interface{}) {
 	if defaultLogger != nil && defaultLogger.level <= ErrorLevel {
-		msg := fmt.Sprintf("[ERROR] "+format, args...)
-		_ = defaultLogger.logger.Output(2, msg)
+		defaultLogger.output(2, "ERROR", fmt.Sprintf(format, args...))
 	}
 }
 
 // Fatal logs a message at ErrorLevel and exits
 func Fatal(format string, args ...interface{}) {
-	msg := fmt.Sprintf("[FATAL] "+format, args...)
 	if defaultLogger != nil {
-		_ = defaultLogger.logger.Output(2, msg)
+		defaultLogger.output(2, "FATAL", fmt.Sprintf(format, args...))
 	}
 	// Use os.Exit directly instead of log.Fatal to avoid double-logging.
 	// The message has already been written to defaultLogger above.
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(prev *Logger) { defaultLogger = prev }(defaultLogger)
+	defaultLogger = newLogger("info", "json", &buf)
+
+	Debug("hidden")
+	Info("fetched %d events from \"%s\"\nnext line", 3, "crypto")
+	Warn("slow")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	var e struct{ TS, Level, Msg, Caller string }
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatalf("line is not JSON: %v\n%s", err, lines[0])
+	}
+	if e.Level != "info" || e.Msg != "fetched 3 events from \"crypto\"\nnext line" {
+		t.Errorf("got level %q msg %q", e.Level, e.Msg)
+	}
+	if !strings.HasPrefix(e.Caller, "logger_test.go:") {
+		t.Errorf("caller = %q, want logger_test.go:<line>", e.Caller)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, e.TS); err != nil {
+		t.Errorf("ts %q: %v", e.TS, err)
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(prev *Logger) { defaultLogger = prev }(defaultLogger)
+	defaultLogger = newLogger("debug", "text", &buf)
+
+	Debug("hello %s", "world")
+
+	if got := buf.String(); !strings.Contains(got, "logger_test.go:") || !strings.Contains(got, "[DEBUG] hello world") {
+		t.Errorf("unexpected text output: %q", got)
+	}
+}
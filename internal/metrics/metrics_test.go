@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_Exposition(t *testing.T) {
+	r := NewRegistry()
+	alerts := r.NewCounterVec("test_alerts_total", "Alerts by category.", "category")
+	processed := r.NewGaugeVec("test_markets_processed", "Processed markets.", "category")
+	lag := r.NewGauge("test_write_seconds", "Write time.")
+
+	alerts.Inc("crypto")
+	alerts.Inc("crypto")
+	alerts.Add("world", 3)
+	alerts.Add("world", -1) // ignored: counters never decrease
+	processed.Set("crypto", 42)
+	processed.Set(`we"ird`, 1)
+	lag.Set(0.25)
+
+	var b strings.Builder
+	r.Write(&b)
+	got := b.String()
+
+	want := `# HELP test_alerts_total Alerts by category.
+# TYPE test_alerts_total counter
+test_alerts_total{category="crypto"} 2
+test_alerts_total{category="world"} 3
+# HELP test_markets_processed Processed markets.
+# TYPE test_markets_processed gauge
+test_markets_processed{category="crypto"} 42
+test_markets_processed{category="we\"ird"} 1
+# HELP test_write_seconds Write time.
+# TYPE test_write_seconds gauge
+test_write_seconds 0.25
+`
+	if got != want {
+		t.Errorf("exposition mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCounterAndHistogram_Exposition(t *testing.T) {
+	r := NewRegistry()
+	fetched := r.NewCounter("test_fetched_total", "Fetched.")
+	cycle := r.NewHistogram("test_cycle_seconds", "Cycle time.", []float64{1, 5})
+
+	fetched.Add(10)
+	fetched.Inc()
+	fetched.Add(-3) // ignored
+	cycle.Observe(0.5)
+	cycle.Observe(1) // le is inclusive
+	cycle.Observe(3)
+	cycle.Observe(60)
+
+	var b strings.Builder
+	r.Write(&b)
+	want := `# HELP test_fetched_total Fetched.
+# TYPE test_fetched_total counter
+test_fetched_total 11
+# HELP test_cycle_seconds Cycle time.
+# TYPE test_cycle_seconds histogram
+test_cycle_seconds_bucket{le="1"} 2
+test_cycle_seconds_bucket{le="5"} 3
+test_cycle_seconds_bucket{le="+Inf"} 4
+test_cycle_seconds_sum 64.5
+test_cycle_seconds_count 4
+`
+	if got := b.String(); got != want {
+		t.Errorf("exposition mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("test_total", "Test.", "l").Inc("a")
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `test_total{l="a"} 1`) {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+}
+
+func TestCategoryLabel(t *testing.T) {
+	configured := []string{"crypto", "world"}
+	tests := []struct {
+		category string
+		want     string
+	}{
+		{"crypto", "crypto"},
+		{"world", "world"},
+		{"sports", OtherCategory},
+		{"", OtherCategory},
+	}
+	for _, tt := range tests {
+		if got := CategoryLabel(tt.category, configured); got != tt.want {
+			t.Errorf("CategoryLabel(%q) = %q, want %q", tt.category, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package metrics
+
+// Default is the registry served on the /metrics endpoint.
+var Default = NewRegistry()
+
+// OtherCategory is the label used for categories outside the configured set,
+// keeping label cardinality bounded.
+const OtherCategory = "other"
+
+var (
+	// AlertsTotal counts notified market alerts by category.
+	AlertsTotal = Default.NewCounterVec(
+		"polyoracle_alerts_total",
+		"Market alerts that passed scoring and cooldown filters, by category.",
+		"category",
+	)
+
+	// AlertsEmitted counts alert groups that passed the quality bar and were
+	// handed to the live stream and notifiers.
+	AlertsEmitted = Default.NewCounter(
+		"polyoracle_alerts_emitted_total",
+		"Alert groups that passed the quality bar and were emitted.",
+	)
+
+	// CycleDuration observes the wall time of each monitoring cycle.
+	CycleDuration = Default.NewHistogram(
+		"polyoracle_cycle_duration_seconds",
+		"Wall time of each monitoring cycle, including failed cycles.",
+		[]float64{0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	)
+
+	// MarketsFetched counts markets returned by the Polymarket API.
+	MarketsFetched = Default.NewCounter(
+		"polyoracle_markets_fetched_total",
+		"Markets returned by the Polymarket API across all cycles.",
+	)
+
+	// APIErrors counts failed Polymarket API attempts by kind: transport,
+	// rate_limited (429, retried), server (5xx, retried) or client (other 4xx,
+	// not retried).
+	APIErrors = Default.NewCounterVec(
+		"polyoracle_api_errors_total",
+		"Failed Polymarket API request attempts, by kind.",
+		"kind",
+	)
+
+	// ConsecutiveFailures is the number of monitoring cycles that have failed
+	// in a row; 0 after a successful cycle.
+	ConsecutiveFailures = Default.NewGauge(
+		"polyoracle_consecutive_failures",
+		"Monitoring cycles that have failed in a row.",
+	)
+
+	// MarketsProcessed is the number of markets processed in the most recent cycle, by category.
+	MarketsProcessed = Default.NewGaugeVec(
+		"polyoracle_markets_processed",
+		"Markets stored and snapshotted in the most recent monitoring cycle, by category.",
+		"category",
+	)
+
+	// SnapshotWriteSeconds is the time spent writing snapshots in the most recent cycle.
+	SnapshotWriteSeconds = Default.NewGauge(
+		"polyoracle_snapshot_write_seconds",
+		"Total time spent writing market snapshots in the most recent monitoring cycle.",
+	)
+
+	// SnapshotWriteFailures is the number of failed snapshot writes in the most recent cycle.
+	SnapshotWriteFailures = Default.NewGauge(
+		"polyoracle_snapshot_write_failures",
+		"Markets whose snapshot write failed in the most recent monitoring cycle.",
+	)
+)
+
+// CategoryLabel returns category when it is one of the configured categories,
+// and OtherCategory otherwise.
+func CategoryLabel(category string, configured []string) string {
+	for _, c := range configured {
+		if c == category {
+			return category
+		}
+	}
+	return OtherCategory
+}
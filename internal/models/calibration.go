@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// CalibrationRecord pairs a resolved market's Yes probability at a fixed lead
+// time before resolution with the outcome, for Brier scoring.
+type CalibrationRecord struct {
+	MarketID    string        `json:"market_id"` // composite ID
+	Category    string        `json:"category"`
+	Predicted   float64       `json:"predicted"` // Yes probability LeadTime before resolution
+	Outcome     int           `json:"outcome"`   // 1 = resolved Yes, 0 = No
+	LeadTime    time.Duration `json:"lead_time"`
+	PredictedAt time.Time     `json:"predicted_at"` // timestamp of the snapshot Predicted was taken from
+	ResolvedAt  time.Time     `json:"resolved_at"`
+}
+
+// BrierScore returns the squared error of the prediction: 0 is a perfect
+// forecast, 0.25 a coin flip, 1 a confident miss.
+func (r CalibrationRecord) BrierScore() float64 {
+	d := r.Predicted - float64(r.Outcome)
+	return d * d
+}
+
+// CalibrationSummary aggregates the calibration records of one category.
+type CalibrationSummary struct {
+	Category      string  `json:"category"`
+	Markets       int     `json:"markets"`
+	Brier         float64 `json:"brier"`          // mean Brier score
+	MeanPredicted float64 `json:"mean_predicted"` // mean forecast Yes probability
+	ResolvedYes   float64 `json:"resolved_yes"`   // fraction that resolved Yes
+}
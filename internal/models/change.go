@@ -3,6 +3,7 @@ package models
 import (
 	"errors"
 	"math"
+	"strings"
 	"time"
 )
 
@@ -27,19 +28,126 @@ type Change struct {
 	NewProbability  float64       `json:"new_probability"`
 	TimeWindow      time.Duration `json:"time_window"` // Duration over which change was detected
 	DetectedAt      time.Time     `json:"detected_at"`
-	Notified        bool          `json:"notified"`               // Whether notification was sent
-	SignalScore     float64       `json:"signal_score,omitempty"` // composite score from scoring algorithm; 0 = unscored
+	Notified        bool          `json:"notified"`                // Whether notification was sent
+	SignalScore     float64       `json:"signal_score,omitempty"`  // composite score from scoring algorithm; 0 = unscored
+	Kind            string        `json:"kind,omitempty"`          // KindProbability (default when empty), KindLiquidityDrop, KindUncertainty, KindInconsistency or KindResolved; comma-separated when merged
+	OldLiquidity    float64       `json:"old_liquidity,omitempty"` // Liquidity baseline in USD (liquidity_drop only)
+	NewLiquidity    float64       `json:"new_liquidity,omitempty"` // Current liquidity in USD (liquidity_drop only)
+	Liquidity       float64       `json:"liquidity,omitempty"`     // Market liquidity in USD at detection (display only, not stored)
+	Volume24hr      float64       `json:"volume_24hr,omitempty"`   // Market 24h volume in USD at detection (display only, not stored)
+
+	// Trend holds the market's most recent stored probabilities, oldest first,
+	// for the notification sparkline (display only, not stored).
+	Trend []float64 `json:"trend,omitempty"`
+
+	// Description is the parent event's description, for notification context
+	// (display only, not stored with the change).
+	Description string `json:"description,omitempty"`
+
+	// LastAlertAt is when the market was last alerted before this change, from
+	// the alert history; zero = never (display only, not stored).
+	LastAlertAt time.Time `json:"last_alert_at,omitzero"`
+
+	// Side is the outcome the change is framed from: SideNo for markets
+	// configured to track their No side (monitor track_side), otherwise empty
+	// (Yes). The probabilities above always stay on the Yes side; see Sided.
+	// Display only, not stored.
+	Side string `json:"side,omitempty"`
+
+	// RelatedQuestion is the ladder rung this market is mispriced against
+	// (inconsistency only).
+	RelatedQuestion string `json:"related_question,omitempty"`
+
+	// Outcome is SideYes or SideNo for a resolved change whose final price
+	// settled at that side; empty when it closed elsewhere (e.g. a 50/50
+	// void). Display only, not stored.
+	Outcome string `json:"outcome,omitempty"`
+
+	// Components holds the inputs behind SignalScore; nil = unscored.
+	Components *ScoreComponents `json:"components,omitempty"`
+}
+
+// ScoreComponents records every input of a change's composite score at
+// detection time, so a stored alert can be audited after the snapshot history
+// it was computed from has rotated away. SignalScore = KL × VolumeWeight × SNR × TC.
+type ScoreComponents struct {
+	KL               float64 `json:"kl"`                // divergence of the move: KL in nats unless monitor.divergence_metric says otherwise
+	VolumeWeight     float64 `json:"volume_weight"`     // log2(1 + Volume24hr/VolumeRef), floored at 0.1
+	SNR              float64 `json:"snr"`               // clamped signal-to-noise ratio
+	TC               float64 `json:"tc"`                // trajectory consistency over the window
+	Sigma            float64 `json:"sigma"`             // per-interval Δp volatility used for SNR; 0 = too little history
+	HistorySnapshots int     `json:"history_snapshots"` // snapshots σ was estimated from
+	WindowSnapshots  int     `json:"window_snapshots"`  // snapshots in the detection window (TC input)
+	Volume24hr       float64 `json:"volume_24hr"`       // market 24h volume in USD
+	VolumeRef        float64 `json:"volume_ref"`        // reference volume (polymarket.volume_24hr_min)
+	Liquidity        float64 `json:"liquidity"`         // event liquidity in USD
+	Threshold        float64 `json:"threshold"`         // score bar the change was compared against
+}
+
+// Change kinds. An empty Kind is treated as KindProbability.
+const (
+	KindProbability   = "probability"
+	KindLiquidityDrop = "liquidity_drop"
+	KindUncertainty   = "uncertainty"   // probability converging toward 0.50
+	KindInconsistency = "inconsistency" // ladder rung priced above a rung it implies
+	KindExtreme       = "extreme"       // secondary tag: composite score at or above the score ceiling
+	KindResolved      = "resolved"      // market closed; NewProbability is its final price
+)
+
+// Outcome sides a change can be framed from. An empty Side is treated as SideYes.
+const (
+	SideYes = "yes"
+	SideNo  = "no"
+)
+
+// Sided returns the change as seen from its Side: for SideNo the probabilities
+// and trend are inverted (1 − p) and the direction flipped. Other changes are
+// returned unchanged. Magnitude is the same from either side.
+func (c Change) Sided() Change {
+	if c.Side != SideNo {
+		return c
+	}
+	c.OldProbability = 1 - c.OldProbability
+	c.NewProbability = 1 - c.NewProbability
+	switch c.Direction {
+	case "increase":
+		c.Direction = "decrease"
+	case "decrease":
+		c.Direction = "increase"
+	}
+	if c.Trend != nil {
+		trend := make([]float64, len(c.Trend))
+		for i, p := range c.Trend {
+			trend[i] = 1 - p
+		}
+		c.Trend = trend
+	}
+	return c
+}
+
+// Kinds returns the change's kind tags. A change merged from several detectors
+// carries all of their kinds, primary (highest-scoring) first.
+func (c *Change) Kinds() []string {
+	if c.Kind == "" {
+		return []string{KindProbability}
+	}
+	return strings.Split(c.Kind, ",")
 }
 
 // Event represents a Polymarket event — a group of related markets sharing the
 // same event page and URL. Multiple markets from the same event are collapsed
 // into one Event so they consume only one slot in top-k notifications.
 type Event struct {
-	ID        string   // Polymarket event ID
-	Title     string   // Event title
-	URL       string   // URL to the Polymarket event page
-	BestScore float64  // Highest signal score among markets in this event
-	Markets   []Change // Individual market changes, sorted by score desc
+	ID        string   `json:"id"`         // Polymarket event ID
+	Title     string   `json:"title"`      // Event title
+	URL       string   `json:"url"`        // URL to the Polymarket event page
+	BestScore float64  `json:"best_score"` // Highest signal score among markets in this event
+	Markets   []Change `json:"markets"`    // Individual market changes, sorted by score desc
+	// Category is the primary category of the group's top market.
+	Category string `json:"category,omitempty"`
+	// Related holds the titles of groups collapsed into this one as
+	// near-duplicates (monitor dedup_similar_titles). Their markets are dropped.
+	Related []string `json:"related,omitempty"`
 }
 
 // Validate checks that all change fields are valid
@@ -54,10 +162,28 @@ func (c *Change) Validate() error {
 		return errors.New("magnitude must be between 0.0 and 1.0")
 	}
 
-	// Verify magnitude equals absolute difference
-	expectedMagnitude := math.Abs(c.NewProbability - c.OldProbability)
-	if math.Abs(c.Magnitude-expectedMagnitude) > 0.001 {
-		return errors.New("magnitude must equal |new_probability - old_probability|")
+	kinds := c.Kinds()
+	for _, k := range kinds {
+		if k != KindProbability && k != KindLiquidityDrop && k != KindUncertainty && k != KindInconsistency && k != KindExtreme && k != KindResolved {
+			return errors.New("kind must be 'probability', 'liquidity_drop', 'uncertainty', 'inconsistency', 'resolved' or 'extreme'")
+		}
+	}
+	if kinds[0] == KindExtreme {
+		return errors.New("kind 'extreme' only tags another kind and cannot be primary")
+	}
+	// Magnitude semantics follow the primary kind
+	switch kinds[0] {
+	case KindProbability, KindUncertainty, KindInconsistency, KindResolved:
+		// Verify magnitude equals absolute difference
+		expectedMagnitude := math.Abs(c.NewProbability - c.OldProbability)
+		if math.Abs(c.Magnitude-expectedMagnitude) > 0.001 {
+			return errors.New("magnitude must equal |new_probability - old_probability|")
+		}
+	case KindLiquidityDrop:
+		// Magnitude is the fractional drop from the liquidity baseline
+		if c.OldLiquidity <= 0 || c.NewLiquidity < 0 {
+			return errors.New("liquidity drop requires positive old liquidity and non-negative new liquidity")
+		}
 	}
 
 	if c.Direction != "increase" && c.Direction != "decrease" {
@@ -69,7 +195,7 @@ func (c *Change) Validate() error {
 	if c.NewProbability < 0.0 || c.NewProbability > 1.0 {
 		return errors.New("new probability must be between 0.0 and 1.0")
 	}
-	if c.DetectedAt.After(time.Now()) {
+	if inFuture(c.DetectedAt) {
 		return errors.New("detected at must not be in the future")
 	}
 	return nil
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DefaultFutureTimestampTolerance is the clock skew accepted when no tolerance
+// is configured.
+const DefaultFutureTimestampTolerance = 5 * time.Second
+
+// futureTolerance holds the current tolerance in nanoseconds.
+var futureTolerance atomic.Int64
+
+func init() {
+	futureTolerance.Store(int64(DefaultFutureTimestampTolerance))
+}
+
+// SetFutureTimestampTolerance sets how far ahead of the local clock a timestamp
+// may be and still count as "not in the future". Validation rejects future
+// timestamps to catch corrupt data, but hosts whose clocks disagree by a few
+// seconds (or a backup imported from a machine running slightly ahead) would
+// otherwise fail it spuriously. Negative values are treated as 0.
+func SetFutureTimestampTolerance(d time.Duration) {
+	futureTolerance.Store(int64(max(d, 0)))
+}
+
+// FutureTimestampTolerance returns the configured clock skew tolerance.
+func FutureTimestampTolerance() time.Duration {
+	return time.Duration(futureTolerance.Load())
+}
+
+// inFuture reports whether t is later than now plus the skew tolerance.
+func inFuture(t time.Time) bool {
+	return t.After(time.Now().Add(FutureTimestampTolerance()))
+}
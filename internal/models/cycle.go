@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// CycleSummaryVersion is the current CycleSummary schema version. It is bumped
+// whenever a field is renamed, removed or changes meaning; adding a field does
+// not bump it.
+const CycleSummaryVersion = 1
+
+// Skip reasons counted in CycleSummary.Skipped.
+const (
+	SkipStoreError    = "store_error"    // market could not be added or updated
+	SkipSnapshotError = "snapshot_error" // snapshot write failed
+	SkipResolving     = "resolving"      // pinned at 0/1, excluded from detection
+	SkipCooldown      = "cooldown"       // alert suppressed as recently sent
+	SkipAlertBudget   = "alert_budget"   // market used its daily alert budget
+	SkipMaintenance   = "maintenance"    // alerted market held back by a maintenance window
+)
+
+// CycleSummary is the machine-readable record of one monitoring cycle, written
+// as a single JSON line (monitor cycle_summary_output) for ingestion by other
+// tools. Counts are of markets unless named otherwise.
+type CycleSummary struct {
+	Version        int            `json:"version"`
+	StartedAt      time.Time      `json:"started_at"`
+	DurationMs     int64          `json:"duration_ms"`
+	MarketsFetched int            `json:"markets_fetched"`
+	MarketsNew     int            `json:"markets_new"`
+	MarketsUpdated int            `json:"markets_updated"`
+	Changes        int            `json:"changes"`      // changes above the detection floor
+	AlertGroups    int            `json:"alert_groups"` // event groups that passed scoring and filters
+	AlertMarkets   int            `json:"alert_markets"`
+	Maintenance    bool           `json:"maintenance"`       // a maintenance window was active
+	Skipped        map[string]int `json:"skipped,omitempty"` // Skip* reason → count
+	Error          string         `json:"error,omitempty"`   // set when the cycle failed
+}
+
+// Skip adds n to the count for reason; n ≤ 0 is ignored.
+func (s *CycleSummary) Skip(reason string, n int) {
+	if n <= 0 {
+		return
+	}
+	if s.Skipped == nil {
+		s.Skipped = make(map[string]int)
+	}
+	s.Skipped[reason] += n
+}
@@ -77,7 +77,7 @@ func (m *Market) Validate() error {
 	if m.Liquidity < 0 {
 		return errors.New("liquidity must not be negative")
 	}
-	if m.LastUpdated.After(time.Now()) {
+	if inFuture(m.LastUpdated) {
 		return errors.New("last updated must not be in the future")
 	}
 	if m.CreatedAt.After(m.LastUpdated) {
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
 	"time"
 )
@@ -213,6 +215,68 @@ func TestChangeValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid liquidity drop",
+			change: Change{
+				ID:             "change-222",
+				EventID:        "event-123",
+				EventTitle:     "Test",
+				Kind:           KindLiquidityDrop,
+				Magnitude:      0.60,
+				Direction:      "decrease",
+				OldProbability: 0.55,
+				NewProbability: 0.55,
+				OldLiquidity:   100000,
+				NewLiquidity:   40000,
+				DetectedAt:     time.Now(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "liquidity drop without baseline",
+			change: Change{
+				ID:             "change-333",
+				EventID:        "event-123",
+				EventTitle:     "Test",
+				Kind:           KindLiquidityDrop,
+				Magnitude:      0.60,
+				Direction:      "decrease",
+				OldProbability: 0.55,
+				NewProbability: 0.55,
+				DetectedAt:     time.Now(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "merged kinds validate against the primary kind",
+			change: Change{
+				ID:             "change-555",
+				EventID:        "event-123",
+				EventTitle:     "Test",
+				Kind:           "probability,liquidity_drop",
+				Magnitude:      0.10,
+				Direction:      "increase",
+				OldProbability: 0.60,
+				NewProbability: 0.70,
+				DetectedAt:     time.Now(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown kind",
+			change: Change{
+				ID:             "change-444",
+				EventID:        "event-123",
+				EventTitle:     "Test",
+				Kind:           "volume_spike",
+				Magnitude:      0.10,
+				Direction:      "increase",
+				OldProbability: 0.60,
+				NewProbability: 0.70,
+				DetectedAt:     time.Now(),
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -224,3 +288,89 @@ func TestChangeValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeSided(t *testing.T) {
+	yes := Change{ID: "c", EventID: "e:m", Direction: "increase", OldProbability: 0.2, NewProbability: 0.3, Magnitude: 0.1, Trend: []float64{0.2, 0.25, 0.3}}
+	if got := yes.Sided(); got.OldProbability != 0.2 || got.Direction != "increase" {
+		t.Errorf("Yes-side change altered: %+v", got)
+	}
+
+	no := yes
+	no.Side = SideNo
+	got := no.Sided()
+	if math.Abs(got.OldProbability-0.8) > 1e-9 || math.Abs(got.NewProbability-0.7) > 1e-9 {
+		t.Errorf("No-side probabilities = %v → %v, want 0.8 → 0.7", got.OldProbability, got.NewProbability)
+	}
+	if got.Direction != "decrease" {
+		t.Errorf("No-side direction = %q, want decrease", got.Direction)
+	}
+	if math.Abs(got.Trend[0]-0.8) > 1e-9 || no.Trend[0] != 0.2 {
+		t.Errorf("trend = %v (original %v), want inverted copy", got.Trend, no.Trend)
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("sided change invalid: %v", err)
+	}
+}
+
+func TestCycleSummaryJSON(t *testing.T) {
+	s := CycleSummary{Version: CycleSummaryVersion, StartedAt: time.Date(2025, 3, 1, 14, 30, 0, 0, time.UTC), MarketsFetched: 3}
+	s.Skip(SkipCooldown, 2)
+	s.Skip(SkipCooldown, 1)
+	s.Skip(SkipAlertBudget, 0) // ignored
+
+	got, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"version":1,"started_at":"2025-03-01T14:30:00Z","duration_ms":0,"markets_fetched":3,"markets_new":0,` +
+		`"markets_updated":0,"changes":0,"alert_groups":0,"alert_markets":0,"maintenance":false,"skipped":{"cooldown":3}}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestBasisPoints(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want int
+	}{
+		{0, 0},
+		{1, 10000},
+		{0.5, 5000},
+		{0.12345, 1235},
+		{0.00004, 0},
+	}
+	for _, tt := range tests {
+		if got := ToBasisPoints(tt.p); got != tt.want {
+			t.Errorf("ToBasisPoints(%v) = %d, want %d", tt.p, got, tt.want)
+		}
+		if got := ToBasisPoints(FromBasisPoints(tt.want)); got != tt.want {
+			t.Errorf("round trip of %d bp = %d", tt.want, got)
+		}
+	}
+}
+
+func TestFutureTimestampTolerance(t *testing.T) {
+	defer SetFutureTimestampTolerance(FutureTimestampTolerance())
+
+	snap := Snapshot{
+		ID: "snap-123", EventID: "event-123", YesProbability: 0.75, NoProbability: 0.25,
+		Timestamp: time.Now().Add(2 * time.Second), Source: "polymarket-gamma-api",
+	}
+
+	SetFutureTimestampTolerance(DefaultFutureTimestampTolerance)
+	if err := snap.Validate(); err != nil {
+		t.Errorf("2s skew should pass with the default tolerance: %v", err)
+	}
+
+	SetFutureTimestampTolerance(0)
+	if err := snap.Validate(); err == nil {
+		t.Error("2s skew should fail with zero tolerance")
+	}
+
+	SetFutureTimestampTolerance(time.Minute)
+	snap.Timestamp = time.Now().Add(2 * time.Minute)
+	if err := snap.Validate(); err == nil {
+		t.Error("skew beyond the tolerance should fail")
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"math"
 	"time"
 )
 
@@ -16,6 +17,8 @@ type Snapshot struct {
 	EventID        string    `json:"event_id"`
 	YesProbability float64   `json:"yes_probability"`
 	NoProbability  float64   `json:"no_probability"`
+	Volume24hr     float64   `json:"volume_24hr,omitempty"` // market's 24h volume at the time; 0 in older snapshots
+	Liquidity      float64   `json:"liquidity,omitempty"`   // event liquidity at the time; 0 in older snapshots
 	Timestamp      time.Time `json:"timestamp"`
 	Source         string    `json:"source"` // Data source identifier (e.g., "polymarket-gamma-api")
 }
@@ -34,7 +37,7 @@ func (s *Snapshot) Validate() error {
 	if s.NoProbability < 0.0 || s.NoProbability > 1.0 {
 		return errors.New("no probability must be between 0.0 and 1.0")
 	}
-	if s.Timestamp.After(time.Now()) {
+	if inFuture(s.Timestamp) {
 		return errors.New("timestamp must not be in the future")
 	}
 	if s.Source == "" {
@@ -42,3 +45,19 @@ func (s *Snapshot) Validate() error {
 	}
 	return nil
 }
+
+// BasisPointsPerUnit is the number of basis points in a probability of 1.0.
+const BasisPointsPerUnit = 10000
+
+// ToBasisPoints converts a probability (0–1) to integer basis points (0–10000),
+// rounding to the nearest basis point.
+func ToBasisPoints(p float64) int {
+	return int(math.Round(p * BasisPointsPerUnit))
+}
+
+// FromBasisPoints converts integer basis points back to a probability.
+// The result is deterministic, so probabilities that round to the same basis
+// point compare exactly equal.
+func FromBasisPoints(bp int) float64 {
+	return float64(bp) / BasisPointsPerUnit
+}
@@ -0,0 +1,330 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ScoreExprVars lists the inputs a score expression may reference. score is
+// the built-in composite (kl × volume_weight × snr × tc); the others are its
+// factors and the market context they were computed from.
+var ScoreExprVars = []string{"score", "kl", "volume_weight", "snr", "tc", "sigma", "magnitude", "volume24hr", "liquidity"}
+
+// scoreExprFuncs are the functions a score expression may call, by arity.
+var scoreExprFuncs = map[string]int{
+	"abs": 1, "sqrt": 1, "log": 1, "exp": 1,
+	"min": 2, "max": 2, "pow": 2,
+}
+
+// ScoreExpr is a parsed user-defined scoring formula: arithmetic (+ - * / ^ and
+// parentheses) over number literals, ScoreExprVars and a few math functions.
+type ScoreExpr struct {
+	src  string
+	root exprNode
+}
+
+// ParseScoreExpr parses src, rejecting syntax errors and unknown names up front
+// so a bad formula fails at startup rather than on the first scored market.
+// An empty src returns nil (use the built-in formula).
+func ParseScoreExpr(src string) (*ScoreExpr, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	p := &exprParser{src: src}
+	p.next()
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.tok.text, p.tok.pos)
+	}
+	return &ScoreExpr{src: src, root: root}, nil
+}
+
+// String returns the expression source.
+func (e *ScoreExpr) String() string { return e.src }
+
+// Eval evaluates the expression with the given inputs (missing ones read as 0).
+// It returns an error if the result is NaN or infinite.
+func (e *ScoreExpr) Eval(vars map[string]float64) (float64, error) {
+	v := e.root.eval(vars)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("score expression %q evaluated to %v", e.src, v)
+	}
+	return v, nil
+}
+
+// ─── Evaluation tree ─────────────────────────────────────────────────────────
+
+type exprNode interface {
+	eval(vars map[string]float64) float64
+}
+
+type numNode float64
+
+func (n numNode) eval(map[string]float64) float64 { return float64(n) }
+
+type varNode string
+
+func (n varNode) eval(vars map[string]float64) float64 { return vars[string(n)] }
+
+type negNode struct{ x exprNode }
+
+func (n negNode) eval(vars map[string]float64) float64 { return -n.x.eval(vars) }
+
+type binNode struct {
+	op   byte
+	l, r exprNode
+}
+
+func (n binNode) eval(vars map[string]float64) float64 {
+	l, r := n.l.eval(vars), n.r.eval(vars)
+	switch n.op {
+	case '+':
+		return l + r
+	case '-':
+		return l - r
+	case '*':
+		return l * r
+	case '/':
+		return l / r
+	default: // '^'
+		return math.Pow(l, r)
+	}
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+func (n callNode) eval(vars map[string]float64) float64 {
+	a := n.args[0].eval(vars)
+	switch n.name {
+	case "abs":
+		return math.Abs(a)
+	case "sqrt":
+		return math.Sqrt(a)
+	case "log":
+		return math.Log(a)
+	case "exp":
+		return math.Exp(a)
+	}
+	b := n.args[1].eval(vars)
+	switch n.name {
+	case "min":
+		return math.Min(a, b)
+	case "max":
+		return math.Max(a, b)
+	default: // "pow"
+		return math.Pow(a, b)
+	}
+}
+
+// ─── Parser ──────────────────────────────────────────────────────────────────
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokNum
+	tokIdent
+	tokOp // one of + - * / ^ ( ) ,
+	tokBad
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// exprParser is a recursive-descent parser. Precedence, lowest first: + -,
+// * /, unary minus, ^ (right-associative).
+type exprParser struct {
+	src string
+	off int
+	tok token
+}
+
+func (p *exprParser) next() {
+	for p.off < len(p.src) && unicode.IsSpace(rune(p.src[p.off])) {
+		p.off++
+	}
+	start := p.off
+	if p.off >= len(p.src) {
+		p.tok = token{kind: tokEOF, text: "end of expression", pos: start}
+		return
+	}
+	c := p.src[p.off]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		for p.off < len(p.src) && (isDigit(p.src[p.off]) || p.src[p.off] == '.') {
+			p.off++
+		}
+		// Exponent: 1e6, 2.5E-3
+		if p.off < len(p.src) && (p.src[p.off] == 'e' || p.src[p.off] == 'E') {
+			end := p.off + 1
+			if end < len(p.src) && (p.src[end] == '+' || p.src[end] == '-') {
+				end++
+			}
+			if end < len(p.src) && isDigit(p.src[end]) {
+				for end < len(p.src) && isDigit(p.src[end]) {
+					end++
+				}
+				p.off = end
+			}
+		}
+		p.tok = token{kind: tokNum, text: p.src[start:p.off], pos: start}
+	case c == '_' || unicode.IsLetter(rune(c)):
+		for p.off < len(p.src) && (p.src[p.off] == '_' || isDigit(p.src[p.off]) || unicode.IsLetter(rune(p.src[p.off]))) {
+			p.off++
+		}
+		p.tok = token{kind: tokIdent, text: p.src[start:p.off], pos: start}
+	case strings.IndexByte("+-*/^(),", c) >= 0:
+		p.off++
+		p.tok = token{kind: tokOp, text: string(c), pos: start}
+	default:
+		p.off++
+		p.tok = token{kind: tokBad, text: string(c), pos: start}
+	}
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func (p *exprParser) isOp(ops string) bool {
+	return p.tok.kind == tokOp && strings.Contains(ops, p.tok.text)
+}
+
+func (p *exprParser) expect(op string) error {
+	if !p.isOp(op) {
+		return fmt.Errorf("expected %q at offset %d, got %q", op, p.tok.pos, p.tok.text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	l, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+-") {
+		op := p.tok.text[0]
+		p.next()
+		r, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l = binNode{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*/") {
+		op := p.tok.text[0]
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binNode{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.isOp("-") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x}, nil
+	}
+	return p.parsePower()
+}
+
+func (p *exprParser) parsePower() (exprNode, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("^") {
+		p.next()
+		exp, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binNode{op: '^', l: base, r: exp}, nil
+	}
+	return base, nil
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokNum:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok.text, tok.pos)
+		}
+		p.next()
+		return numNode(v), nil
+	case tokIdent:
+		p.next()
+		if arity, ok := scoreExprFuncs[tok.text]; ok {
+			return p.parseCall(tok, arity)
+		}
+		for _, name := range ScoreExprVars {
+			if tok.text == name {
+				return varNode(name), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown name %q at offset %d (inputs: %s)", tok.text, tok.pos, strings.Join(ScoreExprVars, ", "))
+	case tokOp:
+		if tok.text == "(" {
+			p.next()
+			x, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", tok.text, tok.pos)
+}
+
+func (p *exprParser) parseCall(name token, arity int) (exprNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []exprNode
+	for {
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(args) != arity {
+		return nil, fmt.Errorf("%s at offset %d takes %d argument(s), got %d", name.text, name.pos, arity, len(args))
+	}
+	return callNode{name: name.text, args: args}, nil
+}
@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+func TestParseScoreExpr(t *testing.T) {
+	vars := map[string]float64{"score": 0.5, "kl": 0.2, "snr": 3, "tc": 0.8, "volume_weight": 4, "liquidity": 1e6}
+
+	tests := []struct {
+		src  string
+		want float64
+	}{
+		{"score", 0.5},
+		{"kl * snr * tc", 0.2 * 3 * 0.8},
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"-2 ^ 2", -4},
+		{"2 ^ 3 ^ 2", 512}, // right-associative
+		{"10 / 4 - 1", 1.5},
+		{"kl * min(volume_weight, 2)", 0.4},
+		{"max(score, 1e-3) * log(liquidity / 1e6 + 1)", 0.5 * math.Log(2)},
+		{"pow(snr, 2) + abs(-1) + sqrt(4) + exp(0)", 13},
+		{"sigma", 0}, // inputs not supplied read as 0
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := ParseScoreExpr(tt.src)
+			if err != nil {
+				t.Fatalf("ParseScoreExpr: %v", err)
+			}
+			got, err := e.Eval(vars)
+			if err != nil {
+				t.Fatalf("Eval: %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Eval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, src := range []string{"kl *", "hellinger", "min(kl)", "(kl", "kl snr", "kl # 2", "foo(1)"} {
+		if _, err := ParseScoreExpr(src); err == nil {
+			t.Errorf("ParseScoreExpr(%q) succeeded, want error", src)
+		}
+	}
+
+	if e, err := ParseScoreExpr("  "); e != nil || err != nil {
+		t.Errorf("blank expression = %v, %v; want nil, nil", e, err)
+	}
+
+	e, _ := ParseScoreExpr("kl / (tc - tc)")
+	if _, err := e.Eval(vars); err == nil {
+		t.Error("expected an error for a non-finite result")
+	}
+}
+
+func TestFinalScore_Expr(t *testing.T) {
+	change := models.Change{
+		EventID:    "e:m",
+		Components: &models.ScoreComponents{KL: 0.1, VolumeWeight: 2, SNR: 3, TC: 0.5},
+	}
+
+	builtin := New(mustStorage(t, 100, 50))
+	if got, want := builtin.finalScore(change), CompositeScore(0.1, 2, 3, 0.5); got != want {
+		t.Errorf("built-in finalScore = %v, want %v", got, want)
+	}
+
+	expr, err := ParseScoreExpr("score * 2 + snr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := New(mustStorage(t, 100, 50), Config{ScoreExpr: expr})
+	if got, want := custom.finalScore(change), CompositeScore(0.1, 2, 3, 0.5)*2+3; math.Abs(got-want) > 1e-12 {
+		t.Errorf("expression finalScore = %v, want %v", got, want)
+	}
+
+	nan, _ := ParseScoreExpr("sqrt(-kl)")
+	if got := New(mustStorage(t, 100, 50), Config{ScoreExpr: nan}).finalScore(change); got != 0 {
+		t.Errorf("non-finite expression finalScore = %v, want 0", got)
+	}
+}
@@ -0,0 +1,164 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a span of time during which alerts are suppressed,
+// either a one-off absolute range or a daily/weekly recurring one.
+type MaintenanceWindow struct {
+	spec string
+
+	// Absolute window: [from, to).
+	from, to time.Time
+
+	// Recurring window: minutes since local midnight, [start, end). end may be
+	// ≤ start, in which case the window wraps past midnight. weekday < 0 means
+	// every day; otherwise the window opens on that weekday.
+	recurring  bool
+	weekday    time.Weekday
+	start, end int
+	loc        *time.Location
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseMaintenanceWindows parses window specs, interpreting recurring ones in
+// the named timezone ("" = UTC). Accepted forms:
+//
+//	2026-03-01T02:00:00Z/2026-03-01T04:00:00Z  one-off range (RFC 3339)
+//	02:00-04:00                                every day
+//	sun 22:00-02:00                            weekly, opening on Sunday
+//
+// Recurring ranges may cross midnight. Errors name the offending spec so bad
+// configuration fails at startup.
+func ParseMaintenanceWindows(specs []string, tz string) ([]MaintenanceWindow, error) {
+	loc := time.UTC
+	if tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+		loc = l
+	}
+
+	windows := make([]MaintenanceWindow, 0, len(specs))
+	for _, spec := range specs {
+		w, err := parseMaintenanceWindow(strings.TrimSpace(spec), loc)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %q: %w", spec, err)
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func parseMaintenanceWindow(spec string, loc *time.Location) (MaintenanceWindow, error) {
+	w := MaintenanceWindow{spec: spec, weekday: -1, loc: loc}
+
+	if from, to, ok := strings.Cut(spec, "/"); ok {
+		var err error
+		if w.from, err = time.Parse(time.RFC3339, strings.TrimSpace(from)); err != nil {
+			return w, fmt.Errorf("start: %w", err)
+		}
+		if w.to, err = time.Parse(time.RFC3339, strings.TrimSpace(to)); err != nil {
+			return w, fmt.Errorf("end: %w", err)
+		}
+		if !w.to.After(w.from) {
+			return w, fmt.Errorf("end must be after start")
+		}
+		return w, nil
+	}
+
+	w.recurring = true
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 1:
+	case 2:
+		day, ok := weekdays[strings.ToLower(fields[0])]
+		if !ok {
+			return w, fmt.Errorf("unknown weekday %q (use sun..sat)", fields[0])
+		}
+		w.weekday = day
+		fields = fields[1:]
+	default:
+		return w, fmt.Errorf("expected [weekday] HH:MM-HH:MM or start/end")
+	}
+
+	from, to, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return w, fmt.Errorf("expected HH:MM-HH:MM")
+	}
+	var err error
+	if w.start, err = parseClock(from); err != nil {
+		return w, err
+	}
+	if w.end, err = parseClock(to); err != nil {
+		return w, err
+	}
+	if w.start == w.end {
+		return w, fmt.Errorf("start and end are equal")
+	}
+	return w, nil
+}
+
+// parseClock parses HH:MM into minutes since midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (use HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// String returns the window's spec as configured.
+func (w MaintenanceWindow) String() string { return w.spec }
+
+// Contains reports whether t falls inside the window.
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	if !w.recurring {
+		return !t.Before(w.from) && t.Before(w.to)
+	}
+	local := t.In(w.loc)
+	minute := local.Hour()*60 + local.Minute()
+	day := local.Weekday()
+	if w.start < w.end {
+		return minute >= w.start && minute < w.end && w.opensOn(day)
+	}
+	// Wraps midnight: the evening part opens on the window's day, the early
+	// morning part belongs to the day before.
+	if minute >= w.start {
+		return w.opensOn(day)
+	}
+	return minute < w.end && w.opensOn((day+6)%7)
+}
+
+func (w MaintenanceWindow) opensOn(day time.Weekday) bool {
+	return w.weekday < 0 || w.weekday == day
+}
+
+// ActiveMaintenanceWindow returns the configured maintenance window covering
+// the current time, if any.
+func (m *Monitor) ActiveMaintenanceWindow() (MaintenanceWindow, bool) {
+	now := m.clock.Now()
+	for _, w := range m.cfg.MaintenanceWindows {
+		if w.Contains(now) {
+			return w, true
+		}
+	}
+	return MaintenanceWindow{}, false
+}
+
+// frozen reports whether baseline updates are paused for a maintenance window.
+func (m *Monitor) frozen() bool {
+	if !m.cfg.FreezeStateInMaintenance {
+		return false
+	}
+	_, active := m.ActiveMaintenanceWindow()
+	return active
+}
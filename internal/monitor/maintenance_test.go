@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+func TestParseMaintenanceWindows(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	tests := []struct {
+		spec string
+		tz   string
+		at   time.Time
+		want bool
+	}{
+		{"02:00-04:00", "", time.Date(2026, 3, 4, 2, 0, 0, 0, time.UTC), true},
+		{"02:00-04:00", "", time.Date(2026, 3, 4, 3, 59, 0, 0, time.UTC), true},
+		{"02:00-04:00", "", time.Date(2026, 3, 4, 4, 0, 0, 0, time.UTC), false},
+		{"02:00-04:00", "", time.Date(2026, 3, 4, 1, 59, 0, 0, time.UTC), false},
+		// Recurring windows are read in the configured timezone
+		{"02:00-04:00", "America/New_York", time.Date(2026, 3, 4, 3, 0, 0, 0, ny), true},
+		{"02:00-04:00", "America/New_York", time.Date(2026, 3, 4, 3, 0, 0, 0, time.UTC), false},
+		// Crossing midnight: 2026-03-01 is a Sunday
+		{"23:00-01:00", "", time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC), true},
+		{"23:00-01:00", "", time.Date(2026, 3, 2, 0, 30, 0, 0, time.UTC), true},
+		{"23:00-01:00", "", time.Date(2026, 3, 2, 1, 30, 0, 0, time.UTC), false},
+		{"sun 22:00-02:00", "", time.Date(2026, 3, 1, 22, 30, 0, 0, time.UTC), true},
+		{"Sun 22:00-02:00", "", time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC), true},    // Monday morning tail
+		{"sun 22:00-02:00", "", time.Date(2026, 3, 1, 1, 0, 0, 0, time.UTC), false},   // Sunday morning: Saturday's tail
+		{"sun 22:00-02:00", "", time.Date(2026, 3, 2, 22, 30, 0, 0, time.UTC), false}, // Monday evening
+		{"mon 09:00-10:00", "", time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC), true},
+		{"2026-03-01T02:00:00Z/2026-03-01T04:00:00Z", "", time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC), true},
+		{"2026-03-01T02:00:00Z/2026-03-01T04:00:00Z", "", time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC), false},
+		{"2026-03-01T02:00:00Z/2026-03-01T04:00:00Z", "", time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		windows, err := ParseMaintenanceWindows([]string{tt.spec}, tt.tz)
+		if err != nil {
+			t.Fatalf("ParseMaintenanceWindows(%q): %v", tt.spec, err)
+		}
+		if got := windows[0].Contains(tt.at); got != tt.want {
+			t.Errorf("%q (tz %q) Contains(%v) = %v, want %v", tt.spec, tt.tz, tt.at, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{
+		"", "02:00", "2:00-25:00", "02:00-02:00", "funday 02:00-03:00", "mon tue 02:00-03:00",
+		"2026-03-01T04:00:00Z/2026-03-01T02:00:00Z", "2026-03-01/2026-03-02",
+	} {
+		if _, err := ParseMaintenanceWindows([]string{spec}, ""); err == nil {
+			t.Errorf("ParseMaintenanceWindows(%q) succeeded, want error", spec)
+		}
+	}
+	if _, err := ParseMaintenanceWindows([]string{"02:00-04:00"}, "Mars/Olympus"); err == nil {
+		t.Error("expected an error for an unknown timezone")
+	}
+}
+
+func TestMaintenanceWindow_FreezesBaselines(t *testing.T) {
+	windows, err := ParseMaintenanceWindows([]string{"02:00-04:00"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &fakeClock{now: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)}
+	m := New(mustStorage(t, 100, 50), Config{
+		LiquidityDropFraction:    0.5,
+		CoverageDropFraction:     0.5,
+		CoverageWindow:           12,
+		MaintenanceWindows:       windows,
+		FreezeStateInMaintenance: true,
+		Clock:                    clock,
+	})
+	markets := func(liquidity float64) []models.Market {
+		return []models.Market{{ID: "e:m", EventID: "e", Liquidity: liquidity}}
+	}
+
+	if _, active := m.ActiveMaintenanceWindow(); active {
+		t.Fatal("maintenance window reported active at noon")
+	}
+	for i := 0; i < 4; i++ {
+		m.DetectLiquidityDrops(markets(100000))
+		m.ObserveCoverage(100)
+	}
+
+	clock.now = time.Date(2026, 3, 4, 3, 0, 0, 0, time.UTC)
+	w, active := m.ActiveMaintenanceWindow()
+	if !active || w.String() != "02:00-04:00" {
+		t.Fatalf("ActiveMaintenanceWindow = %v, %v; want 02:00-04:00", w, active)
+	}
+	// Detection still reports the drop, but the baseline does not learn from it
+	for i := 0; i < 20; i++ {
+		drops := m.DetectLiquidityDrops(markets(10000))
+		want := 0
+		if i == 0 {
+			want = 1
+		}
+		if len(drops) != want {
+			t.Fatalf("cycle %d in maintenance: got %d drops, want %d", i, len(drops), want)
+		}
+		m.ObserveCoverage(0)
+	}
+	if st, _ := m.loadLiquidityStat("e"); st.AvgDepth != 100000 || st.Count != 4 {
+		t.Errorf("liquidity baseline = %+v, want untouched (100000 over 4 samples)", st)
+	}
+	if len(m.coverageHistory) != 4 {
+		t.Errorf("coverage history has %d entries, want 4", len(m.coverageHistory))
+	}
+
+	// Without freezing, the window only affects notifications
+	m.cfg.FreezeStateInMaintenance = false
+	m.ObserveCoverage(0)
+	if len(m.coverageHistory) != 5 {
+		t.Errorf("coverage history has %d entries, want 5 with freezing off", len(m.coverageHistory))
+	}
+}
@@ -17,8 +17,15 @@ package monitor
 import (
 	"fmt"
 	"math"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"github.com/rewired-gh/polyoracle/internal/logger"
@@ -33,18 +40,303 @@ type notifiedRecord struct {
 	SentAt    time.Time
 }
 
+// eventDirection keys the event-level cooldown: a Polymarket event ID and
+// the direction its markets were notified in.
+type eventDirection struct {
+	EventID   string
+	Direction string
+}
+
+// Clock supplies the current time. Tests inject a fake to control cooldowns
+// and detection timestamps without sleeping.
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock reads the system clock.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
 // Monitor handles event monitoring and change detection
 type Monitor struct {
-	storage         *storage.Storage
+	storage storage.Store
+	cfg     Config
+	clock   Clock
+
+	// mu guards the per-market state below (everything but the atomic paused
+	// flag), so it can be read while a cycle is updating it.
+	mu sync.RWMutex
+
 	notifiedMarkets map[string]notifiedRecord // key = composite event ID
+
+	notifiedEvents map[eventDirection]time.Time // last send per event and direction; see EventCooldownMultiplier
+
+	coverageHistory []int // processed-market counts of recent cycles, oldest first
+	coverageDropped bool  // true while the current cycle streak is below the coverage floor
+
+	paused atomic.Bool // set by operator commands; read by the polling loop
+
+	scoreStats map[string]*scoreStat // key = composite event ID; used by adaptive thresholds
+
+	liquidityStats map[string]*liquidityStat // key = Polymarket event ID, or composite ID with PerMarketLiquidity
+
+	// Float32 stand-ins for scoreStats and liquidityStats, used instead of
+	// them under Config.CompactState.
+	compactScoreStats     map[string]*compactScoreStat
+	compactLiquidityStats map[string]*compactLiquidityStat
+
+	uncertainFlagged map[string]bool // composite event IDs that qualified last cycle (already alerted)
+
+	tracked map[string]*trackedMarket // key = composite event ID; markets seen by ForgetMissing
+
+	extremeStreaks map[string]int // key = composite event ID; consecutive polls priced at an extreme
+
+	stateUpdated map[string]time.Time // key = composite event ID; last state update, for MaxStates eviction
+
+	inconsistentFlagged map[string]bool // composite event IDs of ladder rungs flagged last cycle (already alerted)
+}
+
+// trackedMarket counts consecutive fetches a known market has been absent from.
+type trackedMarket struct {
+	EventID string
+	Missed  int
+}
+
+// liquidityStat is an exponentially weighted baseline of an event's (or, with
+// Config.PerMarketLiquidity, a market's) liquidity.
+type liquidityStat struct {
+	AvgDepth float64
+	Count    int
+	Dropped  bool // true while liquidity stays below the drop floor
+}
+
+// scoreStat is an exponentially weighted summary of a market's recent composite scores.
+type scoreStat struct {
+	Mean   float64
+	MeanSq float64
+	Count  int
+}
+
+// compactScoreStat is scoreStat narrowed to float32 for Config.CompactState.
+type compactScoreStat struct {
+	Mean   float32
+	MeanSq float32
+	Count  int32
+}
+
+// compactLiquidityStat is liquidityStat narrowed to float32 for Config.CompactState.
+type compactLiquidityStat struct {
+	AvgDepth float32
+	Count    int32
+	Dropped  bool
 }
 
+// Config holds optional monitoring behavior configuration
+type Config struct {
+	// CoverageDropFraction flags a cycle whose processed-market count falls below
+	// this fraction of the rolling average. 0 disables the check.
+	CoverageDropFraction float64
+	// CoverageWindow is the number of recent cycles in the rolling average.
+	CoverageWindow int
+	// AdaptiveThreshold raises each market's score bar to its own running p90,
+	// estimated from an EWMA of its past scores. The global minimum score
+	// remains the floor.
+	AdaptiveThreshold bool
+	// AdaptiveAlpha is the EWMA weight given to each new score (0–1].
+	AdaptiveAlpha float64
+	// LiquidityDropFraction flags an event whose liquidity falls more than this
+	// fraction below its EWMA baseline (e.g. 0.5 = halved). 0 disables the check.
+	LiquidityDropFraction float64
+	// PerMarketLiquidity keeps liquidity-drop baselines per market (composite
+	// ID) rather than per event, for when Market.Liquidity is each market's own
+	// order-book depth instead of the event-level Gamma figure.
+	PerMarketLiquidity bool
+	// AlertOnUncertainty flags markets converging toward a coin flip: those whose
+	// uncertainty 0.5-|p-0.5| rose by at least UncertaintyThreshold in the window.
+	AlertOnUncertainty   bool
+	UncertaintyThreshold float64
+	// TCClip caps each step's |Δp| in trajectory consistency (see
+	// ClippedTrajectoryConsistency). 0 leaves moves unclamped.
+	TCClip float64
+	// MissingCyclesBeforeCleanup evicts a market's in-memory state (cooldown
+	// record, score and liquidity baselines) once it has been absent from this
+	// many consecutive fetches. 0 keeps state indefinitely.
+	MissingCyclesBeforeCleanup int
+	// MaxStates caps how many markets keep in-memory state. Past it, the
+	// markets whose score baseline or resolution streak was updated least
+	// recently are forgotten as by ForgetMissing. 0 = unlimited.
+	MaxStates int
+	// MinSnapshotsForSigma blends a market's SNR volatility toward a default
+	// while it has fewer snapshots than this (see ConfidenceWeightedSNR). 0 = off.
+	MinSnapshotsForSigma int
+	// RecentSigmaWeight blends SNR volatility toward that of the last
+	// RecentSigmaSnapshots snapshots (see BlendedSigma). 0 = all history only.
+	RecentSigmaWeight    float64
+	RecentSigmaSnapshots int
+	// MinProbability and MaxProbability bound the probabilities eligible for
+	// alerting: a change is dropped before scoring when its old or new
+	// probability falls outside the band. MaxProbability 0 is treated as 1.
+	MinProbability float64
+	MaxProbability float64
+	// TopKTiebreak orders event groups with near-equal BestScores (see
+	// sortGroups): TiebreakScoreOnly (default when empty), TiebreakRecency or
+	// TiebreakVolume.
+	TopKTiebreak string
+	// DivergenceMetric measures how far a move shifted the Yes/No distribution,
+	// the first composite score factor: DivergenceKL (default when empty),
+	// DivergenceJS or DivergenceHellinger.
+	DivergenceMetric string
+	// ResolutionPendingCycles treats a market priced at an extreme (see
+	// resolutionExtreme) for more than this many consecutive polls as awaiting
+	// resolution and excludes it from detection until its price leaves the
+	// extreme. 0 disables the check.
+	ResolutionPendingCycles int
+	// DetectLadderInconsistency checks events whose markets form a threshold
+	// ladder ("above $100k", "above $150k", …) for rungs priced above a rung
+	// they imply by more than LadderTolerance (see DetectLadderInconsistencies).
+	DetectLadderInconsistency bool
+	LadderTolerance           float64
+	// MaxAlertsPerMarketPerDay suppresses a market's notifications once it has
+	// been notified this many times in the current daily window (see
+	// FilterAlertBudget). Counts persist in storage. 0 = unlimited.
+	MaxAlertsPerMarketPerDay int
+	// AlertBudgetResetHour is the UTC hour (0–23) at which the daily window starts.
+	AlertBudgetResetHour int
+	// EventCooldownMultiplier adds an event-level cooldown to FilterRecentlySent:
+	// once any market of an event is notified in a direction, every market of
+	// that event moving the same way is suppressed for this many per-market
+	// cooldowns. Keeps a ladder whose rungs shift together from re-firing
+	// rung by rung. 0 = off.
+	EventCooldownMultiplier float64
+	// GroupMinBestScore is a group-level gate on top of the per-market score
+	// bar: an event group is only returned by ScoreAndRank when its BestScore
+	// reaches it. 0 = no group-level gate.
+	GroupMinBestScore float64
+	// ScoreCeiling marks composite scores at or above it as extreme outliers
+	// (a massive re-pricing or a data glitch). It has two roles: such scores
+	// are never folded into the adaptive-threshold baseline, so one outlier
+	// can't raise a market's bar for weeks (state protection); and
+	// AlertAboveCeiling decides whether they alert at all (alert eligibility).
+	// Alerting ones bypass the adaptive bar and are tagged KindExtreme.
+	// 0 = no ceiling.
+	ScoreCeiling      float64
+	AlertAboveCeiling bool
+	// CompactState keeps the per-market EWMA score and liquidity baselines in
+	// float32, roughly halving their memory for very large market sets. The
+	// arithmetic still runs in float64; only the stored values are narrowed.
+	CompactState bool
+	// ScoreExpr replaces the built-in composite score with a user formula over
+	// the score inputs (see ParseScoreExpr). nil = built-in formula.
+	ScoreExpr *ScoreExpr
+	// Weights raises each composite score factor to an exponent, shifting the
+	// score's emphasis between them. nil = every exponent 1 (plain product).
+	Weights *ScoreWeights
+	// DedupSimilarTitles collapses event groups whose titles have a token
+	// Jaccard similarity of at least TitleSimilarity into the strongest of them,
+	// which lists the others in Related. Catches several events about the same
+	// news ("X by June?", "X by July?"). Heuristic; off by default.
+	DedupSimilarTitles bool
+	TitleSimilarity    float64
+	// MaintenanceWindows are spans when upstream data is known to be unreliable
+	// (see ParseMaintenanceWindows). Detection still runs; callers check
+	// ActiveMaintenanceWindow to hold notifications. With
+	// FreezeStateInMaintenance the score, liquidity and coverage baselines are
+	// not updated during a window, so bad data cannot skew them.
+	MaintenanceWindows       []MaintenanceWindow
+	FreezeStateInMaintenance bool
+	// TrackSide maps a market ID or a lowercase category to the outcome its
+	// changes are framed from (models.SideYes or models.SideNo); market IDs
+	// take precedence. Probabilities stay on the Yes side internally; the side
+	// is carried on each Change for display. With ScoreTrackedSide the
+	// min_base_prob and probability-band prefilters also see the tracked side.
+	TrackSide        map[string]string
+	ScoreTrackedSide bool
+	// CategoryScore overrides ScoreAndRank's minScore and ScoreCeiling for
+	// markets whose lowercase category has an entry. A zero field keeps the
+	// global value.
+	CategoryScore map[string]ScoreBounds
+	// Clock overrides the time source. nil uses the system clock.
+	Clock Clock
+}
+
+// liquidityAlpha is the EWMA weight given to each new liquidity observation.
+const liquidityAlpha = 0.1
+
+// liquidityMinSamples is how many observations an event needs before its
+// liquidity baseline is trusted.
+const liquidityMinSamples = 3
+
+// defaultAdaptiveAlpha is used when Config.AdaptiveAlpha is unset.
+const defaultAdaptiveAlpha = 0.1
+
+// adaptiveMinSamples is how many scores a market needs before its own
+// distribution overrides the global threshold.
+const adaptiveMinSamples = 5
+
+// p90ZScore is the standard normal quantile for the 90th percentile.
+const p90ZScore = 1.2816
+
+// resolutionExtreme is how close to 0 or 1 a price must be to count toward
+// ResolutionPendingCycles.
+const resolutionExtreme = 0.005
+
+// defaultCoverageWindow is used when Config.CoverageWindow is unset.
+const defaultCoverageWindow = 12
+
 // New creates a new Monitor instance
-func New(s *storage.Storage) *Monitor {
-	return &Monitor{
+func New(s storage.Store, cfg ...Config) *Monitor {
+	m := &Monitor{
 		storage:         s,
 		notifiedMarkets: make(map[string]notifiedRecord),
+		notifiedEvents:  make(map[eventDirection]time.Time),
+		scoreStats:      make(map[string]*scoreStat),
+		liquidityStats:  make(map[string]*liquidityStat),
+
+		uncertainFlagged: make(map[string]bool),
+		tracked:          make(map[string]*trackedMarket),
+		extremeStreaks:   make(map[string]int),
+		stateUpdated:     make(map[string]time.Time),
+
+		inconsistentFlagged: make(map[string]bool),
+	}
+	if len(cfg) > 0 {
+		m.cfg = cfg[0]
+	}
+	if m.cfg.CompactState {
+		m.compactScoreStats = make(map[string]*compactScoreStat)
+		m.compactLiquidityStats = make(map[string]*compactLiquidityStat)
+	}
+	m.clock = m.cfg.Clock
+	if m.clock == nil {
+		m.clock = realClock{}
+	}
+	if m.cfg.MaxProbability <= 0 {
+		m.cfg.MaxProbability = 1.0
 	}
+	if m.cfg.CoverageWindow <= 0 {
+		m.cfg.CoverageWindow = defaultCoverageWindow
+	}
+	if m.cfg.AdaptiveAlpha <= 0 || m.cfg.AdaptiveAlpha > 1 {
+		m.cfg.AdaptiveAlpha = defaultAdaptiveAlpha
+	}
+	return m
+}
+
+// Pause stops scheduled monitoring cycles until Resume is called. Unlike muting,
+// no API requests are made while paused. Returns false if already paused.
+func (m *Monitor) Pause() bool {
+	return m.paused.CompareAndSwap(false, true)
+}
+
+// Resume re-enables scheduled monitoring cycles. Returns false if not paused.
+func (m *Monitor) Resume() bool {
+	return m.paused.CompareAndSwap(true, false)
+}
+
+// Paused reports whether monitoring cycles are currently paused.
+func (m *Monitor) Paused() bool {
+	return m.paused.Load()
 }
 
 // DetectionError represents a per-event error during change detection
@@ -74,7 +366,7 @@ func (m *Monitor) DetectChanges(markets []models.Market, window time.Duration) (
 
 	var changes []models.Change
 	var detectionErrors []DetectionError
-	now := time.Now()
+	now := m.clock.Now()
 
 	eventsWithZeroSnapshots := 0
 	eventsWithOneSnapshot := 0
@@ -83,7 +375,11 @@ func (m *Monitor) DetectChanges(markets []models.Market, window time.Duration) (
 	maxChangeSeen := 0.0
 
 	for _, market := range markets {
-		snapshots, err := m.storage.GetSnapshotsInWindow(market.ID, window)
+		// Closed markets are recorded for analysis but never alerted on
+		if market.Closed {
+			continue
+		}
+		snapshots, err := m.storage.GetSnapshotsSince(market.ID, now.Add(-window))
 		if err != nil {
 			detectionErrors = append(detectionErrors, DetectionError{EventID: market.ID, Err: err})
 			continue
@@ -130,6 +426,10 @@ func (m *Monitor) DetectChanges(markets []models.Market, window time.Duration) (
 				TimeWindow:      window,
 				DetectedAt:      now,
 				Notified:        false,
+				Liquidity:       market.Liquidity,
+				Volume24hr:      market.Volume24hr,
+				Description:     market.Description,
+				Side:            m.sideFor(market),
 			})
 		} else if change > 0 {
 			eventsWithChangeBelowFloor++
@@ -152,6 +452,46 @@ func KLDivergence(pOld, pNew float64) float64 {
 	return pNew*math.Log(pNew/pOld) + (1-pNew)*math.Log((1-pNew)/(1-pOld))
 }
 
+// Divergence metrics accepted by Config.DivergenceMetric.
+const (
+	DivergenceKL        = "kl"        // KLDivergence (default)
+	DivergenceJS        = "js"        // JSDivergence
+	DivergenceHellinger = "hellinger" // HellingerDistance
+)
+
+// JSDivergence computes the Jensen–Shannon divergence between the binary
+// distributions of pOld and pNew: the mean KL of each from their midpoint, in
+// nats. Unlike KL it is symmetric and bounded by ln 2. Probabilities are
+// clamped as in KLDivergence.
+func JSDivergence(pOld, pNew float64) float64 {
+	pOld = math.Max(probEpsilon, math.Min(1-probEpsilon, pOld))
+	pNew = math.Max(probEpsilon, math.Min(1-probEpsilon, pNew))
+	mid := (pOld + pNew) / 2
+	return math.Max(0, (KLDivergence(mid, pOld)+KLDivergence(mid, pNew))/2)
+}
+
+// HellingerDistance computes the Hellinger distance between the binary
+// distributions of pOld and pNew, in [0, 1]: sqrt(1 − BC) with Bhattacharyya
+// coefficient BC = sqrt(pOld·pNew) + sqrt((1−pOld)(1−pNew)). Probabilities are
+// clamped to [0, 1]; it is finite at the boundaries without further clamping.
+func HellingerDistance(pOld, pNew float64) float64 {
+	pOld = math.Max(0, math.Min(1, pOld))
+	pNew = math.Max(0, math.Min(1, pNew))
+	bc := math.Sqrt(pOld*pNew) + math.Sqrt((1-pOld)*(1-pNew))
+	return math.Sqrt(math.Max(0, 1-bc))
+}
+
+// divergence measures a move from pOld to pNew with Config.DivergenceMetric.
+func (m *Monitor) divergence(pOld, pNew float64) float64 {
+	switch m.cfg.DivergenceMetric {
+	case DivergenceJS:
+		return JSDivergence(pOld, pNew)
+	case DivergenceHellinger:
+		return HellingerDistance(pOld, pNew)
+	}
+	return KLDivergence(pOld, pNew)
+}
+
 // LogVolumeWeight returns log2(1 + volume24h/vRef), floored at 0.1.
 // At vRef volume the weight is 1.0; at 4×vRef it is ~2.32; at 0 volume it is 0.1.
 // When vRef <= 0 it is treated as 1.0 to avoid division by zero.
@@ -168,20 +508,78 @@ func LogVolumeWeight(volume24h, vRef float64) float64 {
 // Returns clamp(|netChange|/σ, 0.5, 5.0).
 // Falls back to 1.0 when fewer than 2 consecutive pairs exist or σ < 1e-4.
 func HistoricalSNR(allSnapshots []models.Snapshot, netChange float64) float64 {
-	if len(allSnapshots) < 2 {
+	sigma, ok := deltaSigma(allSnapshots)
+	return snrFromSigma(sigma, ok, len(allSnapshots), netChange, 0)
+}
+
+// defaultSigma is the per-interval Δp volatility assumed for a market with too
+// little history to estimate its own.
+const defaultSigma = 0.01
+
+// ConfidenceWeightedSNR is HistoricalSNR with σ blended toward defaultSigma
+// while the market has fewer than minSnapshots snapshots:
+//
+//	σ = w·σ_sample + (1−w)·defaultSigma,  w = min(1, n/minSnapshots)
+//
+// With fewer than 3 snapshots (no sample σ) defaultSigma is used outright.
+// minSnapshots ≤ 0 disables blending and behaves exactly like HistoricalSNR.
+func ConfidenceWeightedSNR(allSnapshots []models.Snapshot, netChange float64, minSnapshots int) float64 {
+	sigma, ok := deltaSigma(allSnapshots)
+	return snrFromSigma(sigma, ok, len(allSnapshots), netChange, minSnapshots)
+}
+
+// snrFromSigma is the SNR shared by HistoricalSNR and ConfidenceWeightedSNR,
+// given a sample σ (ok = false when there is none) estimated from n snapshots.
+func snrFromSigma(sigma float64, ok bool, n int, netChange float64, minSnapshots int) float64 {
+	if minSnapshots > 0 {
+		blended := defaultSigma
+		if ok {
+			w := math.Min(1.0, float64(n)/float64(minSnapshots))
+			blended = w*sigma + (1-w)*defaultSigma
+		}
+		sigma, ok = blended, true
+	}
+	if !ok || sigma < 1e-4 {
 		return 1.0
 	}
 
+	snr := math.Abs(netChange) / sigma
+	return math.Max(0.5, math.Min(5.0, snr))
+}
+
+// BlendedSigma mixes a market's all-history Δp volatility with that of its
+// last recentSnapshots snapshots, so the noise floor follows regime changes
+// without losing long-term stability:
+//
+//	σ = (1−w)·σ_all + w·σ_recent
+//
+// w ≤ 0, or too few recent snapshots for a sample σ, returns σ_all unchanged.
+// ok is false when there is no all-history σ (fewer than 3 snapshots).
+func BlendedSigma(allSnapshots []models.Snapshot, recentSnapshots int, w float64) (float64, bool) {
+	all, ok := deltaSigma(allSnapshots)
+	if !ok || w <= 0 || recentSnapshots <= 0 {
+		return all, ok
+	}
+	recent, recentOK := deltaSigma(allSnapshots[max(0, len(allSnapshots)-recentSnapshots):])
+	if !recentOK {
+		return all, true
+	}
+	w = math.Min(w, 1.0)
+	return (1-w)*all + w*recent, true
+}
+
+// deltaSigma returns the sample std dev of consecutive Δp across snapshots
+// (Bessel correction, divide by n-1), or false with fewer than 2 deltas.
+func deltaSigma(allSnapshots []models.Snapshot) (float64, bool) {
+	if len(allSnapshots) < 3 {
+		return 0, false
+	}
+
 	deltas := make([]float64, len(allSnapshots)-1)
 	for i := 1; i < len(allSnapshots); i++ {
 		deltas[i-1] = allSnapshots[i].YesProbability - allSnapshots[i-1].YesProbability
 	}
 
-	// Need at least 2 deltas for Bessel-corrected std dev (divide by n-1)
-	if len(deltas) < 2 {
-		return 1.0
-	}
-
 	// Sample mean
 	var sum float64
 	for _, d := range deltas {
@@ -194,20 +592,20 @@ func HistoricalSNR(allSnapshots []models.Snapshot, netChange float64) float64 {
 		variance += diff * diff
 	}
 	variance /= float64(len(deltas) - 1)
-	sigma := math.Sqrt(variance)
-
-	if sigma < 1e-4 {
-		return 1.0
-	}
-
-	snr := math.Abs(netChange) / sigma
-	return math.Max(0.5, math.Min(5.0, snr))
+	return math.Sqrt(variance), true
 }
 
 // TrajectoryConsistency returns |ΣΔp| / Σ|Δp| across consecutive snapshot pairs
 // in the window. A value of 1.0 means perfectly directional; 0.0 means fully
 // oscillating. Falls back to 1.0 when the window has ≤ 1 consecutive pair.
 func TrajectoryConsistency(windowSnapshots []models.Snapshot) float64 {
+	return ClippedTrajectoryConsistency(windowSnapshots, 0)
+}
+
+// ClippedTrajectoryConsistency is TrajectoryConsistency with each Δp clamped to
+// [-clip, clip] first, so a single outlier poll cannot dominate the signed sum
+// and keep a quiet market looking directional. clip ≤ 0 disables clamping.
+func ClippedTrajectoryConsistency(windowSnapshots []models.Snapshot, clip float64) float64 {
 	if len(windowSnapshots) < 2 {
 		return 1.0
 	}
@@ -215,6 +613,9 @@ func TrajectoryConsistency(windowSnapshots []models.Snapshot) float64 {
 	var sumSigned, sumAbs float64
 	for i := 1; i < len(windowSnapshots); i++ {
 		delta := windowSnapshots[i].YesProbability - windowSnapshots[i-1].YesProbability
+		if clip > 0 {
+			delta = math.Max(-clip, math.Min(clip, delta))
+		}
 		sumSigned += delta
 		sumAbs += math.Abs(delta)
 	}
@@ -226,11 +627,230 @@ func TrajectoryConsistency(windowSnapshots []models.Snapshot) float64 {
 	return math.Abs(sumSigned) / sumAbs
 }
 
+// SmoothProbability applies one EWMA step to a quoted probability:
+// alpha·raw + (1−alpha)·prev. alpha outside (0, 1) returns raw unchanged, so
+// alpha = 1 disables smoothing.
+func SmoothProbability(prev, raw, alpha float64) float64 {
+	if alpha <= 0 || alpha >= 1 {
+		return raw
+	}
+	return alpha*raw + (1-alpha)*prev
+}
+
 // CompositeScore multiplies the four factors into a single signal quality scalar.
 func CompositeScore(kl, vw, snr, tc float64) float64 {
 	return kl * vw * snr * tc
 }
 
+// ScoreBounds is a category's alert threshold and score ceiling override.
+type ScoreBounds struct {
+	MinScore     float64
+	ScoreCeiling float64
+}
+
+// scoreBounds returns the threshold and score ceiling for market: its
+// category's CategoryScore override where set, else minScore and ScoreCeiling.
+func (m *Monitor) scoreBounds(market *models.Market, minScore float64) (threshold, ceiling float64) {
+	threshold, ceiling = minScore, m.cfg.ScoreCeiling
+	b, ok := m.cfg.CategoryScore[strings.ToLower(market.Category)]
+	if !ok {
+		return threshold, ceiling
+	}
+	if b.MinScore > 0 {
+		threshold = b.MinScore
+	}
+	if b.ScoreCeiling > 0 {
+		ceiling = b.ScoreCeiling
+	}
+	return threshold, ceiling
+}
+
+// ScoreWeights are exponents on the composite score factors. 1 keeps a factor
+// as is, 0 drops it, and >1 makes the score more sensitive to it.
+type ScoreWeights struct {
+	KL     float64
+	Volume float64
+	SNR    float64
+	TC     float64
+}
+
+// WeightedCompositeScore is CompositeScore with each factor raised to its
+// exponent in w: kl^w.KL · vw^w.Volume · snr^w.SNR · tc^w.TC. All factors are
+// non-negative, and an exponent of 1 leaves its factor exactly unchanged.
+func WeightedCompositeScore(kl, vw, snr, tc float64, w ScoreWeights) float64 {
+	return math.Pow(kl, w.KL) * math.Pow(vw, w.Volume) * math.Pow(snr, w.SNR) * math.Pow(tc, w.TC)
+}
+
+// sideFor returns the side a market's changes are framed from, by market ID
+// then category; empty means the Yes side.
+func (m *Monitor) sideFor(market models.Market) string {
+	side, ok := m.cfg.TrackSide[market.MarketID]
+	if !ok {
+		side = m.cfg.TrackSide[strings.ToLower(market.Category)]
+	}
+	if side == models.SideNo {
+		return side
+	}
+	return ""
+}
+
+// inProbabilityBand reports whether p lies within the configured alerting band.
+func (m *Monitor) inProbabilityBand(p float64) bool {
+	return p >= m.cfg.MinProbability && p <= m.cfg.MaxProbability
+}
+
+// adaptiveThreshold returns the score a market must reach under adaptive mode:
+// the approximate p90 (mean + 1.28σ) of its recent scores, floored at minScore.
+// Markets with fewer than adaptiveMinSamples scores use minScore.
+func (m *Monitor) adaptiveThreshold(id string, minScore float64) float64 {
+	st, ok := m.loadScoreStat(id)
+	if !ok || st.Count < adaptiveMinSamples {
+		return minScore
+	}
+	variance := math.Max(0, st.MeanSq-st.Mean*st.Mean)
+	return math.Max(minScore, st.Mean+p90ZScore*math.Sqrt(variance))
+}
+
+// observeScore folds a new score into the market's EWMA score summary.
+func (m *Monitor) observeScore(id string, score float64) {
+	defer m.touchState(id)
+	st, ok := m.loadScoreStat(id)
+	if !ok {
+		m.storeScoreStat(id, scoreStat{Mean: score, MeanSq: score * score, Count: 1})
+		return
+	}
+	a := m.cfg.AdaptiveAlpha
+	st.Mean = (1-a)*st.Mean + a*score
+	st.MeanSq = (1-a)*st.MeanSq + a*score*score
+	st.Count++
+	m.storeScoreStat(id, st)
+}
+
+// loadScoreStat returns a market's score summary, widened to float64 when
+// state is compact.
+func (m *Monitor) loadScoreStat(id string) (scoreStat, bool) {
+	if m.cfg.CompactState {
+		c, ok := m.compactScoreStats[id]
+		if !ok {
+			return scoreStat{}, false
+		}
+		return scoreStat{Mean: float64(c.Mean), MeanSq: float64(c.MeanSq), Count: int(c.Count)}, true
+	}
+	st, ok := m.scoreStats[id]
+	if !ok {
+		return scoreStat{}, false
+	}
+	return *st, true
+}
+
+// storeScoreStat saves a market's score summary, narrowing it to float32 when
+// state is compact.
+func (m *Monitor) storeScoreStat(id string, st scoreStat) {
+	if m.cfg.CompactState {
+		m.compactScoreStats[id] = &compactScoreStat{Mean: float32(st.Mean), MeanSq: float32(st.MeanSq), Count: int32(st.Count)}
+		return
+	}
+	full := st
+	m.scoreStats[id] = &full
+}
+
+// touchState records a market's state as just updated and, when that brings
+// the market count over MaxStates, evicts the least recently updated one.
+func (m *Monitor) touchState(id string) {
+	if m.cfg.MaxStates <= 0 {
+		return
+	}
+	_, known := m.stateUpdated[id]
+	m.stateUpdated[id] = m.clock.Now()
+	if known || len(m.stateUpdated) <= m.cfg.MaxStates {
+		return
+	}
+
+	var oldest string
+	var oldestAt time.Time
+	for other, at := range m.stateUpdated {
+		if other == id {
+			continue
+		}
+		if oldest == "" || at.Before(oldestAt) || (at.Equal(oldestAt) && other < oldest) {
+			oldest, oldestAt = other, at
+		}
+	}
+	m.forgetMarket(oldest)
+	logger.Debug("Evicted state of market %s (last updated %s; monitor.max_states=%d)",
+		oldest, oldestAt.Format(time.RFC3339), m.cfg.MaxStates)
+}
+
+// liquidityKey returns the key of a market's liquidity baseline: its event ID,
+// or its composite ID under Config.PerMarketLiquidity.
+func (m *Monitor) liquidityKey(market models.Market) string {
+	if m.cfg.PerMarketLiquidity {
+		return market.ID
+	}
+	return market.EventID
+}
+
+// loadLiquidityStat returns the liquidity baseline stored under key (see
+// liquidityKey), widened to float64 when state is compact.
+func (m *Monitor) loadLiquidityStat(key string) (liquidityStat, bool) {
+	if m.cfg.CompactState {
+		c, ok := m.compactLiquidityStats[key]
+		if !ok {
+			return liquidityStat{}, false
+		}
+		return liquidityStat{AvgDepth: float64(c.AvgDepth), Count: int(c.Count), Dropped: c.Dropped}, true
+	}
+	st, ok := m.liquidityStats[key]
+	if !ok {
+		return liquidityStat{}, false
+	}
+	return *st, true
+}
+
+// storeLiquidityStat saves the liquidity baseline under key, narrowing it to
+// float32 when state is compact.
+func (m *Monitor) storeLiquidityStat(key string, st liquidityStat) {
+	if m.cfg.CompactState {
+		m.compactLiquidityStats[key] = &compactLiquidityStat{AvgDepth: float32(st.AvgDepth), Count: int32(st.Count), Dropped: st.Dropped}
+		return
+	}
+	full := st
+	m.liquidityStats[key] = &full
+}
+
+// mergeByMarket collapses changes that refer to the same market (composite
+// EventID) into a single entry, keeping the highest-scoring change and merging
+// the kind tags of the others into it. First-seen order is preserved.
+func mergeByMarket(changes []models.Change) []models.Change {
+	index := make(map[string]int, len(changes))
+	var result []models.Change
+
+	for _, change := range changes {
+		i, exists := index[change.EventID]
+		if !exists {
+			index[change.EventID] = len(result)
+			result = append(result, change)
+			continue
+		}
+		kept, other := result[i], change
+		if other.SignalScore > kept.SignalScore {
+			kept, other = other, kept
+		}
+		kinds := kept.Kinds()
+		for _, k := range other.Kinds() {
+			if !slices.Contains(kinds, k) {
+				kinds = append(kinds, k)
+			}
+		}
+		kept.Kind = strings.Join(kinds, ",")
+		if len(kinds) == 1 && kinds[0] == models.KindProbability {
+			kept.Kind = "" // keep the default representation for plain probability moves
+		}
+		result[i] = kept
+	}
+	return result
+}
+
 // groupByEvent groups a slice of scored changes by their OriginalEventID (falling
 // back to EventID when OriginalEventID is empty). Markets within each group are
 // sorted by SignalScore descending. Insertion order of groups is preserved.
@@ -271,9 +891,11 @@ func groupByEvent(changes []models.Change) []models.Event {
 }
 
 // ScoreAndRank scores each change using the four-factor composite signal score,
-// filters out changes below minScore, groups them by original event ID, and
+// filters out changes below minScore, groups them by original event ID, drops
+// groups whose BestScore is below Config.GroupMinBestScore, and
 // returns at most k event groups sorted by BestScore descending. Ties are broken
-// by EventID lexicographic descending for determinism. Returns an empty (non-nil)
+// per Config.TopKTiebreak, then by EventID lexicographic descending for
+// determinism. Returns an empty (non-nil)
 // slice when nothing clears the quality bar.
 // vRef is the reference volume for log-volume weighting (typically volume_24hr_min
 // from config); markets at this volume receive weight ≈ 1.0.
@@ -296,26 +918,10 @@ func (m *Monitor) ScoreAndRank(
 	}
 
 	var candidates []models.Change
+	frozen := m.frozen()
 
 	for _, change := range changes {
-		// Pre-score filter 1: minimum absolute probability change.
-		// KL divergence can be inflated for small absolute moves (especially at
-		// tail probabilities where log-ratios are large). Discard changes that
-		// are not economically meaningful regardless of KL or volume.
-		// Exception: skip this filter when the market *enters* confirmation territory
-		// (new probability crosses >95% or <5% from outside), as those transitions
-		// are always noteworthy regardless of move size.
-		entersConfirmation := (change.NewProbability > 0.95 && change.OldProbability <= 0.95) ||
-			(change.NewProbability < 0.05 && change.OldProbability >= 0.05)
-		if minAbsChange > 0 && change.Magnitude < minAbsChange && !entersConfirmation {
-			continue
-		}
-
-		// Pre-score filter 2: minimum base probability.
-		// Tail-probability markets (< 5%) have unreliable KL because p_new/p_old
-		// ratios blow up for tiny absolute moves. Also, stable tail markets have
-		// near-zero historical σ, so SNR clamps to 5.0 and amplifies the inflated KL.
-		if minBaseProb > 0 && change.OldProbability < minBaseProb {
+		if !m.passesPrefilters(change, minAbsChange, minBaseProb) {
 			continue
 		}
 
@@ -326,36 +932,258 @@ func (m *Monitor) ScoreAndRank(
 		}
 
 		allSnaps, err := m.storage.GetSnapshots(change.EventID)
+		sigma, hasSigma := BlendedSigma(allSnaps, m.cfg.RecentSigmaSnapshots, m.cfg.RecentSigmaWeight)
 		snr := 1.0
 		if err == nil {
-			snr = HistoricalSNR(allSnaps, change.NewProbability-change.OldProbability)
+			snr = snrFromSigma(sigma, hasSigma, len(allSnaps), change.NewProbability-change.OldProbability, m.cfg.MinSnapshotsForSigma)
 		}
 
-		winSnaps, err := m.storage.GetSnapshotsInWindow(change.EventID, change.TimeWindow)
+		winSnaps, err := m.storage.GetSnapshotsSince(change.EventID, m.clock.Now().Add(-change.TimeWindow))
 		tc := 1.0
 		if err == nil {
-			tc = TrajectoryConsistency(winSnaps)
+			tc = ClippedTrajectoryConsistency(winSnaps, m.cfg.TCClip)
 		}
 
-		kl := KLDivergence(change.OldProbability, change.NewProbability)
-		vw := LogVolumeWeight(market.Volume24hr, vRef)
-		score := CompositeScore(kl, vw, snr, tc)
+		change.Components = &models.ScoreComponents{
+			KL:               m.divergence(change.OldProbability, change.NewProbability),
+			VolumeWeight:     LogVolumeWeight(market.Volume24hr, vRef),
+			SNR:              snr,
+			TC:               tc,
+			Sigma:            sigma,
+			HistorySnapshots: len(allSnaps),
+			WindowSnapshots:  len(winSnaps),
+			Volume24hr:       market.Volume24hr,
+			VolumeRef:        vRef,
+			Liquidity:        market.Liquidity,
+		}
+		score := m.finalScore(change)
 
 		change.SignalScore = score
-		if score >= minScore {
+		threshold, ceiling := m.scoreBounds(market, minScore)
+		extreme := ceiling > 0 && score >= ceiling
+		if m.cfg.AdaptiveThreshold {
+			// Compare against history before folding in this score, so a spike
+			// cannot raise its own bar. Outliers above the ceiling stay out.
+			// Only the score summaries are shared state; the snapshot reads
+			// and scoring above run unlocked so readers aren't held up.
+			m.mu.Lock()
+			threshold = m.adaptiveThreshold(change.EventID, threshold)
+			if !extreme && !frozen {
+				m.observeScore(change.EventID, score)
+			}
+			m.mu.Unlock()
+		}
+		change.Components.Threshold = threshold
+		if extreme {
+			if !m.cfg.AlertAboveCeiling {
+				logger.Debug("Score %.4f for %s at or above ceiling %.4f; not alerting", score, change.EventID, ceiling)
+				continue
+			}
+			change.Kind = strings.Join(append(change.Kinds(), models.KindExtreme), ",")
+			candidates = append(candidates, change)
+			continue
+		}
+		if score >= threshold {
 			candidates = append(candidates, change)
 		}
 	}
+	return m.rank(candidates, markets, k)
+}
 
-	groups := groupByEvent(candidates)
+// Rerank rescores stored alerts under the monitor's current configuration and
+// the given ScoreAndRank parameters, and ranks them the same way, so config
+// changes can be evaluated without waiting for live moves. Scores are rebuilt
+// from each alert's recorded components: the divergence (KL field) and volume
+// weight are recomputed, SNR is re-derived from the recorded σ, and TC is
+// reused as recorded. Alerts without components are skipped. categories maps
+// composite market IDs to their stored category, so Config.CategoryScore
+// overrides apply as in ScoreAndRank. Adaptive thresholds are not applied,
+// since per-market score baselines are not persisted.
+func (m *Monitor) Rerank(
+	alerts []models.Change,
+	categories map[string]string,
+	minScore float64,
+	k int,
+	vRef float64,
+	minAbsChange float64,
+	minBaseProb float64,
+) []models.Event {
+	if vRef <= 0 {
+		vRef = 25000.0
+	}
 
-	sort.Slice(groups, func(i, j int) bool {
-		if groups[i].BestScore != groups[j].BestScore {
-			return groups[i].BestScore > groups[j].BestScore
+	var candidates []models.Change
+	markets := make(map[string]*models.Market)
+	for _, change := range alerts {
+		c := change.Components
+		if c == nil || !m.passesPrefilters(change, minAbsChange, minBaseProb) {
+			continue
 		}
-		// Tie-break: ID lexicographic descending
-		return groups[i].ID > groups[j].ID
+		market := &models.Market{ID: change.EventID, Category: categories[change.EventID], Volume24hr: c.Volume24hr, Liquidity: c.Liquidity}
+		markets[change.EventID] = market
+		threshold, ceiling := m.scoreBounds(market, minScore)
+
+		// deltaSigma needs 3 snapshots; below that the recorded σ is a placeholder.
+		snr := snrFromSigma(c.Sigma, c.HistorySnapshots >= 3, c.HistorySnapshots,
+			change.NewProbability-change.OldProbability, m.cfg.MinSnapshotsForSigma)
+		rescored := *c
+		rescored.KL = m.divergence(change.OldProbability, change.NewProbability)
+		rescored.VolumeWeight = LogVolumeWeight(c.Volume24hr, vRef)
+		rescored.SNR = snr
+		rescored.VolumeRef, rescored.Threshold = vRef, threshold
+		change.Components = &rescored
+		score := m.finalScore(change)
+		change.SignalScore = score
+
+		if ceiling > 0 && score >= ceiling {
+			if m.cfg.AlertAboveCeiling {
+				change.Kind = strings.Join(append(change.Kinds(), models.KindExtreme), ",")
+				candidates = append(candidates, change)
+			}
+			continue
+		}
+		if score >= threshold {
+			candidates = append(candidates, change)
+		}
+	}
+	return m.rank(candidates, markets, k)
+}
+
+// finalScore returns a change's score from its Components: the built-in
+// CompositeScore (weighted by Config.Weights), or Config.ScoreExpr evaluated
+// over the same inputs when set. An expression that yields NaN or ±Inf scores
+// 0, so the market can't alert.
+func (m *Monitor) finalScore(change models.Change) float64 {
+	c := change.Components
+	score := CompositeScore(c.KL, c.VolumeWeight, c.SNR, c.TC)
+	if m.cfg.Weights != nil {
+		score = WeightedCompositeScore(c.KL, c.VolumeWeight, c.SNR, c.TC, *m.cfg.Weights)
+	}
+	if m.cfg.ScoreExpr == nil {
+		return score
+	}
+	v, err := m.cfg.ScoreExpr.Eval(map[string]float64{
+		"score":         score,
+		"kl":            c.KL,
+		"volume_weight": c.VolumeWeight,
+		"snr":           c.SNR,
+		"tc":            c.TC,
+		"sigma":         c.Sigma,
+		"magnitude":     change.Magnitude,
+		"volume24hr":    c.Volume24hr,
+		"liquidity":     c.Liquidity,
 	})
+	if err != nil {
+		logger.Debug("Scoring %s: %v; scoring it 0", change.EventID, err)
+		return 0
+	}
+	return v
+}
+
+// collapseSimilarTitles folds each group into the first earlier group whose
+// title is at least threshold-similar (see titleSimilarity), recording its
+// title in that group's Related. groups must be sorted strongest first, so the
+// representative kept is always the highest-ranked of its cluster.
+func collapseSimilarTitles(groups []models.Event, threshold float64) []models.Event {
+	var kept []models.Event
+	var keptTokens []map[string]bool
+	for _, g := range groups {
+		tokens := titleTokens(g.Title)
+		i := slices.IndexFunc(keptTokens, func(t map[string]bool) bool {
+			return titleSimilarity(tokens, t) >= threshold
+		})
+		if i >= 0 {
+			kept[i].Related = append(kept[i].Related, g.Title)
+			continue
+		}
+		kept = append(kept, g)
+		keptTokens = append(keptTokens, tokens)
+	}
+	return kept
+}
+
+// titleTokens returns the set of lowercased letter/digit runs in a title.
+func titleTokens(title string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, f := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		tokens[f] = true
+	}
+	return tokens
+}
+
+// titleSimilarity is the Jaccard index |A∩B| / |A∪B| of two token sets (0 when
+// both are empty).
+func titleSimilarity(a, b map[string]bool) float64 {
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// passesPrefilters applies ScoreAndRank's pre-score filters to a change.
+func (m *Monitor) passesPrefilters(change models.Change, minAbsChange, minBaseProb float64) bool {
+	if m.cfg.ScoreTrackedSide {
+		change = change.Sided()
+	}
+
+	// Pre-score filter 1: minimum absolute probability change.
+	// KL divergence can be inflated for small absolute moves (especially at
+	// tail probabilities where log-ratios are large). Discard changes that
+	// are not economically meaningful regardless of KL or volume.
+	// Exception: skip this filter when the market *enters* confirmation territory
+	// (new probability crosses >95% or <5% from outside), as those transitions
+	// are always noteworthy regardless of move size.
+	entersConfirmation := (change.NewProbability > 0.95 && change.OldProbability <= 0.95) ||
+		(change.NewProbability < 0.05 && change.OldProbability >= 0.05)
+	if minAbsChange > 0 && change.Magnitude < minAbsChange && !entersConfirmation {
+		return false
+	}
+
+	// Pre-score filter 2: minimum base probability.
+	// Tail-probability markets (< 5%) have unreliable KL because p_new/p_old
+	// ratios blow up for tiny absolute moves. Also, stable tail markets have
+	// near-zero historical σ, so SNR clamps to 5.0 and amplifies the inflated KL.
+	if minBaseProb > 0 && change.OldProbability < minBaseProb {
+		return false
+	}
+
+	// Pre-score filter 3: probability band. Markets whose old or new
+	// probability lies outside [MinProbability, MaxProbability] are out of
+	// scope for alerting; their snapshots are still recorded.
+	return m.inProbabilityBand(change.OldProbability) && m.inProbabilityBand(change.NewProbability)
+}
+
+// rank groups scored candidates by event and returns the top k groups, as the
+// final step of ScoreAndRank and Rerank.
+func (m *Monitor) rank(candidates []models.Change, markets map[string]*models.Market, k int) []models.Event {
+	// DetectChanges yields one change per market, but guard the notification
+	// against listing a market twice should a caller pass more.
+	candidates = mergeByMarket(candidates)
+	groups := groupByEvent(candidates)
+	if m.cfg.GroupMinBestScore > 0 {
+		groups = slices.DeleteFunc(groups, func(g models.Event) bool {
+			return g.BestScore < m.cfg.GroupMinBestScore
+		})
+	}
+	for i := range groups {
+		// Markets are sorted by score, so the top market represents the group.
+		if market, ok := markets[groups[i].Markets[0].EventID]; ok {
+			groups[i].Category = market.Category
+		}
+	}
+	m.sortGroups(groups, markets)
+	if m.cfg.DedupSimilarTitles {
+		groups = collapseSimilarTitles(groups, m.cfg.TitleSimilarity)
+	}
 
 	if k <= 0 || len(groups) == 0 {
 		return []models.Event{}
@@ -366,17 +1194,134 @@ func (m *Monitor) ScoreAndRank(
 	return groups[:k]
 }
 
+// Tie-break modes accepted by Config.TopKTiebreak.
+const (
+	TiebreakScoreOnly = "score_only" // exact BestScore, then event ID (default)
+	TiebreakRecency   = "recency"    // latest DetectedAt among the group's markets
+	TiebreakVolume    = "volume"     // highest 24hr volume among the group's markets
+)
+
+// tiebreakSignificantDigits is the precision at which BestScores count as tied
+// under the recency and volume tie-breaks.
+const tiebreakSignificantDigits = 3
+
+// sortGroups orders groups by BestScore descending. Under TiebreakRecency or
+// TiebreakVolume, scores equal to tiebreakSignificantDigits are ordered by that
+// key first. Remaining ties fall back to exact score, then event ID descending,
+// so the order is always deterministic.
+func (m *Monitor) sortGroups(groups []models.Event, markets map[string]*models.Market) {
+	tiebreak := m.cfg.TopKTiebreak
+	keys := make(map[string]float64, len(groups))
+	if tiebreak == TiebreakRecency || tiebreak == TiebreakVolume {
+		for _, g := range groups {
+			var key float64
+			for _, c := range g.Markets {
+				v := float64(c.DetectedAt.UnixNano())
+				if tiebreak == TiebreakVolume {
+					v = 0
+					if market, ok := markets[c.EventID]; ok {
+						v = market.Volume24hr
+					}
+				}
+				key = math.Max(key, v)
+			}
+			keys[g.ID] = key
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if len(keys) > 0 {
+			if ra, rb := roundSignificant(a.BestScore), roundSignificant(b.BestScore); ra != rb {
+				return ra > rb
+			}
+			if keys[a.ID] != keys[b.ID] {
+				return keys[a.ID] > keys[b.ID]
+			}
+		}
+		if a.BestScore != b.BestScore {
+			return a.BestScore > b.BestScore
+		}
+		// Tie-break: ID lexicographic descending
+		return a.ID > b.ID
+	})
+}
+
+// roundSignificant rounds a positive score to tiebreakSignificantDigits significant digits.
+func roundSignificant(s float64) float64 {
+	if s <= 0 {
+		return s
+	}
+	scale := math.Pow(10, tiebreakSignificantDigits-1-math.Floor(math.Log10(s)))
+	return math.Round(s*scale) / scale
+}
+
 // isDeterministicZone returns true when a probability is in the high-conviction
 // region (>90% or <10%), where further moves carry outsized informational weight.
 func isDeterministicZone(p float64) bool {
 	return p > 0.90 || p < 0.10
 }
 
+// MergeGroups combines alert groups held from an earlier cycle with those of a
+// later one, for sending as a single notification. A market present in both
+// keeps only its latest change; event metadata also comes from the latest
+// cycle. Markets are re-sorted by score within each group and groups by
+// BestScore.
+func (m *Monitor) MergeGroups(pending, latest []models.Event) []models.Event {
+	groups := make(map[string]*models.Event)
+	var order []string
+	for _, batch := range [][]models.Event{pending, latest} {
+		for _, g := range batch {
+			merged, ok := groups[g.ID]
+			if !ok {
+				merged = &models.Event{}
+				groups[g.ID] = merged
+				order = append(order, g.ID)
+			}
+			markets := merged.Markets
+			*merged = g
+			merged.Markets = markets
+			for _, c := range g.Markets {
+				i := slices.IndexFunc(merged.Markets, func(o models.Change) bool { return o.EventID == c.EventID })
+				if i >= 0 {
+					merged.Markets[i] = c
+				} else {
+					merged.Markets = append(merged.Markets, c)
+				}
+			}
+		}
+	}
+
+	result := make([]models.Event, 0, len(order))
+	for _, id := range order {
+		g := groups[id]
+		sort.SliceStable(g.Markets, func(i, j int) bool {
+			return g.Markets[i].SignalScore > g.Markets[j].SignalScore
+		})
+		g.BestScore = 0
+		for _, c := range g.Markets {
+			g.BestScore = math.Max(g.BestScore, c.SignalScore)
+		}
+		result = append(result, *g)
+	}
+	m.sortGroups(result, nil)
+	return result
+}
+
 // FilterRecentlySent removes markets from groups that were recently notified with
 // the same direction and are not entering the deterministic zone for the first time.
 // Groups that become empty after filtering are dropped. Returns a non-nil slice.
 func (m *Monitor) FilterRecentlySent(groups []models.Event, cooldown time.Duration) []models.Event {
-	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	skewed := now.Add(models.FutureTimestampTolerance())
+	eventCooldown := time.Duration(m.cfg.EventCooldownMultiplier * float64(cooldown))
+	for key, sentAt := range m.notifiedEvents {
+		if now.Sub(sentAt) >= eventCooldown {
+			delete(m.notifiedEvents, key)
+		}
+	}
 	var result []models.Event
 
 	for _, group := range groups {
@@ -384,11 +1329,22 @@ func (m *Monitor) FilterRecentlySent(groups []models.Event, cooldown time.Durati
 		for _, change := range group.Markets {
 			compositeID := change.EventID
 			rec, exists := m.notifiedMarkets[compositeID]
+			// A send time beyond the skew tolerance in the future means the clock
+			// stepped back; ignore the record rather than stretch the cooldown.
+			if exists && rec.SentAt.After(skewed) {
+				exists = false
+			}
+			enteringDetZone := isDeterministicZone(change.NewProbability) && (!exists || !isDeterministicZone(rec.NewProb))
 			if exists && now.Sub(rec.SentAt) < cooldown {
 				// Recently sent — suppress unless direction changed or entering det zone
-				sameDirection := rec.Direction == change.Direction
-				enteringDetZone := isDeterministicZone(change.NewProbability) && !isDeterministicZone(rec.NewProb)
-				if sameDirection && !enteringDetZone {
+				if rec.Direction == change.Direction && !enteringDetZone {
+					continue
+				}
+			}
+			// The event moved this way recently, possibly through other markets
+			if eventCooldown > 0 && !enteringDetZone {
+				sentAt, ok := m.notifiedEvents[eventDirection{group.ID, change.Direction}]
+				if ok && !sentAt.After(skewed) && now.Sub(sentAt) < eventCooldown {
 					continue
 				}
 			}
@@ -418,8 +1374,12 @@ func (m *Monitor) FilterRecentlySent(groups []models.Event, cooldown time.Durati
 
 // RecordNotified records all markets in the given groups as notified at the current time.
 // Call this after a successful Telegram send to enable cooldown deduplication.
+// With an alert budget configured, each market's daily count is also incremented.
 func (m *Monitor) RecordNotified(groups []models.Event) {
-	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.clock.Now()
+	var ids []string
 	for _, group := range groups {
 		for _, change := range group.Markets {
 			m.notifiedMarkets[change.EventID] = notifiedRecord{
@@ -427,6 +1387,625 @@ func (m *Monitor) RecordNotified(groups []models.Event) {
 				NewProb:   change.NewProbability,
 				SentAt:    now,
 			}
+			ids = append(ids, change.EventID)
+			if m.cfg.EventCooldownMultiplier > 0 {
+				m.notifiedEvents[eventDirection{group.ID, change.Direction}] = now
+			}
+		}
+	}
+	if m.cfg.MaxAlertsPerMarketPerDay > 0 {
+		if err := m.storage.IncrementAlertCounts(ids, m.alertBudgetWindow(now)); err != nil {
+			logger.Warn("Failed to record alert budget: %v", err)
+		}
+	}
+}
+
+// RestoreEventCooldowns seeds the event-level cooldown from alerts stored in
+// the last window, so a restart doesn't re-fire events notified just before
+// it. Returns the number of event/direction pairs restored.
+func (m *Monitor) RestoreEventCooldowns(window time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg.EventCooldownMultiplier <= 0 || window <= 0 {
+		return 0, nil
+	}
+	alerts, err := m.storage.GetAlertsSince(m.clock.Now().Add(-window))
+	if err != nil {
+		return 0, err
+	}
+	for _, a := range alerts {
+		// Grouped as by groupByEvent
+		eventID := a.OriginalEventID
+		if eventID == "" {
+			eventID = a.EventID
+		}
+		key := eventDirection{eventID, a.Direction}
+		if a.DetectedAt.After(m.notifiedEvents[key]) {
+			m.notifiedEvents[key] = a.DetectedAt
+		}
+	}
+	return len(m.notifiedEvents), nil
+}
+
+// alertBudgetWindow returns the start of the daily alert-budget window
+// containing now: the most recent AlertBudgetResetHour:00 UTC.
+func (m *Monitor) alertBudgetWindow(now time.Time) time.Time {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), m.cfg.AlertBudgetResetHour, 0, 0, 0, time.UTC)
+	if start.After(now) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+// FilterAlertBudget removes markets from groups that have already been notified
+// MaxAlertsPerMarketPerDay times in the current daily window. Groups that
+// become empty are dropped. A count that can't be read keeps the market, so a
+// storage hiccup never silences alerts. Returns groups unchanged when the
+// budget is disabled.
+func (m *Monitor) FilterAlertBudget(groups []models.Event) []models.Event {
+	if m.cfg.MaxAlertsPerMarketPerDay <= 0 {
+		return groups
+	}
+	window := m.alertBudgetWindow(m.clock.Now())
+	result := []models.Event{}
+
+	for _, group := range groups {
+		var filtered []models.Change
+		for _, change := range group.Markets {
+			count, err := m.storage.GetAlertCount(change.EventID, window)
+			if err != nil {
+				logger.Warn("Failed to read alert budget for %s: %v", change.EventID, err)
+			} else if count >= m.cfg.MaxAlertsPerMarketPerDay {
+				logger.Debug("Alert budget exhausted for %s (%d/%d today)", change.EventID, count, m.cfg.MaxAlertsPerMarketPerDay)
+				continue
+			}
+			filtered = append(filtered, change)
+		}
+
+		if len(filtered) == 0 {
+			continue
+		}
+
+		newGroup := group
+		newGroup.Markets = filtered
+		newGroup.BestScore = 0
+		for _, c := range filtered {
+			if c.SignalScore > newGroup.BestScore {
+				newGroup.BestScore = c.SignalScore
+			}
+		}
+		result = append(result, newGroup)
+	}
+	return result
+}
+
+// minCoverageSamples is how many cycles must be observed before a coverage drop
+// can be flagged, so the first few cycles after startup don't set a noisy baseline.
+const minCoverageSamples = 3
+
+// ObserveCoverage records the number of markets processed in a cycle and compares
+// it against the rolling average of the previous cycles. It returns that average and
+// whether this cycle starts a coverage drop (count < CoverageDropFraction × average).
+// Only the first cycle of a drop streak reports true; the streak ends when coverage
+// recovers. Dropped cycles still enter the average, so a permanent reduction
+// eventually becomes the new baseline.
+func (m *Monitor) ObserveCoverage(processed int) (float64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var baseline float64
+	if len(m.coverageHistory) > 0 {
+		sum := 0
+		for _, n := range m.coverageHistory {
+			sum += n
+		}
+		baseline = float64(sum) / float64(len(m.coverageHistory))
+	}
+
+	dropped := m.cfg.CoverageDropFraction > 0 &&
+		len(m.coverageHistory) >= minCoverageSamples &&
+		float64(processed) < m.cfg.CoverageDropFraction*baseline
+	alert := dropped && !m.coverageDropped
+	m.coverageDropped = dropped
+
+	if m.frozen() {
+		return baseline, alert
+	}
+	m.coverageHistory = append(m.coverageHistory, processed)
+	if len(m.coverageHistory) > m.cfg.CoverageWindow {
+		m.coverageHistory = m.coverageHistory[len(m.coverageHistory)-m.cfg.CoverageWindow:]
+	}
+	return baseline, alert
+}
+
+// ForgetMissing records which markets appeared in this cycle's fetch and evicts
+// the in-memory state of markets absent for MissingCyclesBeforeCleanup
+// consecutive cycles (rotated out upstream or resolved). An event's liquidity
+// baseline is evicted once none of its markets are tracked. Returns the number
+// of markets evicted; always 0 when cleanup is disabled.
+func (m *Monitor) ForgetMissing(markets []models.Market) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg.MissingCyclesBeforeCleanup <= 0 {
+		return 0
+	}
+
+	present := make(map[string]bool, len(markets))
+	for _, market := range markets {
+		present[market.ID] = true
+		if t, ok := m.tracked[market.ID]; ok {
+			t.Missed = 0
+		} else {
+			m.tracked[market.ID] = &trackedMarket{EventID: market.EventID}
+		}
+	}
+
+	evicted := 0
+	for id, t := range m.tracked {
+		if present[id] {
+			continue
+		}
+		t.Missed++
+		if t.Missed < m.cfg.MissingCyclesBeforeCleanup {
+			continue
+		}
+		m.forgetMarket(id)
+		evicted++
+		logger.Debug("Market %s no longer tracked (absent for %d cycles)", id, t.Missed)
+	}
+
+	if evicted > 0 {
+		liveEvents := make(map[string]bool, len(m.tracked))
+		for _, t := range m.tracked {
+			liveEvents[t.EventID] = true
+		}
+		// Keys are event IDs, or tracked composite IDs under PerMarketLiquidity
+		for key := range m.liquidityStats {
+			if !liveEvents[key] && m.tracked[key] == nil {
+				delete(m.liquidityStats, key)
+			}
+		}
+		for key := range m.compactLiquidityStats {
+			if !liveEvents[key] && m.tracked[key] == nil {
+				delete(m.compactLiquidityStats, key)
+			}
+		}
+	}
+	return evicted
+}
+
+// StateSize reports how many markets hold in-memory score state and how many
+// are in the notification cooldown table. Safe to call while a cycle runs.
+func (m *Monitor) StateSize() (markets, notified int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.scoreStats) + len(m.compactScoreStats), len(m.notifiedMarkets)
+}
+
+// forgetMarket drops a market's in-memory state. Its event's liquidity
+// baseline is left to ForgetMissing.
+func (m *Monitor) forgetMarket(id string) {
+	delete(m.tracked, id)
+	delete(m.notifiedMarkets, id)
+	delete(m.scoreStats, id)
+	delete(m.compactScoreStats, id)
+	delete(m.extremeStreaks, id)
+	delete(m.stateUpdated, id)
+	delete(m.liquidityStats, id) // only keyed by market under PerMarketLiquidity
+	delete(m.compactLiquidityStats, id)
+}
+
+// ladderNumber matches the threshold in a ladder question: an optional "$", a
+// number with optional thousands separators and decimals, and an optional k/m/b
+// multiplier ("$100k", "1,500", "2.5M").
+var ladderNumber = regexp.MustCompile(`\$?(\d[\d,]*(?:\.\d+)?)\s*([kKmMbB]\b)?`)
+
+// Comparison words that fix which way a ladder's probabilities must run.
+var (
+	ladderAbove = regexp.MustCompile(`(?i)\b(above|over|more than|greater than|at least|exceed|exceeds|reach|reaches|hit|hits)\b|>|≥`)
+	ladderBelow = regexp.MustCompile(`(?i)\b(below|under|less than|fewer than|at most)\b|<|≤`)
+)
+
+// ladderRung is one market of a threshold ladder.
+type ladderRung struct {
+	market    models.Market
+	numbers   []float64 // every number in the question, in order
+	threshold float64
+}
+
+// parseLadderQuestion masks every number in question, returning the template,
+// the numbers (k/m/b multipliers applied) and the ladder direction: +1 when a
+// higher threshold must be no more likely ("above"), -1 when it must be no less
+// likely ("below"). ok is false without numbers or an unambiguous comparison.
+func parseLadderQuestion(question string) (template string, numbers []float64, dir int, ok bool) {
+	locs := ladderNumber.FindAllStringSubmatchIndex(question, -1)
+	if len(locs) == 0 {
+		return "", nil, 0, false
+	}
+	var b strings.Builder
+	prev := 0
+	for _, loc := range locs {
+		v, err := strconv.ParseFloat(strings.ReplaceAll(question[loc[2]:loc[3]], ",", ""), 64)
+		if err != nil {
+			return "", nil, 0, false
+		}
+		if loc[4] >= 0 {
+			switch strings.ToLower(question[loc[4]:loc[5]]) {
+			case "k":
+				v *= 1e3
+			case "m":
+				v *= 1e6
+			case "b":
+				v *= 1e9
+			}
+		}
+		numbers = append(numbers, v)
+		b.WriteString(question[prev:loc[0]])
+		b.WriteString("#")
+		prev = loc[1]
+	}
+	b.WriteString(question[prev:])
+	template = b.String()
+
+	above, below := ladderAbove.MatchString(template), ladderBelow.MatchString(template)
+	switch {
+	case above && !below:
+		dir = 1
+	case below && !above:
+		dir = -1
+	default:
+		return "", nil, 0, false
+	}
+	return template, numbers, dir, true
+}
+
+// ladderThresholds sets each rung's threshold to the one number that varies
+// across the rungs (dates and other shared numbers stay fixed). It returns
+// false unless exactly one position varies.
+func ladderThresholds(rungs []ladderRung) bool {
+	varying := -1
+	for pos := range rungs[0].numbers {
+		for _, r := range rungs[1:] {
+			if r.numbers[pos] != rungs[0].numbers[pos] {
+				if varying >= 0 && varying != pos {
+					return false
+				}
+				varying = pos
+				break
+			}
+		}
+	}
+	if varying < 0 {
+		return false
+	}
+	for i := range rungs {
+		rungs[i].threshold = rungs[i].numbers[varying]
+	}
+	return true
+}
+
+// DetectLadderInconsistencies finds threshold ladders among each event's
+// markets — questions identical except for one number, with an "above" or
+// "below" comparison — and returns a KindInconsistency change for each rung
+// priced more than LadderTolerance above the adjacent rung it implies (e.g.
+// "above $150k" at 40% while "above $100k" is at 30%). OldProbability is the
+// implied rung's price (named in RelatedQuestion), NewProbability the violating
+// rung's. Like uncertainty alerts, a rung is reported once per violation and
+// re-armed when it is consistent again. Returns nil when the check is disabled.
+func (m *Monitor) DetectLadderInconsistencies(markets []models.Market) []models.Change {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.cfg.DetectLadderInconsistency {
+		return nil
+	}
+
+	type ladderKey struct{ eventID, template string }
+	ladders := make(map[ladderKey][]ladderRung)
+	dirs := make(map[ladderKey]int)
+	var order []ladderKey
+	for _, market := range markets {
+		if market.Closed {
+			continue
+		}
+		template, numbers, dir, ok := parseLadderQuestion(market.MarketQuestion)
+		if !ok {
+			continue
+		}
+		key := ladderKey{market.EventID, template}
+		if _, seen := ladders[key]; !seen {
+			order = append(order, key)
+		}
+		ladders[key] = append(ladders[key], ladderRung{market: market, numbers: numbers})
+		dirs[key] = dir
+	}
+
+	now := m.clock.Now()
+	var result []models.Change
+	flagged := make(map[string]bool)
+	for _, key := range order {
+		rungs := ladders[key]
+		if len(rungs) < 2 || !ladderThresholds(rungs) {
+			continue
+		}
+		// Order rungs from most to least likely: ascending thresholds for
+		// "above" ladders, descending for "below".
+		sort.SliceStable(rungs, func(i, j int) bool {
+			if dirs[key] > 0 {
+				return rungs[i].threshold < rungs[j].threshold
+			}
+			return rungs[i].threshold > rungs[j].threshold
+		})
+		for i := 1; i < len(rungs); i++ {
+			implied, rung := rungs[i-1].market, rungs[i].market
+			gap := rung.YesProbability - implied.YesProbability
+			if gap <= m.cfg.LadderTolerance {
+				continue
+			}
+			flagged[rung.ID] = true
+			if m.inconsistentFlagged[rung.ID] {
+				continue
+			}
+			result = append(result, models.Change{
+				ID:              uuid.New().String(),
+				EventID:         rung.ID,
+				OriginalEventID: rung.EventID,
+				EventTitle:      rung.Title,
+				EventURL:        rung.EventURL,
+				MarketID:        rung.MarketID,
+				MarketQuestion:  rung.MarketQuestion,
+				RelatedQuestion: implied.MarketQuestion,
+				Kind:            models.KindInconsistency,
+				Magnitude:       gap,
+				Direction:       "increase",
+				OldProbability:  implied.YesProbability,
+				NewProbability:  rung.YesProbability,
+				DetectedAt:      now,
+				SignalScore:     gap,
+			})
+		}
+	}
+	// Rungs no longer violating are re-armed.
+	m.inconsistentFlagged = flagged
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].SignalScore > result[j].SignalScore
+	})
+	return result
+}
+
+// ObserveResolution counts, for each fetched market, consecutive polls priced
+// within resolutionExtreme of 0 or 1, and returns the markets that crossed
+// ResolutionPendingCycles this poll (each is reported once per streak). A price
+// leaving the extreme resets its streak. Returns nil when the check is disabled.
+func (m *Monitor) ObserveResolution(markets []models.Market) []models.Market {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg.ResolutionPendingCycles <= 0 {
+		return nil
+	}
+
+	var resolving []models.Market
+	for _, market := range markets {
+		if market.Closed {
+			// Already resolved; nothing is pending
+			continue
+		}
+		p := market.YesProbability
+		if p > resolutionExtreme && p < 1-resolutionExtreme {
+			delete(m.extremeStreaks, market.ID)
+			continue
+		}
+		m.extremeStreaks[market.ID]++
+		m.touchState(market.ID)
+		if m.extremeStreaks[market.ID] == m.cfg.ResolutionPendingCycles+1 {
+			resolving = append(resolving, market)
+		}
+	}
+	return resolving
+}
+
+// ResolvedOutcome reports how a closed market resolved: 1 for Yes, 0 for No.
+// ok is false while the market is open or its final price is not within
+// resolutionExtreme of 0 or 1 (e.g. a 50/50 void).
+func ResolvedOutcome(market models.Market) (outcome int, ok bool) {
+	if !market.Closed {
+		return 0, false
+	}
+	switch p := market.YesProbability; {
+	case p >= 1-resolutionExtreme:
+		return 1, true
+	case p <= resolutionExtreme:
+		return 0, true
+	}
+	return 0, false
+}
+
+// ClosedMarket pairs a market's last stored open state with its state on the
+// poll that found it closed.
+type ClosedMarket struct {
+	Last  models.Market
+	Final models.Market
+}
+
+// Resolutions returns the markets that closed this poll as KindResolved
+// changes from their last open price to their final price, grouped by event
+// in input order. Callers send them like other alerts, then call
+// ForgetResolved.
+func (m *Monitor) Resolutions(closed []ClosedMarket) []models.Event {
+	if len(closed) == 0 {
+		return nil
+	}
+	now := m.clock.Now()
+	changes := make([]models.Change, 0, len(closed))
+	for _, c := range closed {
+		market := c.Final
+		direction := "increase"
+		if market.YesProbability < c.Last.YesProbability {
+			direction = "decrease"
+		}
+		change := models.Change{
+			ID:              uuid.New().String(),
+			EventID:         market.ID,
+			OriginalEventID: market.EventID,
+			EventTitle:      market.Title,
+			EventURL:        market.EventURL,
+			MarketID:        market.MarketID,
+			MarketQuestion:  market.MarketQuestion,
+			Kind:            models.KindResolved,
+			Magnitude:       math.Abs(market.YesProbability - c.Last.YesProbability),
+			Direction:       direction,
+			OldProbability:  c.Last.YesProbability,
+			NewProbability:  market.YesProbability,
+			DetectedAt:      now,
+			Side:            m.sideFor(market),
+		}
+		if outcome, ok := ResolvedOutcome(market); ok {
+			change.Outcome = models.SideYes
+			if outcome == 0 {
+				change.Outcome = models.SideNo
+			}
+		}
+		changes = append(changes, change)
+	}
+	return groupByEvent(changes)
+}
+
+// ForgetResolved drops the in-memory state of the markets in resolved groups:
+// a closed market never alerts again.
+func (m *Monitor) ForgetResolved(groups []models.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, g := range groups {
+		for _, change := range g.Markets {
+			m.forgetMarket(change.EventID)
+		}
+	}
+}
+
+// ExcludeResolving drops markets that ObserveResolution considers pending
+// resolution. Their snapshots are still recorded.
+func (m *Monitor) ExcludeResolving(markets []models.Market) []models.Market {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.cfg.ResolutionPendingCycles <= 0 {
+		return markets
+	}
+	result := make([]models.Market, 0, len(markets))
+	for _, market := range markets {
+		if m.extremeStreaks[market.ID] > m.cfg.ResolutionPendingCycles {
+			continue
+		}
+		result = append(result, market)
+	}
+	return result
+}
+
+// DetectLiquidityDrops compares each event's current liquidity against its EWMA
+// baseline and returns a KindLiquidityDrop change for events that fell more than
+// LiquidityDropFraction below it. Gamma reports liquidity per event, so at most
+// one change is returned per event (carrying the first market seen); under
+// Config.PerMarketLiquidity each market is compared against its own baseline.
+// Like coverage drops, only the first cycle of a drop streak is reported.
+// Returns nil when the check is disabled.
+func (m *Monitor) DetectLiquidityDrops(markets []models.Market) []models.Change {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg.LiquidityDropFraction <= 0 {
+		return nil
+	}
+
+	var changes []models.Change
+	seen := make(map[string]bool)
+	now := m.clock.Now()
+	frozen := m.frozen()
+
+	for _, market := range markets {
+		key := m.liquidityKey(market)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		st, ok := m.loadLiquidityStat(key)
+		if !ok {
+			if !frozen {
+				m.storeLiquidityStat(key, liquidityStat{AvgDepth: market.Liquidity, Count: 1})
+			}
+			continue
+		}
+
+		// Compare against the baseline before folding in this observation.
+		baseline := st.AvgDepth
+		dropped := st.Count >= liquidityMinSamples && baseline > 0 &&
+			market.Liquidity < (1-m.cfg.LiquidityDropFraction)*baseline
+		if dropped && !st.Dropped {
+			changes = append(changes, models.Change{
+				ID:              uuid.New().String(),
+				EventID:         market.ID,
+				OriginalEventID: market.EventID,
+				EventTitle:      market.Title,
+				EventURL:        market.EventURL,
+				MarketID:        market.MarketID,
+				MarketQuestion:  market.MarketQuestion,
+				Kind:            models.KindLiquidityDrop,
+				Magnitude:       1 - market.Liquidity/baseline,
+				Direction:       "decrease",
+				OldProbability:  market.YesProbability,
+				NewProbability:  market.YesProbability,
+				OldLiquidity:    baseline,
+				NewLiquidity:    market.Liquidity,
+				DetectedAt:      now,
+			})
+		}
+		st.Dropped = dropped
+		if !frozen {
+			st.AvgDepth = (1-liquidityAlpha)*st.AvgDepth + liquidityAlpha*market.Liquidity
+			st.Count++
+		}
+		m.storeLiquidityStat(key, st)
+	}
+	return changes
+}
+
+// UncertaintyGain returns how much closer to a coin flip the probability moved:
+// the increase in 0.5-|p-0.5| from pOld to pNew. Negative when moving away from 0.50.
+func UncertaintyGain(pOld, pNew float64) float64 {
+	return math.Abs(pOld-0.5) - math.Abs(pNew-0.5)
+}
+
+// DetectUncertainty returns a KindUncertainty copy of each change whose
+// UncertaintyGain reaches UncertaintyThreshold, scored by that gain. This is
+// independent of directional scoring: 80%→55% qualifies even if its composite
+// score is low. A market is reported once per convergence and re-armed when its
+// gain drops below the threshold. Returns nil when the check is disabled.
+func (m *Monitor) DetectUncertainty(changes []models.Change) []models.Change {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.cfg.AlertOnUncertainty {
+		return nil
+	}
+
+	var result []models.Change
+	flagged := make(map[string]bool)
+	for _, change := range changes {
+		gain := UncertaintyGain(change.OldProbability, change.NewProbability)
+		if gain < m.cfg.UncertaintyThreshold {
+			continue
+		}
+		flagged[change.EventID] = true
+		if m.uncertainFlagged[change.EventID] {
+			continue
 		}
+
+		change.Kind = models.KindUncertainty
+		change.SignalScore = gain
+		result = append(result, change)
 	}
+	// Markets absent from this cycle's qualifying set are re-armed.
+	m.uncertainFlagged = flagged
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].SignalScore > result[j].SignalScore
+	})
+	return result
 }
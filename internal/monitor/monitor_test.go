@@ -1,7 +1,11 @@
 package monitor
 
 import (
+	"fmt"
 	"math"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -288,6 +292,59 @@ func TestKLDivergence(t *testing.T) {
 	}
 }
 
+func TestJSDivergenceAndHellinger(t *testing.T) {
+	for _, tt := range []struct{ pOld, pNew float64 }{
+		{0.5, 0.6}, {0.3, 0.7}, {0.7, 0.7}, {0, 0.05}, {1, 0.95}, {0, 1}, {-0.1, 1.2},
+	} {
+		js, h := JSDivergence(tt.pOld, tt.pNew), HellingerDistance(tt.pOld, tt.pNew)
+		for name, got := range map[string]float64{"JSDivergence": js, "HellingerDistance": h} {
+			if math.IsNaN(got) || math.IsInf(got, 0) || got < 0 {
+				t.Errorf("%s(%v, %v) = %v, want finite and >= 0", name, tt.pOld, tt.pNew, got)
+			}
+		}
+		if js > math.Ln2+1e-12 || h > 1 {
+			t.Errorf("(%v, %v): JS %v above ln 2 or Hellinger %v above 1", tt.pOld, tt.pNew, js, h)
+		}
+		if rev := JSDivergence(tt.pNew, tt.pOld); math.Abs(rev-js) > 1e-12 {
+			t.Errorf("JSDivergence not symmetric at (%v, %v): %v vs %v", tt.pOld, tt.pNew, js, rev)
+		}
+	}
+
+	if got := JSDivergence(0.5, 0.6); math.Abs(got-0.005059) > 1e-5 {
+		t.Errorf("JSDivergence(0.5, 0.6) = %v, want ≈0.005059", got)
+	}
+	if got := HellingerDistance(0.5, 0.6); math.Abs(got-0.071161) > 1e-5 {
+		t.Errorf("HellingerDistance(0.5, 0.6) = %v, want ≈0.071161", got)
+	}
+	if got := HellingerDistance(0, 1); math.Abs(got-1) > 1e-12 {
+		t.Errorf("HellingerDistance(0, 1) = %v, want 1", got)
+	}
+}
+
+func TestScoreAndRank_DivergenceMetric(t *testing.T) {
+	markets := map[string]*models.Market{
+		"e1": {ID: "e1", EventID: "e1", Volume24hr: 100_000, Title: "Test", Category: "test"},
+	}
+	changes := []models.Change{
+		{ID: "c1", EventID: "e1", OldProbability: 0.50, NewProbability: 0.60, Magnitude: 0.10, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+	}
+	for metric, want := range map[string]float64{
+		"":                  KLDivergence(0.5, 0.6),
+		DivergenceKL:        KLDivergence(0.5, 0.6),
+		DivergenceJS:        JSDivergence(0.5, 0.6),
+		DivergenceHellinger: HellingerDistance(0.5, 0.6),
+	} {
+		mon := New(mustStorage(t, 100, 50), Config{DivergenceMetric: metric})
+		result := mon.ScoreAndRank(changes, markets, 0, 5, 25000.0, 0.0, 0.0)
+		if len(result) != 1 {
+			t.Fatalf("%q: got %d groups, want 1", metric, len(result))
+		}
+		if got := result[0].Markets[0].Components.KL; got != want {
+			t.Errorf("%q: divergence = %v, want %v", metric, got, want)
+		}
+	}
+}
+
 // ─── T012: TestLogVolumeWeight ────────────────────────────────────────────────
 
 func TestLogVolumeWeight(t *testing.T) {
@@ -407,6 +464,78 @@ func TestHistoricalSNR(t *testing.T) {
 	}
 }
 
+func TestConfidenceWeightedSNR(t *testing.T) {
+	const netChange = 0.04
+	volatile := []float64{0.50, 0.55, 0.48, 0.56, 0.47, 0.55, 0.49, 0.56, 0.48, 0.55, 0.50, 0.56}
+	sampleSigma, _ := deltaSigma(makeSnaps(volatile[:5]))
+
+	tests := []struct {
+		name  string
+		probs []float64
+		min   int
+		want  float64
+	}{
+		// Too few snapshots for a sample σ: defaultSigma (0.01) outright, where
+		// HistoricalSNR would fall back to a neutral 1.0.
+		{"1 snapshot", volatile[:1], 10, netChange / defaultSigma},
+		{"2 snapshots", volatile[:2], 10, netChange / defaultSigma},
+		// Half the required history: σ is the midpoint of sample and default.
+		{"5 of 10 snapshots", volatile[:5], 10, netChange / (0.5*sampleSigma + 0.5*defaultSigma)},
+		// Enough history: the market's own σ, identical to HistoricalSNR.
+		{"many snapshots", volatile, 10, HistoricalSNR(makeSnaps(volatile), netChange)},
+		{"blending disabled", volatile[:2], 0, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConfidenceWeightedSNR(makeSnaps(tt.probs), netChange, tt.min)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ConfidenceWeightedSNR = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlendedSigma(t *testing.T) {
+	// A long quiet history followed by a volatile recent stretch.
+	probs := []float64{0.50, 0.501, 0.50, 0.501, 0.50, 0.501, 0.50, 0.501, 0.50, 0.501, 0.50, 0.501}
+	probs = append(probs, 0.55, 0.48, 0.56, 0.47, 0.55, 0.49)
+	snaps := makeSnaps(probs)
+
+	all, _ := deltaSigma(snaps)
+	recent, _ := deltaSigma(snaps[len(snaps)-6:])
+
+	tests := []struct {
+		name string
+		w    float64
+		want float64
+	}{
+		{"weight 0 is all history", 0, all},
+		{"weight 1 is recent only", 1, recent},
+		{"half weight", 0.5, 0.5*all + 0.5*recent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := BlendedSigma(snaps, 6, tt.w)
+			if !ok {
+				t.Fatal("BlendedSigma returned !ok")
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("BlendedSigma = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if recent <= all {
+		t.Errorf("recent σ %v should exceed all-history σ %v after a regime shift", recent, all)
+	}
+
+	// Too few snapshots overall: no σ at all.
+	if _, ok := BlendedSigma(makeSnaps(probs[:2]), 6, 0.5); ok {
+		t.Error("BlendedSigma with 2 snapshots should not be ok")
+	}
+}
+
 // ─── T014: TestTrajectoryConsistency ─────────────────────────────────────────
 
 func TestTrajectoryConsistency(t *testing.T) {
@@ -465,6 +594,41 @@ func TestTrajectoryConsistency(t *testing.T) {
 	}
 }
 
+// TestClippedTrajectoryConsistency_OutlierDecays verifies that with tc_clip an
+// outlier poll followed by quiet back-and-forth polls stops looking directional,
+// whereas unclamped the single jump keeps TC near 1 for the whole window.
+func TestClippedTrajectoryConsistency_OutlierDecays(t *testing.T) {
+	probs := []float64{0.40, 0.70} // one extreme poll
+	for range 3 {
+		probs = append(probs, 0.69, 0.70) // quiet polls
+	}
+
+	if got := ClippedTrajectoryConsistency(makeSnaps(probs), 0); got < 0.8 {
+		t.Errorf("unclamped TC = %.3f, want ≥ 0.8 (outlier dominates)", got)
+	}
+	if got, want := ClippedTrajectoryConsistency(makeSnaps(probs), 0), TrajectoryConsistency(makeSnaps(probs)); got != want {
+		t.Errorf("clip=0 TC = %v, want TrajectoryConsistency %v", got, want)
+	}
+
+	// Under clipping, each further pair of quiet polls pulls TC down.
+	prev := ClippedTrajectoryConsistency(makeSnaps(probs[:2]), 0.02)
+	for n := 4; n <= len(probs); n += 2 {
+		got := ClippedTrajectoryConsistency(makeSnaps(probs[:n]), 0.02)
+		if got >= prev {
+			t.Errorf("clipped TC after %d snapshots = %.3f, want < %.3f", n, got, prev)
+		}
+		prev = got
+	}
+	if prev > 0.3 {
+		t.Errorf("clipped TC after quiet polls = %.3f, want ≤ 0.3", prev)
+	}
+
+	// A clean monotonic move is unaffected by clipping.
+	if got := ClippedTrajectoryConsistency(makeSnaps([]float64{0.40, 0.50, 0.60, 0.70}), 0.02); got != 1.0 {
+		t.Errorf("clipped TC of monotonic move = %v, want 1.0", got)
+	}
+}
+
 // ─── T015: TestScoring — 8 comprehensive cases ───────────────────────────────
 
 func TestScoring(t *testing.T) {
@@ -627,6 +791,69 @@ func TestScoreAndRank_TopKLimit(t *testing.T) {
 	}
 }
 
+func TestRerank(t *testing.T) {
+	alert := func(id, event string, oldP, newP, volume float64) models.Change {
+		return models.Change{
+			ID: id, EventID: event + ":m", OriginalEventID: event, EventTitle: event,
+			Magnitude: math.Abs(newP - oldP), OldProbability: oldP, NewProbability: newP,
+			SignalScore: 0.5,
+			Components: &models.ScoreComponents{
+				TC: 1.0, Sigma: 0.02, HistorySnapshots: 20, Volume24hr: volume,
+			},
+		}
+	}
+	alerts := []models.Change{
+		alert("big", "e1", 0.40, 0.60, 100000),
+		alert("small", "e2", 0.50, 0.53, 100000),
+		alert("thin", "e3", 0.40, 0.60, 1000),
+		{ID: "unscored", EventID: "e4:m", OriginalEventID: "e4", Magnitude: 0.3, OldProbability: 0.2, NewProbability: 0.5},
+	}
+
+	m := New(mustStorage(t, 100, 50))
+	groups := m.Rerank(alerts, nil, 0.01, 10, 25000, 0, 0)
+	var ids []string
+	for _, g := range groups {
+		ids = append(ids, g.Markets[0].ID)
+	}
+	// The unscored alert is skipped; the thin-volume move ranks below the
+	// liquid one of the same size.
+	if len(ids) < 2 || ids[0] != "big" || !slices.Contains(ids, "thin") || slices.Contains(ids, "unscored") {
+		t.Fatalf("rerank order = %v, want big first, thin present, unscored absent", ids)
+	}
+
+	c := groups[0].Markets[0]
+	want := CompositeScore(KLDivergence(0.40, 0.60), LogVolumeWeight(100000, 25000), 5.0, 1.0)
+	if math.Abs(c.SignalScore-want) > 1e-12 {
+		t.Errorf("rescored = %v, want %v", c.SignalScore, want)
+	}
+	if c.Components.VolumeRef != 25000 || c.Components.Threshold != 0.01 {
+		t.Errorf("components not updated: %+v", c.Components)
+	}
+	if alerts[0].Components.VolumeRef != 0 {
+		t.Error("Rerank modified the input components")
+	}
+
+	// A higher bar drops the weaker alerts.
+	if got := m.Rerank(alerts, nil, c.SignalScore, 10, 25000, 0, 0); len(got) != 1 {
+		t.Errorf("with min score at the top score: %d groups, want 1", len(got))
+	}
+
+	// A category override holds its markets to its own bar and ceiling.
+	m = New(mustStorage(t, 100, 50), Config{CategoryScore: map[string]ScoreBounds{
+		"crypto": {MinScore: c.SignalScore * 2},
+		"tech":   {ScoreCeiling: 0.01},
+	}})
+	categories := map[string]string{"e1:m": "Crypto", "e3:m": "tech"}
+	for _, g := range m.Rerank(alerts, categories, 0.01, 10, 25000, 0, 0) {
+		if id := g.Markets[0].ID; id == "big" || id == "thin" {
+			t.Errorf("%s passed despite its category override", id)
+		}
+		if g.Markets[0].ID == "small" && g.Markets[0].Components.Threshold != 0.01 {
+			t.Errorf("small threshold = %v, want the global 0.01", g.Markets[0].Components.Threshold)
+		}
+	}
+}
+
 func TestScoreAndRank_NeverNil(t *testing.T) {
 	store := mustStorage(t, 100, 50)
 	mon := New(store)
@@ -655,6 +882,31 @@ func TestScoreAndRank_MinScoreFilters(t *testing.T) {
 	}
 }
 
+func TestScoreAndRank_CategoryScore(t *testing.T) {
+	mon := New(mustStorage(t, 100, 50), Config{
+		CategoryScore: map[string]ScoreBounds{
+			"crypto": {MinScore: 999},
+			"world":  {ScoreCeiling: 1e-9},
+		},
+	})
+
+	markets := map[string]*models.Market{}
+	var changes []models.Change
+	for _, category := range []string{"Crypto", "World", "Sports"} {
+		id := strings.ToLower(category)
+		markets[id] = &models.Market{ID: id, EventID: id, Volume24hr: 100_000, Title: category, Category: category}
+		changes = append(changes, models.Change{ID: "c-" + id, EventID: id, OldProbability: 0.40, NewProbability: 0.60,
+			Magnitude: 0.20, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()})
+	}
+
+	// crypto misses its raised bar, world hits its ceiling (not alerting by
+	// default), sports has no override and passes the global bar
+	result := mon.ScoreAndRank(changes, markets, 0.001, 5, 25000.0, 0.0, 0.0)
+	if len(result) != 1 || result[0].ID != "sports" {
+		t.Fatalf("got %d groups (%+v), want only sports", len(result), result)
+	}
+}
+
 func TestScoreAndRank_TopKZero(t *testing.T) {
 	store := mustStorage(t, 100, 50)
 	mon := New(store)
@@ -718,6 +970,44 @@ func TestScoreAndRank_PreScoreFilters(t *testing.T) {
 	}
 }
 
+func TestScoreAndRank_ProbabilityBand(t *testing.T) {
+	markets := map[string]*models.Market{}
+	for _, id := range []string{"middle", "old-tail", "new-tail", "edge"} {
+		markets[id] = &models.Market{ID: id, EventID: id, Volume24hr: 500_000, Title: id, Category: "test"}
+	}
+	changes := []models.Change{
+		{ID: "c1", EventID: "middle", OldProbability: 0.40, NewProbability: 0.55, Magnitude: 0.15, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+		{ID: "c2", EventID: "old-tail", OldProbability: 0.03, NewProbability: 0.20, Magnitude: 0.17, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+		{ID: "c3", EventID: "new-tail", OldProbability: 0.80, NewProbability: 0.97, Magnitude: 0.17, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+		{ID: "c4", EventID: "edge", OldProbability: 0.05, NewProbability: 0.25, Magnitude: 0.20, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+	}
+
+	tests := []struct {
+		name     string
+		min, max float64
+		want     []string
+	}{
+		{"unset band passes all", 0, 0, []string{"edge", "middle", "new-tail", "old-tail"}},
+		{"5–95% band", 0.05, 0.95, []string{"edge", "middle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mon := New(mustStorage(t, 100, 50), Config{MinProbability: tt.min, MaxProbability: tt.max})
+			result := mon.ScoreAndRank(changes, markets, 0.0, 10, 25000.0, 0.0, 0.0)
+
+			var got []string
+			for _, g := range result {
+				got = append(got, g.ID)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("alerted = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestScoreAndRank_ConfirmationEntry_BypassesMinAbsChange(t *testing.T) {
 	store := mustStorage(t, 100, 50)
 	mon := New(store)
@@ -1254,6 +1544,75 @@ func TestScoreAndRank_TopKAtGroupLevel(t *testing.T) {
 
 // TestFilterRecentlySent_SuppressesDuplicates verifies that a market notified
 // recently with the same direction is suppressed within the cooldown window.
+func TestMergeGroups(t *testing.T) {
+	m := New(mustStorage(t, 100, 50))
+	change := func(id string, score, newProb float64) models.Change {
+		return models.Change{ID: uuid.New().String(), EventID: id, SignalScore: score, NewProbability: newProb}
+	}
+
+	pending := []models.Event{
+		{ID: "e1", Title: "Old title", BestScore: 0.5, Markets: []models.Change{change("e1:a", 0.5, 0.60), change("e1:b", 0.2, 0.30)}},
+		{ID: "e2", BestScore: 0.3, Markets: []models.Change{change("e2:a", 0.3, 0.40)}},
+	}
+	latest := []models.Event{
+		{ID: "e1", Title: "New title", BestScore: 0.4, Markets: []models.Change{change("e1:b", 0.4, 0.35)}},
+		{ID: "e3", BestScore: 0.9, Markets: []models.Change{change("e3:a", 0.9, 0.80)}},
+	}
+
+	got := m.MergeGroups(pending, latest)
+	var ids []string
+	for _, g := range got {
+		ids = append(ids, g.ID)
+	}
+	if want := []string{"e3", "e1", "e2"}; !slices.Equal(ids, want) {
+		t.Fatalf("group order = %v, want %v", ids, want)
+	}
+
+	e1 := got[1]
+	if e1.Title != "New title" {
+		t.Errorf("e1 title = %q, want the latest cycle's", e1.Title)
+	}
+	if len(e1.Markets) != 2 {
+		t.Fatalf("e1 has %d markets, want 2 (deduped by market)", len(e1.Markets))
+	}
+	if e1.Markets[1].EventID != "e1:b" || e1.Markets[1].NewProbability != 0.35 {
+		t.Errorf("e1:b = %+v, want the latest move (0.35)", e1.Markets[1])
+	}
+	if e1.BestScore != 0.5 {
+		t.Errorf("e1 BestScore = %v, want 0.5", e1.BestScore)
+	}
+
+	// The inputs are left untouched.
+	if pending[0].Markets[1].NewProbability != 0.30 {
+		t.Error("MergeGroups modified the pending groups")
+	}
+}
+
+func TestCollapseSimilarTitles(t *testing.T) {
+	groups := []models.Event{
+		{ID: "e1", Title: "Will the Fed cut rates by June?", BestScore: 0.9},
+		{ID: "e2", Title: "Bitcoin above $100k?", BestScore: 0.8},
+		{ID: "e3", Title: "Will the Fed cut rates by July?", BestScore: 0.5},
+		{ID: "e4", Title: "Will the FED cut rates by June 2025?", BestScore: 0.4},
+	}
+
+	got := collapseSimilarTitles(groups, 0.6)
+	if len(got) != 2 || got[0].ID != "e1" || got[1].ID != "e2" {
+		t.Fatalf("collapsed groups = %+v, want [e1 e2]", got)
+	}
+	if want := []string{groups[2].Title, groups[3].Title}; !slices.Equal(got[0].Related, want) {
+		t.Errorf("e1 Related = %v, want %v", got[0].Related, want)
+	}
+	if len(got[1].Related) != 0 {
+		t.Errorf("e2 Related = %v, want none", got[1].Related)
+	}
+
+	// A strict threshold keeps every group.
+	if got := collapseSimilarTitles(groups, 1.0); len(got) != 4 {
+		t.Errorf("threshold 1.0 kept %d groups, want 4", len(got))
+	}
+}
+
 func TestFilterRecentlySent_SuppressesDuplicates(t *testing.T) {
 	store := mustStorage(t, 100, 50)
 	mon := New(store)
@@ -1375,3 +1734,1095 @@ func TestFilterRecentlySent_PassesAfterCooldown(t *testing.T) {
 		t.Errorf("Expected 1 group after cooldown expired, got %d", len(filtered))
 	}
 }
+
+func TestObserveCoverage(t *testing.T) {
+	m := New(mustStorage(t, 100, 50), Config{CoverageDropFraction: 0.5, CoverageWindow: 4})
+
+	steps := []struct {
+		processed int
+		wantAlert bool
+	}{
+		{100, false}, // warming up
+		{100, false},
+		{40, false}, // below floor but fewer than minCoverageSamples cycles seen
+		{100, false},
+		{30, true},  // first cycle of a drop streak
+		{20, false}, // still dropped, already alerted
+		{100, false},
+		{10, true}, // new streak alerts again
+	}
+	for i, st := range steps {
+		_, alert := m.ObserveCoverage(st.processed)
+		if alert != st.wantAlert {
+			t.Errorf("step %d (processed=%d): alert = %v, want %v", i, st.processed, alert, st.wantAlert)
+		}
+	}
+}
+
+func TestObserveCoverage_Disabled(t *testing.T) {
+	m := New(mustStorage(t, 100, 50))
+	for _, n := range []int{100, 100, 100, 100, 0} {
+		if _, alert := m.ObserveCoverage(n); alert {
+			t.Fatalf("coverage alert fired with coverage_drop_fraction unset")
+		}
+	}
+}
+
+func TestPauseResume(t *testing.T) {
+	m := New(mustStorage(t, 100, 50))
+	if m.Paused() {
+		t.Fatal("new monitor should not be paused")
+	}
+	if !m.Pause() || !m.Paused() {
+		t.Fatal("Pause should transition to paused")
+	}
+	if m.Pause() {
+		t.Error("second Pause should report no change")
+	}
+	if !m.Resume() || m.Paused() {
+		t.Fatal("Resume should transition to running")
+	}
+	if m.Resume() {
+		t.Error("second Resume should report no change")
+	}
+}
+
+func TestAdaptiveThreshold(t *testing.T) {
+	m := New(mustStorage(t, 100, 50), Config{AdaptiveThreshold: true, AdaptiveAlpha: 0.2})
+	const minScore = 0.01
+	const id = "e:m"
+
+	if got := m.adaptiveThreshold(id, minScore); got != minScore {
+		t.Fatalf("unseen market threshold = %v, want floor %v", got, minScore)
+	}
+
+	// Noisy market: scores oscillate around 0.5
+	for i := 0; i < 20; i++ {
+		score := 0.4
+		if i%2 == 0 {
+			score = 0.6
+		}
+		m.observeScore(id, score)
+	}
+	th := m.adaptiveThreshold(id, minScore)
+	if th <= 0.5 || th >= 0.8 {
+		t.Errorf("noisy market threshold = %v, want roughly p90 of its scores (0.5, 0.8)", th)
+	}
+
+	// A quiet market keeps the global floor
+	for i := 0; i < 20; i++ {
+		m.observeScore("quiet", 0.001)
+	}
+	if got := m.adaptiveThreshold("quiet", minScore); got != minScore {
+		t.Errorf("quiet market threshold = %v, want floor %v", got, minScore)
+	}
+
+	// Too few samples falls back to the floor
+	for i := 0; i < adaptiveMinSamples-1; i++ {
+		m.observeScore("new", 1.0)
+	}
+	if got := m.adaptiveThreshold("new", minScore); got != minScore {
+		t.Errorf("under-sampled market threshold = %v, want floor %v", got, minScore)
+	}
+}
+
+func TestCompactState(t *testing.T) {
+	full := New(mustStorage(t, 100, 50), Config{AdaptiveThreshold: true, AdaptiveAlpha: 0.2, LiquidityDropFraction: 0.5})
+	compact := New(mustStorage(t, 100, 50), Config{AdaptiveThreshold: true, AdaptiveAlpha: 0.2, LiquidityDropFraction: 0.5, CompactState: true})
+
+	for i := 0; i < 20; i++ {
+		score := 0.4 + 0.2*float64(i%2)
+		full.observeScore("e:m", score)
+		compact.observeScore("e:m", score)
+	}
+	want := full.adaptiveThreshold("e:m", 0.01)
+	got := compact.adaptiveThreshold("e:m", 0.01)
+	if math.Abs(got-want) > 1e-6 {
+		t.Errorf("compact threshold = %v, want %v", got, want)
+	}
+	if len(compact.scoreStats) != 0 || len(compact.compactScoreStats) != 1 {
+		t.Errorf("compact monitor stored %d full / %d compact score stats, want 0 / 1",
+			len(compact.scoreStats), len(compact.compactScoreStats))
+	}
+
+	// Liquidity drops fire on the same cycles either way.
+	for i, liquidity := range []float64{100000, 100000, 100000, 30000, 20000, 100000, 10000} {
+		markets := []models.Market{{ID: "e1:m1", EventID: "e1", MarketID: "m1", Title: "Event", YesProbability: 0.4, Liquidity: liquidity}}
+		if f, c := len(full.DetectLiquidityDrops(markets)), len(compact.DetectLiquidityDrops(markets)); f != c {
+			t.Errorf("step %d: compact state found %d drops, full state %d", i, c, f)
+		}
+	}
+
+	// Memory: bytes allocated for the baselines of many markets.
+	const n = 10000
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("e%d:m", i)
+	}
+	allocated := func(cfg Config) uint64 {
+		m := New(mustStorage(t, 100, 50), cfg)
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		for _, id := range ids {
+			m.observeScore(id, 0.5)
+		}
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+	fullBytes := allocated(Config{AdaptiveAlpha: 0.2})
+	compactBytes := allocated(Config{AdaptiveAlpha: 0.2, CompactState: true})
+	t.Logf("score baselines for %d markets: full %d bytes, compact %d bytes", n, fullBytes, compactBytes)
+	if compactBytes >= fullBytes {
+		t.Errorf("compact state allocated %d bytes, want less than full state's %d", compactBytes, fullBytes)
+	}
+}
+
+func TestDetectLiquidityDrops(t *testing.T) {
+	m := New(mustStorage(t, 100, 50), Config{LiquidityDropFraction: 0.5})
+
+	// Two markets of the same event share event-level liquidity.
+	markets := func(liquidity float64) []models.Market {
+		return []models.Market{
+			{ID: "e1:m1", EventID: "e1", MarketID: "m1", Title: "Event", YesProbability: 0.4, Liquidity: liquidity},
+			{ID: "e1:m2", EventID: "e1", MarketID: "m2", Title: "Event", YesProbability: 0.6, Liquidity: liquidity},
+		}
+	}
+
+	steps := []struct {
+		liquidity float64
+		wantDrops int
+	}{
+		{100000, 0}, // warming up
+		{100000, 0},
+		{40000, 0}, // below floor but fewer than liquidityMinSamples observations
+		{100000, 0},
+		{30000, 1}, // first cycle of a drop streak, one alert per event
+		{20000, 0}, // still dropped, already alerted
+		{100000, 0},
+		{10000, 1}, // new streak alerts again
+	}
+	for i, st := range steps {
+		drops := m.DetectLiquidityDrops(markets(st.liquidity))
+		if len(drops) != st.wantDrops {
+			t.Fatalf("step %d (liquidity=%.0f): got %d drops, want %d", i, st.liquidity, len(drops), st.wantDrops)
+		}
+		for _, d := range drops {
+			if d.Kind != models.KindLiquidityDrop {
+				t.Errorf("step %d: Kind = %q, want %q", i, d.Kind, models.KindLiquidityDrop)
+			}
+			if d.NewLiquidity != st.liquidity || d.OldLiquidity <= d.NewLiquidity {
+				t.Errorf("step %d: liquidity %.0f → %.0f, want drop to %.0f", i, d.OldLiquidity, d.NewLiquidity, st.liquidity)
+			}
+			if err := d.Validate(); err != nil {
+				t.Errorf("step %d: invalid change: %v", i, err)
+			}
+		}
+	}
+}
+
+func TestDetectLiquidityDrops_PerMarket(t *testing.T) {
+	m := New(mustStorage(t, 100, 50), Config{LiquidityDropFraction: 0.5, PerMarketLiquidity: true})
+
+	// Order-book depth differs by market: a deep favorite and a thin long shot.
+	deep := models.Market{ID: "e1:m1", EventID: "e1", MarketID: "m1", Title: "Event", YesProbability: 0.7, Liquidity: 100000}
+	thin := models.Market{ID: "e1:m2", EventID: "e1", MarketID: "m2", Title: "Event", YesProbability: 0.3, Liquidity: 10000}
+	for i := range liquidityMinSamples + 1 {
+		if drops := m.DetectLiquidityDrops([]models.Market{deep, thin}); len(drops) != 0 {
+			t.Fatalf("warm-up cycle %d: got %d drops, want 0", i, len(drops))
+		}
+	}
+
+	// The deep market drops out of the fetch (e.g. below the volume floor); the
+	// thin one is unchanged and must not be compared against the deep baseline.
+	if drops := m.DetectLiquidityDrops([]models.Market{thin}); len(drops) != 0 {
+		t.Fatalf("first market dropped out: got %+v, want no drops", drops)
+	}
+
+	thin.Liquidity = 3000
+	drops := m.DetectLiquidityDrops([]models.Market{thin})
+	if len(drops) != 1 || drops[0].EventID != "e1:m2" || drops[0].OldLiquidity < 9000 {
+		t.Fatalf("got %+v, want one drop of e1:m2 against its own ~10000 baseline", drops)
+	}
+}
+
+func TestDetectLiquidityDrops_Disabled(t *testing.T) {
+	m := New(mustStorage(t, 100, 50))
+	for _, liq := range []float64{100000, 100000, 100000, 100000, 0} {
+		if drops := m.DetectLiquidityDrops([]models.Market{{ID: "e:m", EventID: "e", Liquidity: liq}}); drops != nil {
+			t.Fatalf("liquidity drop reported with liquidity_drop_fraction unset")
+		}
+	}
+}
+
+func TestMergeByMarket(t *testing.T) {
+	changes := []models.Change{
+		{ID: "c1", EventID: "e:m1", SignalScore: 0.02},
+		{ID: "c2", EventID: "e:m2", SignalScore: 0.05},
+		{ID: "c3", EventID: "e:m1", Kind: models.KindLiquidityDrop, SignalScore: 0.04},
+	}
+
+	merged := mergeByMarket(changes)
+	if len(merged) != 2 {
+		t.Fatalf("got %d changes, want 2: %+v", len(merged), merged)
+	}
+	m1 := merged[0]
+	if m1.EventID != "e:m1" || m1.ID != "c3" {
+		t.Errorf("expected highest-scoring entry c3 for e:m1 in first position, got %+v", m1)
+	}
+	if m1.Kind != "liquidity_drop,probability" {
+		t.Errorf("Kind = %q, want merged tags", m1.Kind)
+	}
+	if merged[1].ID != "c2" || merged[1].Kind != "" {
+		t.Errorf("unrelated market should be untouched, got %+v", merged[1])
+	}
+}
+
+func TestScoreAndRank_MergesSameMarketAlerts(t *testing.T) {
+	markets := map[string]*models.Market{
+		"btc:100k": {ID: "btc:100k", EventID: "btc", Volume24hr: 500_000, Title: "BTC 100k", Category: "crypto"},
+	}
+	changes := []models.Change{
+		{ID: "c1", EventID: "btc:100k", OriginalEventID: "btc", OldProbability: 0.50, NewProbability: 0.65, Magnitude: 0.15, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+		{ID: "c2", EventID: "btc:100k", OriginalEventID: "btc", OldProbability: 0.50, NewProbability: 0.60, Magnitude: 0.10, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+	}
+
+	mon := New(mustStorage(t, 100, 50), Config{})
+	groups := mon.ScoreAndRank(changes, markets, 0.0, 10, 25000.0, 0.0, 0.0)
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if got := len(groups[0].Markets); got != 1 {
+		t.Fatalf("got %d market entries, want 1", got)
+	}
+	if groups[0].Markets[0].ID != "c1" {
+		t.Errorf("expected the larger move c1 to be kept, got %s", groups[0].Markets[0].ID)
+	}
+}
+
+func TestUncertaintyGain(t *testing.T) {
+	tests := []struct {
+		old, new float64
+		want     float64
+	}{
+		{0.80, 0.55, 0.25},
+		{0.20, 0.45, 0.25},
+		{0.55, 0.80, -0.25},
+		{0.40, 0.60, 0.0}, // crossing 50% symmetrically: no net convergence
+		{0.70, 0.70, 0.0},
+	}
+	for _, tt := range tests {
+		if got := UncertaintyGain(tt.old, tt.new); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("UncertaintyGain(%v, %v) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestDetectUncertainty(t *testing.T) {
+	m := New(mustStorage(t, 100, 50), Config{AlertOnUncertainty: true, UncertaintyThreshold: 0.15})
+	change := func(id string, old, new float64) models.Change {
+		direction := "increase"
+		if new < old {
+			direction = "decrease"
+		}
+		return models.Change{ID: id, EventID: id, OldProbability: old, NewProbability: new,
+			Magnitude: math.Abs(new - old), Direction: direction, DetectedAt: time.Now()}
+	}
+
+	got := m.DetectUncertainty([]models.Change{
+		change("a", 0.80, 0.60), // gain 0.20
+		change("b", 0.90, 0.50), // gain 0.40
+		change("c", 0.50, 0.90), // moving away from 50%
+		change("d", 0.70, 0.60), // gain 0.10, below threshold
+	})
+	if len(got) != 2 || got[0].EventID != "b" || got[1].EventID != "a" {
+		t.Fatalf("expected [b a] ordered by gain, got %+v", got)
+	}
+	for _, c := range got {
+		if c.Kind != models.KindUncertainty {
+			t.Errorf("%s: Kind = %q, want %q", c.EventID, c.Kind, models.KindUncertainty)
+		}
+		if err := c.Validate(); err != nil {
+			t.Errorf("%s: invalid change: %v", c.EventID, err)
+		}
+	}
+
+	// Still converged next cycle: already alerted.
+	if got := m.DetectUncertainty([]models.Change{change("a", 0.80, 0.60)}); len(got) != 0 {
+		t.Errorf("expected no repeat alert, got %+v", got)
+	}
+	// a drops out of the qualifying set, then converges again: re-armed.
+	m.DetectUncertainty(nil)
+	if got := m.DetectUncertainty([]models.Change{change("a", 0.80, 0.60)}); len(got) != 1 {
+		t.Errorf("expected re-armed alert, got %+v", got)
+	}
+}
+
+func TestDetectUncertainty_Disabled(t *testing.T) {
+	m := New(mustStorage(t, 100, 50))
+	changes := []models.Change{{EventID: "a", OldProbability: 0.9, NewProbability: 0.5}}
+	if got := m.DetectUncertainty(changes); got != nil {
+		t.Errorf("expected nil with alert_on_uncertainty unset, got %+v", got)
+	}
+}
+
+// fakeClock is a manually advanced Clock for deterministic timing tests.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestFilterRecentlySent_FakeClock(t *testing.T) {
+	mkGroup := func(dir string, oldP, newP float64) models.Event {
+		return models.Event{ID: "evt-1", Markets: []models.Change{{
+			ID: uuid.New().String(), EventID: "evt-1",
+			OldProbability: oldP, NewProbability: newP, Magnitude: math.Abs(newP - oldP),
+			Direction: dir, TimeWindow: time.Hour,
+		}}}
+	}
+
+	tests := []struct {
+		name    string
+		sent    models.Event
+		next    models.Event
+		advance time.Duration
+		want    int
+	}{
+		{"same direction inside cooldown", mkGroup("increase", 0.50, 0.60), mkGroup("increase", 0.60, 0.70), 59 * time.Minute, 0},
+		{"same direction at cooldown boundary", mkGroup("increase", 0.50, 0.60), mkGroup("increase", 0.60, 0.70), time.Hour, 1},
+		{"same direction after cooldown", mkGroup("increase", 0.50, 0.60), mkGroup("increase", 0.60, 0.70), 61 * time.Minute, 1},
+		{"reversal inside cooldown", mkGroup("increase", 0.50, 0.60), mkGroup("decrease", 0.60, 0.52), time.Minute, 1},
+		{"entering det zone inside cooldown", mkGroup("increase", 0.80, 0.85), mkGroup("increase", 0.85, 0.93), time.Minute, 1},
+		{"already in det zone inside cooldown", mkGroup("increase", 0.85, 0.92), mkGroup("increase", 0.92, 0.96), time.Minute, 0},
+		{"already in det zone after cooldown", mkGroup("increase", 0.85, 0.92), mkGroup("increase", 0.92, 0.96), 2 * time.Hour, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+			mon := New(mustStorage(t, 100, 50), Config{Clock: clock})
+
+			mon.RecordNotified([]models.Event{tt.sent})
+			clock.Advance(tt.advance)
+
+			filtered := mon.FilterRecentlySent([]models.Event{tt.next}, time.Hour)
+			if len(filtered) != tt.want {
+				t.Errorf("got %d groups, want %d", len(filtered), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordNotified_UsesClock(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	mon := New(mustStorage(t, 100, 50), Config{Clock: &fakeClock{now: at}})
+
+	mon.RecordNotified([]models.Event{{ID: "evt-1", Markets: []models.Change{{EventID: "evt-1", Direction: "increase"}}}})
+	if got := mon.notifiedMarkets["evt-1"].SentAt; !got.Equal(at) {
+		t.Errorf("SentAt = %v, want %v", got, at)
+	}
+}
+
+func TestForgetMissing(t *testing.T) {
+	mk := func(eventID, marketID string) models.Market {
+		return models.Market{ID: eventID + ":" + marketID, EventID: eventID, MarketID: marketID}
+	}
+	a1, a2, b1 := mk("A", "1"), mk("A", "2"), mk("B", "1")
+
+	m := New(mustStorage(t, 100, 50), Config{MissingCyclesBeforeCleanup: 2})
+	m.ForgetMissing([]models.Market{a1, a2, b1})
+	for _, id := range []string{a1.ID, a2.ID, b1.ID} {
+		m.notifiedMarkets[id] = notifiedRecord{Direction: "increase"}
+		m.scoreStats[id] = &scoreStat{Count: 1}
+	}
+	m.liquidityStats["A"] = &liquidityStat{Count: 1}
+	m.liquidityStats["B"] = &liquidityStat{Count: 1}
+
+	// Cycle 1: A:2 and B:1 missing once — below the limit, nothing evicted.
+	if n := m.ForgetMissing([]models.Market{a1}); n != 0 {
+		t.Fatalf("evicted %d after 1 missed cycle, want 0", n)
+	}
+	// Cycle 2: B:1 reappears (counter resets); A:2 reaches the limit.
+	if n := m.ForgetMissing([]models.Market{a1, b1}); n != 1 {
+		t.Fatalf("evicted %d after 2 missed cycles, want 1", n)
+	}
+	if _, ok := m.notifiedMarkets[a2.ID]; ok {
+		t.Error("cooldown record for A:2 should be evicted")
+	}
+	if _, ok := m.scoreStats[a2.ID]; ok {
+		t.Error("score stats for A:2 should be evicted")
+	}
+	if _, ok := m.liquidityStats["A"]; !ok {
+		t.Error("liquidity stats for A should survive while A:1 is tracked")
+	}
+	if _, ok := m.notifiedMarkets[b1.ID]; !ok {
+		t.Error("B:1 reappeared and should keep its cooldown record")
+	}
+
+	// Cycles 3–4: everything disappears; all remaining state is evicted.
+	m.ForgetMissing(nil)
+	if n := m.ForgetMissing(nil); n != 2 {
+		t.Fatalf("evicted %d, want 2", n)
+	}
+	if len(m.notifiedMarkets) != 0 || len(m.scoreStats) != 0 || len(m.liquidityStats) != 0 || len(m.tracked) != 0 {
+		t.Errorf("state not fully evicted: notified=%d scores=%d liquidity=%d tracked=%d",
+			len(m.notifiedMarkets), len(m.scoreStats), len(m.liquidityStats), len(m.tracked))
+	}
+}
+
+// TestStateSize_ConcurrentWithCycle reads state while cycles mutate it; run
+// with -race to catch unguarded map access.
+func TestStateSize_ConcurrentWithCycle(t *testing.T) {
+	m := New(mustStorage(t, 100, 50), Config{MissingCyclesBeforeCleanup: 1, AdaptiveThreshold: true, AdaptiveAlpha: 0.1})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 200 {
+			id := fmt.Sprintf("evt-%d:1", i%20)
+			market := models.Market{ID: id, EventID: fmt.Sprintf("evt-%d", i%20), MarketID: "1"}
+			change := models.Change{
+				ID: uuid.New().String(), EventID: id, OriginalEventID: market.EventID,
+				OldProbability: 0.50, NewProbability: 0.60, Magnitude: 0.10,
+				Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now(),
+			}
+			m.ForgetMissing([]models.Market{market})
+			m.ScoreAndRank([]models.Change{change}, map[string]*models.Market{id: &market}, 0.001, 10, 25000, 0, 0)
+			m.RecordNotified(m.FilterRecentlySent([]models.Event{{ID: market.EventID, Markets: []models.Change{change}}}, time.Hour))
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			if _, notified := m.StateSize(); notified != 1 {
+				t.Errorf("notified = %d after the last cycle, want 1", notified)
+			}
+			return
+		default:
+			m.StateSize()
+		}
+	}
+}
+
+func TestFilterRecentlySent_EventCooldown(t *testing.T) {
+	rung := func(marketID, dir string, oldP, newP float64) models.Change {
+		return models.Change{
+			ID: uuid.New().String(), EventID: "ladder:" + marketID, OriginalEventID: "ladder",
+			OldProbability: oldP, NewProbability: newP, Magnitude: math.Abs(newP - oldP),
+			Direction: dir, TimeWindow: time.Hour,
+		}
+	}
+	group := func(changes ...models.Change) []models.Event {
+		return []models.Event{{ID: "ladder", Markets: changes}}
+	}
+
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := mustStorage(t, 100, 50)
+	mon := New(store, Config{Clock: clock, EventCooldownMultiplier: 2})
+	sent := group(rung("100k", "increase", 0.40, 0.50))
+	mon.RecordNotified(sent)
+
+	// Other rungs moving the same way are held back; a reversal is not
+	clock.Advance(90 * time.Minute)
+	filtered := mon.FilterRecentlySent(group(
+		rung("150k", "increase", 0.20, 0.30),
+		rung("200k", "decrease", 0.20, 0.10),
+		rung("250k", "increase", 0.85, 0.92), // entering the deterministic zone
+	), time.Hour)
+	if len(filtered) != 1 || len(filtered[0].Markets) != 2 {
+		t.Fatalf("got %+v, want the reversal and the det-zone entry", filtered)
+	}
+	for _, c := range filtered[0].Markets {
+		if c.EventID == "ladder:150k" {
+			t.Error("same-direction rung passed inside the event cooldown")
+		}
+	}
+
+	// Past multiplier × cooldown the event may fire again
+	clock.Advance(31 * time.Minute)
+	if filtered := mon.FilterRecentlySent(group(rung("150k", "increase", 0.20, 0.30)), time.Hour); len(filtered) != 1 {
+		t.Errorf("got %d groups after the event cooldown, want 1", len(filtered))
+	}
+
+	// A restarted monitor picks the cooldown up from the alert history
+	alert := rung("150k", "increase", 0.20, 0.30)
+	alert.DetectedAt = clock.now
+	if err := store.AddAlerts([]models.Change{alert}); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+	restarted := New(store, Config{Clock: clock, EventCooldownMultiplier: 2})
+	if n, err := restarted.RestoreEventCooldowns(2 * time.Hour); err != nil || n != 1 {
+		t.Fatalf("RestoreEventCooldowns = %d, %v; want 1", n, err)
+	}
+	if filtered := restarted.FilterRecentlySent(group(rung("150k", "increase", 0.20, 0.30)), time.Hour); len(filtered) != 0 {
+		t.Errorf("got %d groups after restart, want the restored cooldown to hold", len(filtered))
+	}
+}
+
+func TestResolvedOutcome(t *testing.T) {
+	tests := []struct {
+		closed  bool
+		yes     float64
+		outcome int
+		ok      bool
+	}{
+		{true, 1, 1, true},
+		{true, 0.998, 1, true},
+		{true, 0, 0, true},
+		{true, 0.004, 0, true},
+		{true, 0.5, 0, false}, // voided 50/50
+		{false, 1, 0, false},  // pinned but not yet closed
+	}
+	for _, tt := range tests {
+		outcome, ok := ResolvedOutcome(models.Market{Closed: tt.closed, YesProbability: tt.yes})
+		if outcome != tt.outcome || ok != tt.ok {
+			t.Errorf("ResolvedOutcome(closed=%v, yes=%v) = %d, %v; want %d, %v", tt.closed, tt.yes, outcome, ok, tt.outcome, tt.ok)
+		}
+	}
+}
+
+func TestResolutions(t *testing.T) {
+	mon := New(mustStorage(t, 100, 50))
+	last := models.Market{ID: "e1:m1", EventID: "e1", Title: "Fed cut?", MarketQuestion: "June", YesProbability: 0.62}
+	final := last
+	final.Closed, final.YesProbability = true, 0.999
+	voidLast := models.Market{ID: "e1:m2", EventID: "e1", Title: "Fed cut?", YesProbability: 0.40}
+	voidFinal := voidLast
+	voidFinal.Closed, voidFinal.YesProbability = true, 0.5
+	mon.observeScore(last.ID, 1)
+
+	groups := mon.Resolutions([]ClosedMarket{{Last: last, Final: final}, {Last: voidLast, Final: voidFinal}})
+	if len(groups) != 1 || groups[0].ID != "e1" || len(groups[0].Markets) != 2 {
+		t.Fatalf("got %+v, want one e1 group with both markets", groups)
+	}
+	for _, c := range groups[0].Markets {
+		if err := c.Validate(); err != nil {
+			t.Errorf("%s: invalid change: %v", c.EventID, err)
+		}
+		if c.Kind != models.KindResolved {
+			t.Errorf("%s: kind = %q, want resolved", c.EventID, c.Kind)
+		}
+	}
+	settled, void := groups[0].Markets[0], groups[0].Markets[1]
+	if settled.Outcome != models.SideYes || settled.OldProbability != 0.62 || settled.NewProbability != 0.999 || settled.Direction != "increase" {
+		t.Errorf("settled change = %+v", settled)
+	}
+	if void.Outcome != "" || void.Direction != "increase" {
+		t.Errorf("void change: outcome %q direction %q, want no outcome", void.Outcome, void.Direction)
+	}
+
+	mon.ForgetResolved(groups)
+	if _, ok := mon.loadScoreStat(last.ID); ok {
+		t.Error("score state kept after ForgetResolved")
+	}
+	if mon.Resolutions(nil) != nil {
+		t.Error("Resolutions(nil) should be nil")
+	}
+}
+
+func TestMaxStates_EvictsLeastRecentlyUpdated(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)}
+	m := New(mustStorage(t, 100, 50), Config{MaxStates: 2, Clock: clock})
+
+	m.observeScore("A:1", 1)
+	clock.Advance(time.Minute)
+	m.observeScore("B:1", 1)
+	clock.Advance(time.Minute)
+	m.observeScore("A:1", 2) // A:1 is now the most recently updated
+	m.notifiedMarkets["B:1"] = notifiedRecord{Direction: "increase"}
+	clock.Advance(time.Minute)
+	m.observeScore("C:1", 1)
+
+	if _, ok := m.scoreStats["B:1"]; ok {
+		t.Error("B:1 was least recently updated and should be evicted")
+	}
+	if _, ok := m.notifiedMarkets["B:1"]; ok {
+		t.Error("B:1's cooldown record should be evicted with its state")
+	}
+	for _, id := range []string{"A:1", "C:1"} {
+		if _, ok := m.scoreStats[id]; !ok {
+			t.Errorf("%s should keep its state", id)
+		}
+	}
+	if st := m.scoreStats["A:1"]; st.Count != 2 {
+		t.Errorf("A:1 score count = %d, want 2", st.Count)
+	}
+	if len(m.stateUpdated) != 2 {
+		t.Errorf("tracking %d states, want 2", len(m.stateUpdated))
+	}
+}
+
+func TestForgetMissing_Disabled(t *testing.T) {
+	m := New(mustStorage(t, 100, 50))
+	m.notifiedMarkets["A:1"] = notifiedRecord{}
+	for range 5 {
+		if n := m.ForgetMissing(nil); n != 0 {
+			t.Fatalf("evicted %d with cleanup disabled", n)
+		}
+	}
+	if len(m.notifiedMarkets) != 1 {
+		t.Error("cooldown record evicted with cleanup disabled")
+	}
+}
+
+func TestSortGroups_Tiebreak(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	group := func(id string, score float64, detectedAgo time.Duration) models.Event {
+		return models.Event{ID: id, BestScore: score, Markets: []models.Change{
+			{EventID: id + ":m", SignalScore: score, DetectedAt: base.Add(-detectedAgo)},
+		}}
+	}
+	markets := map[string]*models.Market{
+		"a:m": {Volume24hr: 900_000},
+		"b:m": {Volume24hr: 500_000},
+		"c:m": {Volume24hr: 100_000},
+		"d:m": {Volume24hr: 1_000},
+	}
+
+	tests := []struct {
+		tiebreak string
+		want     string
+	}{
+		// a, b and c agree to 3 significant digits; d clearly leads on score.
+		{"", "d,b,a,c"},
+		{TiebreakScoreOnly, "d,b,a,c"},
+		{TiebreakRecency, "d,c,a,b"},
+		{TiebreakVolume, "d,a,b,c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tiebreak, func(t *testing.T) {
+			groups := []models.Event{
+				group("a", 0.12340, 2*time.Minute),
+				group("b", 0.12341, 3*time.Minute),
+				group("c", 0.12339, time.Minute),
+				group("d", 0.5, time.Hour),
+			}
+			m := New(mustStorage(t, 100, 50), Config{TopKTiebreak: tt.tiebreak})
+			m.sortGroups(groups, markets)
+
+			ids := make([]string, len(groups))
+			for i, g := range groups {
+				ids[i] = g.ID
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("order = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmoothProbability(t *testing.T) {
+	const alpha = 0.3
+	const minMove = 0.10 // min_abs_change
+
+	// smooth runs raw quotes through the EWMA as the polling loop does.
+	smooth := func(raw []float64, alpha float64) []float64 {
+		out := []float64{raw[0]}
+		for _, q := range raw[1:] {
+			out = append(out, SmoothProbability(out[len(out)-1], q, alpha))
+		}
+		return out
+	}
+	maxMove := func(series []float64) float64 {
+		var m float64
+		for _, p := range series {
+			m = math.Max(m, math.Abs(p-series[0]))
+		}
+		return m
+	}
+
+	spike := []float64{0.50, 0.50, 0.70, 0.50, 0.50, 0.50}
+	if got := maxMove(smooth(spike, 1.0)); math.Abs(got-0.20) > 1e-9 {
+		t.Errorf("alpha=1 should pass raw quotes through: max move %.3f, want 0.200", got)
+	}
+	if got := maxMove(smooth(spike, alpha)); got >= minMove {
+		t.Errorf("one-poll spike not attenuated: max move %.3f ≥ %.2f", got, minMove)
+	}
+
+	sustained := []float64{0.50, 0.70, 0.70, 0.70, 0.70, 0.70}
+	if got := maxMove(smooth(sustained, alpha)); got < minMove {
+		t.Errorf("sustained move suppressed: max move %.3f < %.2f", got, minMove)
+	}
+}
+
+func TestScoreAndRank_RecordsScoreComponents(t *testing.T) {
+	store := mustStorage(t, 100, 50)
+	mon := New(store)
+
+	markets := map[string]*models.Market{
+		"e1": {ID: "e1", EventID: "e1", Volume24hr: 100_000, Liquidity: 50_000, Title: "Test", Category: "test"},
+	}
+	changes := []models.Change{
+		{ID: "c1", EventID: "e1", OldProbability: 0.50, NewProbability: 0.65, Magnitude: 0.15, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+	}
+
+	top := mon.ScoreAndRank(changes, markets, 0.001, 5, 25000.0, 0.0, 0.0)
+	if len(top) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(top))
+	}
+	c := top[0].Markets[0]
+	comp := c.Components
+	if comp == nil {
+		t.Fatal("scored change has no components")
+	}
+	if got := CompositeScore(comp.KL, comp.VolumeWeight, comp.SNR, comp.TC); math.Abs(got-c.SignalScore) > 1e-12 {
+		t.Errorf("components reproduce score %v, want %v", got, c.SignalScore)
+	}
+	if comp.Volume24hr != 100_000 || comp.VolumeRef != 25000 || comp.Liquidity != 50_000 || comp.Threshold != 0.001 {
+		t.Errorf("unexpected context in components: %+v", comp)
+	}
+}
+
+func TestObserveResolution(t *testing.T) {
+	mon := New(mustStorage(t, 100, 50), Config{ResolutionPendingCycles: 2})
+	pinned := models.Market{ID: "e1:m1", EventID: "e1", YesProbability: 0.999, NoProbability: 0.001}
+	live := models.Market{ID: "e2:m1", EventID: "e2", YesProbability: 0.60, NoProbability: 0.40}
+	markets := []models.Market{pinned, live}
+
+	// Two polls at the extreme are tolerated; the third marks it resolving, once.
+	for poll := 1; poll <= 4; poll++ {
+		resolving := mon.ObserveResolution(markets)
+		wantNote := poll == 3
+		if gotNote := len(resolving) == 1 && resolving[0].ID == pinned.ID; gotNote != wantNote || len(resolving) > 1 {
+			t.Errorf("poll %d: resolving = %v, want note %v", poll, resolving, wantNote)
+		}
+		kept := mon.ExcludeResolving(markets)
+		wantKept := 2
+		if poll >= 3 {
+			wantKept = 1
+		}
+		if len(kept) != wantKept || kept[len(kept)-1].ID != live.ID {
+			t.Errorf("poll %d: kept %d markets, want %d (live market always kept)", poll, len(kept), wantKept)
+		}
+	}
+
+	// Leaving the extreme re-enables the market and resets its streak.
+	markets[0].YesProbability, markets[0].NoProbability = 0.90, 0.10
+	mon.ObserveResolution(markets)
+	if kept := mon.ExcludeResolving(markets); len(kept) != 2 {
+		t.Errorf("market back off the extreme should be detected again, kept %d", len(kept))
+	}
+
+	// Disabled: nothing is tracked or excluded.
+	off := New(mustStorage(t, 100, 50))
+	for range 5 {
+		if r := off.ObserveResolution([]models.Market{pinned}); r != nil {
+			t.Fatalf("disabled check reported %v", r)
+		}
+	}
+	if kept := off.ExcludeResolving([]models.Market{pinned}); len(kept) != 1 {
+		t.Errorf("disabled check excluded markets")
+	}
+}
+
+func TestDetectLadderInconsistencies(t *testing.T) {
+	rung := func(id, question string, p float64) models.Market {
+		return models.Market{ID: "e1:" + id, EventID: "e1", MarketID: id, Title: "Bitcoin price",
+			MarketQuestion: question, YesProbability: p, NoProbability: 1 - p}
+	}
+
+	tests := []struct {
+		name    string
+		markets []models.Market
+		want    []string // violating market IDs, largest gap first
+	}{
+		{
+			name: "above ladder in order",
+			markets: []models.Market{
+				rung("m1", "Will Bitcoin be above $100k on June 30, 2026?", 0.60),
+				rung("m2", "Will Bitcoin be above $150k on June 30, 2026?", 0.30),
+				rung("m3", "Will Bitcoin be above $200,000 on June 30, 2026?", 0.10),
+			},
+		},
+		{
+			name: "above ladder with a mispriced rung",
+			markets: []models.Market{
+				rung("m3", "Will Bitcoin be above $200,000 on June 30, 2026?", 0.10),
+				rung("m1", "Will Bitcoin be above $100k on June 30, 2026?", 0.30),
+				rung("m2", "Will Bitcoin be above $150k on June 30, 2026?", 0.40),
+			},
+			want: []string{"e1:m2"},
+		},
+		{
+			name: "gap within tolerance",
+			markets: []models.Market{
+				rung("m1", "Will Bitcoin be above $100k on June 30, 2026?", 0.30),
+				rung("m2", "Will Bitcoin be above $150k on June 30, 2026?", 0.31),
+			},
+		},
+		{
+			name: "below ladder runs the other way",
+			markets: []models.Market{
+				rung("m1", "Will Bitcoin dip below $50k in 2026?", 0.20),
+				rung("m2", "Will Bitcoin dip below $70k in 2026?", 0.10),
+			},
+			want: []string{"e1:m1"},
+		},
+		{
+			name: "not a ladder",
+			markets: []models.Market{
+				rung("m1", "Will Trump win?", 0.40),
+				rung("m2", "Will Harris win?", 0.60),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mon := New(mustStorage(t, 100, 50), Config{DetectLadderInconsistency: true, LadderTolerance: 0.02})
+			got := mon.DetectLadderInconsistencies(tt.markets)
+			var ids []string
+			for _, c := range got {
+				if err := c.Validate(); err != nil {
+					t.Errorf("invalid change %s: %v", c.EventID, err)
+				}
+				ids = append(ids, c.EventID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("violations = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLadderInconsistencies_ReportsOnce(t *testing.T) {
+	mon := New(mustStorage(t, 100, 50), Config{DetectLadderInconsistency: true})
+	markets := []models.Market{
+		{ID: "e1:m1", EventID: "e1", MarketQuestion: "ETH above $3k?", YesProbability: 0.30, NoProbability: 0.70},
+		{ID: "e1:m2", EventID: "e1", MarketQuestion: "ETH above $4k?", YesProbability: 0.45, NoProbability: 0.55},
+	}
+
+	first := mon.DetectLadderInconsistencies(markets)
+	if len(first) != 1 || first[0].RelatedQuestion != "ETH above $3k?" || math.Abs(first[0].Magnitude-0.15) > 1e-9 {
+		t.Fatalf("first cycle = %+v, want one 15pp violation against the $3k rung", first)
+	}
+	if again := mon.DetectLadderInconsistencies(markets); len(again) != 0 {
+		t.Errorf("persisting violation re-reported: %+v", again)
+	}
+
+	// Consistent for a cycle re-arms the rung.
+	markets[1].YesProbability, markets[1].NoProbability = 0.20, 0.80
+	mon.DetectLadderInconsistencies(markets)
+	markets[1].YesProbability, markets[1].NoProbability = 0.45, 0.55
+	if rearmed := mon.DetectLadderInconsistencies(markets); len(rearmed) != 1 {
+		t.Errorf("re-armed violation not reported: %+v", rearmed)
+	}
+
+	if off := New(mustStorage(t, 100, 50)).DetectLadderInconsistencies(markets); off != nil {
+		t.Errorf("disabled check returned %+v", off)
+	}
+}
+
+func TestScoreAndRank_SetsGroupCategory(t *testing.T) {
+	mon := New(mustStorage(t, 100, 50))
+	markets := map[string]*models.Market{
+		"e1:m1": {ID: "e1:m1", EventID: "e1", Volume24hr: 100_000, Title: "Test", Category: "crypto"},
+	}
+	changes := []models.Change{
+		{ID: "c1", EventID: "e1:m1", OriginalEventID: "e1", OldProbability: 0.50, NewProbability: 0.65, Magnitude: 0.15, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+	}
+	top := mon.ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.0)
+	if len(top) != 1 || top[0].Category != "crypto" {
+		t.Errorf("groups = %+v, want one crypto group", top)
+	}
+}
+
+func TestDetectChanges_SkipsClosedMarkets(t *testing.T) {
+	s := mustStorage(t, 100, 50)
+	m := New(s)
+
+	now := time.Now()
+	market := models.Market{
+		ID:             "event-1:market-1",
+		EventID:        "event-1",
+		MarketID:       "market-1",
+		Title:          "Resolved?",
+		Category:       "politics",
+		YesProbability: 1.0,
+		NoProbability:  0.0,
+		Closed:         true,
+		LastUpdated:    now,
+		CreatedAt:      now.Add(-1 * time.Hour),
+	}
+	if err := s.AddMarket(&market); err != nil {
+		t.Fatalf("Failed to add market: %v", err)
+	}
+	for i, p := range []float64{0.5, 1.0} {
+		snap := models.Snapshot{
+			ID:             uuid.New().String(),
+			EventID:        market.ID,
+			YesProbability: p,
+			NoProbability:  1 - p,
+			Timestamp:      now.Add(time.Duration(i-1) * time.Hour),
+			Source:         "test",
+		}
+		if err := s.AddSnapshot(&snap); err != nil {
+			t.Fatalf("Failed to add snapshot: %v", err)
+		}
+	}
+
+	changes, _, err := m.DetectChanges([]models.Market{market}, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("DetectChanges failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Expected closed market to be skipped, got %d changes", len(changes))
+	}
+
+	market.Closed = false
+	changes, _, err = m.DetectChanges([]models.Market{market}, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("DetectChanges failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Errorf("Expected the same market to alert once open, got %d changes", len(changes))
+	}
+}
+
+func TestFilterAlertBudget(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 7, 0, 0, 0, time.UTC)}
+	mon := New(mustStorage(t, 100, 50), Config{Clock: clock, MaxAlertsPerMarketPerDay: 2, AlertBudgetResetHour: 8})
+	group := models.Event{ID: "evt-1", Markets: []models.Change{
+		{EventID: "evt-1:m1", Direction: "increase", SignalScore: 0.5},
+		{EventID: "evt-1:m2", Direction: "increase", SignalScore: 0.3},
+	}}
+	noisy := models.Event{ID: "evt-1", Markets: group.Markets[:1]}
+
+	mon.RecordNotified([]models.Event{noisy})
+	mon.RecordNotified([]models.Event{noisy})
+
+	filtered := mon.FilterAlertBudget([]models.Event{group})
+	if len(filtered) != 1 || len(filtered[0].Markets) != 1 || filtered[0].Markets[0].EventID != "evt-1:m2" {
+		t.Fatalf("expected only evt-1:m2 to survive, got %+v", filtered)
+	}
+	if filtered[0].BestScore != 0.3 {
+		t.Errorf("BestScore = %v, want 0.3 (recomputed from survivors)", filtered[0].BestScore)
+	}
+
+	// 08:00 UTC starts a new window
+	clock.Advance(time.Hour)
+	if filtered := mon.FilterAlertBudget([]models.Event{group}); len(filtered) != 1 || len(filtered[0].Markets) != 2 {
+		t.Errorf("expected the budget to reset at the window boundary, got %+v", filtered)
+	}
+}
+
+func TestFilterAlertBudget_Disabled(t *testing.T) {
+	mon := New(mustStorage(t, 100, 50))
+	groups := []models.Event{{ID: "evt-1", Markets: []models.Change{{EventID: "evt-1:m1"}}}}
+	for range 5 {
+		mon.RecordNotified(groups)
+	}
+	if got := mon.FilterAlertBudget(groups); len(got) != 1 {
+		t.Errorf("expected no filtering with the budget disabled, got %d groups", len(got))
+	}
+}
+
+func TestScoreAndRank_GroupMinBestScore(t *testing.T) {
+	markets := map[string]*models.Market{
+		"e1": {ID: "e1", EventID: "e1", Volume24hr: 100_000, Title: "Strong", Category: "test"},
+		"e2": {ID: "e2", EventID: "e2", Volume24hr: 100_000, Title: "Weak", Category: "test"},
+	}
+	changes := []models.Change{
+		{ID: "c1", EventID: "e1", OriginalEventID: "e1", OldProbability: 0.50, NewProbability: 0.80, Magnitude: 0.30, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+		{ID: "c2", EventID: "e2", OriginalEventID: "e2", OldProbability: 0.50, NewProbability: 0.53, Magnitude: 0.03, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+	}
+
+	all := New(mustStorage(t, 100, 50)).ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.0)
+	if len(all) != 2 {
+		t.Fatalf("Expected both groups without a group gate, got %d", len(all))
+	}
+	// A bar between the two groups' best scores keeps only the strong event
+	bar := (all[0].BestScore + all[1].BestScore) / 2
+
+	gated := New(mustStorage(t, 100, 50), Config{GroupMinBestScore: bar}).ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.0)
+	if len(gated) != 1 || gated[0].ID != "e1" {
+		t.Errorf("Expected only the strong group above best-score bar %.4f, got %+v", bar, gated)
+	}
+}
+
+func TestScoreAndRank_ScoreCeiling(t *testing.T) {
+	markets := map[string]*models.Market{
+		"e1": {ID: "e1", EventID: "e1", Volume24hr: 100_000, Title: "Test", Category: "test"},
+	}
+	changes := []models.Change{
+		{ID: "c1", EventID: "e1", OriginalEventID: "e1", OldProbability: 0.20, NewProbability: 0.80, Magnitude: 0.60, Direction: "increase", TimeWindow: time.Hour, DetectedAt: time.Now()},
+	}
+
+	t.Run("alerting extreme is tagged and kept out of the baseline", func(t *testing.T) {
+		mon := New(mustStorage(t, 100, 50), Config{AdaptiveThreshold: true, ScoreCeiling: 0.01, AlertAboveCeiling: true})
+		top := mon.ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.0)
+		if len(top) != 1 {
+			t.Fatalf("Expected the extreme change to alert, got %d groups", len(top))
+		}
+		if kinds := top[0].Markets[0].Kinds(); !slices.Equal(kinds, []string{models.KindProbability, models.KindExtreme}) {
+			t.Errorf("Kinds = %v, want [probability extreme]", kinds)
+		}
+		if _, ok := mon.scoreStats["e1"]; ok {
+			t.Error("Expected an above-ceiling score to stay out of the adaptive baseline")
+		}
+	})
+
+	t.Run("non-alerting extreme is dropped", func(t *testing.T) {
+		mon := New(mustStorage(t, 100, 50), Config{ScoreCeiling: 0.01})
+		if top := mon.ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.0); len(top) != 0 {
+			t.Errorf("Expected no alerts with alert_above_ceiling off, got %d groups", len(top))
+		}
+	})
+
+	t.Run("below the ceiling scores normally", func(t *testing.T) {
+		mon := New(mustStorage(t, 100, 50), Config{AdaptiveThreshold: true, ScoreCeiling: 1e6, AlertAboveCeiling: true})
+		top := mon.ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.0)
+		if len(top) != 1 || top[0].Markets[0].Kind != "" {
+			t.Fatalf("Expected one untagged alert, got %+v", top)
+		}
+		if _, ok := mon.scoreStats["e1"]; !ok {
+			t.Error("Expected the score to feed the adaptive baseline")
+		}
+	})
+}
+
+func TestTrackSide(t *testing.T) {
+	markets := map[string]*models.Market{
+		"e1:m1": {ID: "e1:m1", EventID: "e1", MarketID: "m1", Volume24hr: 100_000, Title: "Ceasefire", Category: "world"},
+	}
+	// Yes 0.90 → 0.70 is No 0.10 → 0.30
+	changes := []models.Change{
+		{ID: "c1", EventID: "e1:m1", OriginalEventID: "e1", OldProbability: 0.90, NewProbability: 0.70, Magnitude: 0.20, Direction: "decrease", TimeWindow: time.Hour, DetectedAt: time.Now(), Side: models.SideNo},
+	}
+
+	mon := New(mustStorage(t, 100, 50), Config{TrackSide: map[string]string{"world": models.SideNo, "m2": models.SideYes}})
+	if side := mon.sideFor(*markets["e1:m1"]); side != models.SideNo {
+		t.Errorf("sideFor by category = %q, want no", side)
+	}
+	if side := mon.sideFor(models.Market{MarketID: "m2", Category: "World"}); side != "" {
+		t.Errorf("sideFor with a market ID override = %q, want Yes (empty)", side)
+	}
+	if top := mon.ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.5); len(top) != 1 {
+		t.Errorf("Yes-side prefilter: got %d groups, want 1 (old Yes 0.90 ≥ min_base_prob)", len(top))
+	}
+
+	scored := New(mustStorage(t, 100, 50), Config{ScoreTrackedSide: true})
+	if top := scored.ScoreAndRank(changes, markets, 0.0, 5, 25000.0, 0.0, 0.5); len(top) != 0 {
+		t.Errorf("No-side prefilter: got %d groups, want 0 (old No 0.10 < min_base_prob)", len(top))
+	}
+}
+
+func TestFinalScore_Weights(t *testing.T) {
+	change := models.Change{
+		EventID:    "e:m",
+		Components: &models.ScoreComponents{KL: 0.1, VolumeWeight: 2, SNR: 3, TC: 0.5},
+	}
+
+	ones := New(mustStorage(t, 100, 50), Config{Weights: &ScoreWeights{KL: 1, Volume: 1, SNR: 1, TC: 1}})
+	if got, want := ones.finalScore(change), CompositeScore(0.1, 2, 3, 0.5); got != want {
+		t.Errorf("unit weights: finalScore = %v, want exactly %v", got, want)
+	}
+
+	// Volume squared, SNR dropped: 0.1 × 2² × 1 × 0.5
+	weighted := New(mustStorage(t, 100, 50), Config{Weights: &ScoreWeights{KL: 1, Volume: 2, SNR: 0, TC: 1}})
+	if got, want := weighted.finalScore(change), 0.2; math.Abs(got-want) > 1e-12 {
+		t.Errorf("weighted finalScore = %v, want %v", got, want)
+	}
+}
@@ -10,11 +10,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/rewired-gh/polyoracle/internal/logger"
+	"github.com/rewired-gh/polyoracle/internal/metrics"
 	"github.com/rewired-gh/polyoracle/internal/models"
 )
 
@@ -26,6 +32,27 @@ type Client struct {
 	timeout        time.Duration
 	maxRetries     int
 	retryDelayBase time.Duration
+
+	schemaDriftFraction float64
+	retryOnEmpty        bool
+	eventURLTemplate    string
+	pageSize            int
+	perCategoryFetch    bool
+	includeClosed       bool
+	defaultCategory     string
+	singleMarketVolume  bool
+	residualMode        string
+	categoryVolumeMin   map[string]VolumeThresholds
+	positionalFallback  bool
+	depthBand           float64
+	fetchConcurrency    int
+	multiOutcome        bool
+	rngMu               sync.Mutex // rng is not safe for concurrent use; pages retry in parallel
+	rng                 *rand.Rand
+	lastFetchCount      int // markets returned by the previous FetchEvents call
+	// schemaDrift holds the suspicious-event fraction from the most recent fetch
+	// when it crossed schemaDriftFraction, or 0 when the response looked healthy.
+	schemaDrift float64
 }
 
 // PolymarketEvent represents an event from Polymarket Gamma API
@@ -67,6 +94,7 @@ type PolymarketMarket struct {
 	Volume        string  `json:"volume"`        // Total volume (string in API)
 	Volume1wk     float64 `json:"volume1wk"`     // 1-week volume (number in API)
 	Volume1mo     float64 `json:"volume1mo"`     // 1-month volume (number in API)
+	Closed        bool    `json:"closed"`
 }
 
 // ClientConfig holds optional configuration for the Polymarket client
@@ -76,8 +104,99 @@ type ClientConfig struct {
 	MaxIdleConns        int
 	MaxIdleConnsPerHost int
 	IdleConnTimeout     time.Duration
+	// SchemaDriftFraction is the fraction of events per page that may decode with
+	// all-zero volume and no tags before the response is flagged as schema drift.
+	// 0 disables the check.
+	SchemaDriftFraction float64
+	// RetryOnEmpty retries an empty first page once when the previous fetch
+	// returned markets, so a flaky response isn't mistaken for an empty universe.
+	RetryOnEmpty bool
+	// EventURLTemplate builds each market's link; {slug} is replaced with the
+	// event slug and {marketID} with the Polymarket market ID.
+	EventURLTemplate string
+	// PageSize is the number of events requested per page (1–MaxPageSize).
+	PageSize int
+	// PerCategoryFetch queries each category separately (filtered by tag) so
+	// low-volume categories are not crowded out by the global volume ordering.
+	// Costs at least one request per category per cycle.
+	PerCategoryFetch bool
+	// IncludeClosed also requests closed events, with their final prices, for
+	// retrospective analysis. Closed markets are returned with Closed set.
+	IncludeClosed bool
+	// DefaultCategory is assigned to markets whose event has no usable tag, so
+	// they are still tracked. Empty uses DefaultCategory.
+	DefaultCategory string
+	// SingleMarketVolume gives the only market of a single-market event the
+	// event's 24h/1wk/1mo volumes as-is instead of estimating its share.
+	SingleMarketVolume bool
+	// ResidualMode handles binary markets that list extra outcomes beyond Yes
+	// and No (e.g. a small "Other"): ResidualDistribute (default when empty)
+	// rescales Yes and No to sum to 1; ResidualIgnore keeps the raw prices.
+	ResidualMode string
+	// CategoryVolumeMin replaces FetchEvents' volume floors for events whose
+	// primary category has an entry.
+	CategoryVolumeMin map[string]VolumeThresholds
+	// PositionalFallback reads a market whose outcomes are not labeled Yes/No
+	// but which has exactly two prices as Yes then No, instead of skipping it.
+	PositionalFallback bool
+	// FetchConcurrency is the number of event pages requested at once
+	// (default DefaultFetchConcurrency). Pages are still processed in order.
+	FetchConcurrency int
+	// MultiOutcome tracks each outcome of a categorical market (more than two
+	// outcomes, none labeled Yes/No) as its own market, with composite ID
+	// eventID:marketID:outcomeIndex and the outcome label as its question.
+	MultiOutcome bool
+	// DepthBand, when positive, replaces each fetched market's Liquidity with
+	// the dollar depth of its CLOB order book within ±DepthBand of the
+	// midpoint. Costs one CLOB request per market per fetch.
+	DepthBand float64
+	// Rand draws the retry backoff jitter, so a fixed seed reproduces a run.
+	// nil = seeded from the current time.
+	Rand *rand.Rand
+}
+
+// VolumeThresholds are minimum event volumes; 0 disables a window's floor.
+type VolumeThresholds struct {
+	Volume24hrMin float64
+	Volume1wkMin  float64
+	Volume1moMin  float64
 }
 
+// passes reports whether the event clears the thresholds, requiring any
+// (or) or all of the enabled windows.
+func (th VolumeThresholds) passes(pe PolymarketEvent, or bool) bool {
+	if th.Volume24hrMin <= 0 && th.Volume1wkMin <= 0 && th.Volume1moMin <= 0 {
+		return true
+	}
+	vol24hrPass := pe.Volume24hr >= th.Volume24hrMin
+	vol1wkPass := pe.Volume1wk >= th.Volume1wkMin
+	vol1moPass := pe.Volume1mo >= th.Volume1moMin
+	if or {
+		// Logical OR: include if ANY condition passes
+		return vol24hrPass || vol1wkPass || vol1moPass
+	}
+	// Logical AND: include if ALL conditions pass
+	return vol24hrPass && vol1wkPass && vol1moPass
+}
+
+// Residual outcome modes accepted by ClientConfig.ResidualMode.
+const (
+	ResidualDistribute = "distribute"
+	ResidualIgnore     = "ignore"
+)
+
+// DefaultCategory is the category of markets whose event carries no tags.
+const DefaultCategory = "other"
+
+// DefaultEventURLTemplate links to the public Polymarket event page.
+const DefaultEventURLTemplate = "https://polymarket.com/event/{slug}"
+
+// MaxPageSize is the Gamma API's maximum events per request, and the default page size.
+const MaxPageSize = 500
+
+// DefaultFetchConcurrency is the number of event pages requested at once.
+const DefaultFetchConcurrency = 4
+
 // NewClient creates a new Polymarket client
 func NewClient(gammaAPIURL, clobAPIURL string, timeout time.Duration, cfg ...ClientConfig) *Client {
 	var maxRetries = 3
@@ -85,6 +204,21 @@ func NewClient(gammaAPIURL, clobAPIURL string, timeout time.Duration, cfg ...Cli
 	var maxIdleConns = 100
 	var maxIdleConnsPerHost = 10
 	var idleConnTimeout = 90 * time.Second
+	var schemaDriftFraction float64
+	var retryOnEmpty bool
+	var eventURLTemplate = DefaultEventURLTemplate
+	var pageSize = MaxPageSize
+	var perCategoryFetch bool
+	var includeClosed bool
+	var defaultCategory = DefaultCategory
+	var singleMarketVolume bool
+	var residualMode = ResidualDistribute
+	var categoryVolumeMin map[string]VolumeThresholds
+	var positionalFallback bool
+	var depthBand float64
+	var fetchConcurrency = DefaultFetchConcurrency
+	var multiOutcome bool
+	var rng *rand.Rand
 
 	if len(cfg) > 0 {
 		if cfg[0].MaxRetries > 0 {
@@ -102,6 +236,37 @@ func NewClient(gammaAPIURL, clobAPIURL string, timeout time.Duration, cfg ...Cli
 		if cfg[0].IdleConnTimeout > 0 {
 			idleConnTimeout = cfg[0].IdleConnTimeout
 		}
+		if cfg[0].SchemaDriftFraction > 0 {
+			schemaDriftFraction = cfg[0].SchemaDriftFraction
+		}
+		retryOnEmpty = cfg[0].RetryOnEmpty
+		if cfg[0].EventURLTemplate != "" {
+			eventURLTemplate = cfg[0].EventURLTemplate
+		}
+		if cfg[0].PageSize > 0 && cfg[0].PageSize <= MaxPageSize {
+			pageSize = cfg[0].PageSize
+		}
+		perCategoryFetch = cfg[0].PerCategoryFetch
+		includeClosed = cfg[0].IncludeClosed
+		if cfg[0].DefaultCategory != "" {
+			defaultCategory = cfg[0].DefaultCategory
+		}
+		singleMarketVolume = cfg[0].SingleMarketVolume
+		if cfg[0].ResidualMode != "" {
+			residualMode = cfg[0].ResidualMode
+		}
+		categoryVolumeMin = cfg[0].CategoryVolumeMin
+		positionalFallback = cfg[0].PositionalFallback
+		depthBand = cfg[0].DepthBand
+		multiOutcome = cfg[0].MultiOutcome
+		rng = cfg[0].Rand
+		if cfg[0].FetchConcurrency > 0 {
+			fetchConcurrency = cfg[0].FetchConcurrency
+		}
+	}
+
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
 	}
 
 	return &Client{
@@ -116,75 +281,136 @@ func NewClient(gammaAPIURL, clobAPIURL string, timeout time.Duration, cfg ...Cli
 				TLSHandshakeTimeout: 10 * time.Second,
 			},
 		},
-		timeout:        timeout,
-		maxRetries:     maxRetries,
-		retryDelayBase: retryDelayBase,
+		timeout:             timeout,
+		maxRetries:          maxRetries,
+		retryDelayBase:      retryDelayBase,
+		schemaDriftFraction: schemaDriftFraction,
+		retryOnEmpty:        retryOnEmpty,
+		eventURLTemplate:    eventURLTemplate,
+		pageSize:            pageSize,
+		perCategoryFetch:    perCategoryFetch,
+		includeClosed:       includeClosed,
+		defaultCategory:     defaultCategory,
+		singleMarketVolume:  singleMarketVolume,
+		residualMode:        residualMode,
+		categoryVolumeMin:   categoryVolumeMin,
+		positionalFallback:  positionalFallback,
+		depthBand:           depthBand,
+		fetchConcurrency:    fetchConcurrency,
+		multiOutcome:        multiOutcome,
+		rng:                 rng,
 	}
 }
 
+// SchemaDrift reports the fraction of suspicious events seen by the most recent
+// FetchEvents call when it exceeded the configured drift fraction, and whether
+// drift was detected at all.
+func (c *Client) SchemaDrift() (float64, bool) {
+	return c.schemaDrift, c.schemaDrift > 0
+}
+
 // FetchEvents retrieves events from Polymarket Gamma API with filtering
 // Filter order: 1) categories, 2) top K by volume (logical OR), 3) then detect changes
-// Uses pagination to fetch events beyond the API's 500 per-request limit.
+// Uses pagination (pageSize events per request) to fetch events beyond one page.
+//
+// With per-category fetching enabled, each category is queried separately and
+// contributes up to ceil(limit/len(categories)) markets, so the result may hold
+// slightly more than limit markets.
 func (c *Client) FetchEvents(ctx context.Context, categories []string, vol24hrMin, vol1wkMin, vol1moMin float64, volumeFilterOR bool, limit int) ([]models.Market, error) {
 	// Filter by categories
 	categoryMap := make(map[string]bool)
 	for _, cat := range categories {
 		categoryMap[cat] = true
 	}
+	c.schemaDrift = 0
 
 	var allEvents []models.Market
-	const pageSize = 500 // API max per request
-	maxFetch := limit * 3
-
-	// Paginate through results
-	for offset := 0; offset < maxFetch; offset += pageSize {
-		// Build URL with query parameters
-		u, err := url.Parse(c.gammaAPIURL + "/events")
+	if c.perCategoryFetch && len(categories) > 0 {
+		perCategory := (limit + len(categories) - 1) / len(categories)
+		seen := make(map[string]bool)
+		for _, cat := range categories {
+			markets, err := c.fetchMarkets(ctx, cat, categoryMap, vol24hrMin, vol1wkMin, vol1moMin, volumeFilterOR, perCategory)
+			if err != nil {
+				return nil, fmt.Errorf("category %q: %w", cat, err)
+			}
+			for _, m := range markets {
+				// Events tagged with several requested categories are returned by each query
+				if seen[m.ID] {
+					continue
+				}
+				seen[m.ID] = true
+				allEvents = append(allEvents, m)
+			}
+		}
+	} else {
+		var err error
+		allEvents, err = c.fetchMarkets(ctx, "", categoryMap, vol24hrMin, vol1wkMin, vol1moMin, volumeFilterOR, limit)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse URL: %w", err)
+			return nil, err
 		}
+	}
 
-		q := u.Query()
-		q.Set("active", "true")
-		q.Set("closed", "false")
-		q.Set("limit", fmt.Sprintf("%d", pageSize))
-		q.Set("offset", fmt.Sprintf("%d", offset))
-
-		// Sort by volume24hr descending (one of the volume metrics)
-		q.Set("order", "volume24hr")
-		q.Set("ascending", "false")
-
-		u.RawQuery = q.Encode()
+	c.lastFetchCount = len(allEvents)
+	return allEvents, nil
+}
 
-		resp, err := c.doRequest(ctx, u.String())
+// fetchMarkets paginates through active events, optionally restricted to one
+// tag slug, and returns up to limit markets that pass the category and volume
+// filters.
+func (c *Client) fetchMarkets(ctx context.Context, tagSlug string, categoryMap map[string]bool, vol24hrMin, vol1wkMin, vol1moMin float64, volumeFilterOR bool, limit int) ([]models.Market, error) {
+	var allEvents []models.Market
+	pageSize := c.pageSize
+	// Always allow at least one full page, whatever the configured page size
+	maxFetch := max(limit*3, pageSize)
+	seen := make(map[string]int) // composite ID → index in allEvents
+	// composite ID → CLOB token, for order-book depth
+	tokens := make(map[string]string)
+	renormalizedMarkets := 0
+
+	// Paginate through results; pages are prefetched concurrently but
+	// processed in offset order
+	pages := &pageFetcher{c: c, ctx: ctx, pageSize: pageSize, maxFetch: maxFetch, tagSlug: tagSlug}
+	for offset := 0; offset < maxFetch; offset += pageSize {
+		pmEvents, err := pages.get(offset)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch events from %s: %w", u.String(), err)
-		}
-
-		// Validate content type
-		contentType := resp.Header.Get("Content-Type")
-		if contentType != "" && contentType != "application/json" && !containsJSON(contentType) {
-			_ = resp.Body.Close()
-			return nil, fmt.Errorf("unexpected content type: %s (expected application/json)", contentType)
+			return nil, err
 		}
 
-		// Response is array directly, not wrapped
-		var pmEvents []PolymarketEvent
-		if err := json.NewDecoder(resp.Body).Decode(&pmEvents); err != nil {
-			_ = resp.Body.Close()
-			return nil, fmt.Errorf("failed to decode events JSON: %w", err)
+		// A transiently empty first page would otherwise end the fetch and make the
+		// whole cycle look like the market universe vanished. Retry it once. A
+		// single category can legitimately be empty, so tag queries don't retry.
+		if len(pmEvents) == 0 && offset == 0 && tagSlug == "" && c.retryOnEmpty && c.lastFetchCount > 0 {
+			logger.Warn("Polymarket returned an empty first page (previous fetch had %d markets); retrying once", c.lastFetchCount)
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("request cancelled during empty-page retry: %w", ctx.Err())
+			case <-time.After(c.retryDelayBase):
+			}
+			pmEvents, err = c.fetchPage(ctx, offset, pageSize, tagSlug)
+			if err != nil {
+				return nil, err
+			}
 		}
-		_ = resp.Body.Close()
 
 		// No more events
 		if len(pmEvents) == 0 {
 			break
 		}
 
+		// Renamed JSON fields decode silently as zero values, so a page dominated
+		// by volume-less, tag-less events is the only visible symptom.
+		if c.schemaDriftFraction > 0 {
+			if frac := suspiciousEventFraction(pmEvents); frac >= c.schemaDriftFraction && frac > c.schemaDrift {
+				logger.Warn("Possible Polymarket API schema drift: %.0f%% of %d events at offset %d have zero volume and no tags",
+					frac*100, len(pmEvents), offset)
+				c.schemaDrift = frac
+			}
+		}
+
 		// Process events from this page
 		for _, pe := range pmEvents {
 			// Filter by category using tags (category field is often null in API)
-			if len(categories) > 0 {
+			if len(categoryMap) > 0 {
 				// Check if any tag matches the requested categories
 				tagMatch := false
 				for _, tag := range pe.Tags {
@@ -198,25 +424,6 @@ func (c *Client) FetchEvents(ctx context.Context, categories []string, vol24hrMi
 				}
 			}
 
-			// Apply volume filtering (logical OR or AND)
-			if vol24hrMin > 0 || vol1wkMin > 0 || vol1moMin > 0 {
-				vol24hrPass := pe.Volume24hr >= vol24hrMin
-				vol1wkPass := pe.Volume1wk >= vol1wkMin
-				vol1moPass := pe.Volume1mo >= vol1moMin
-
-				if volumeFilterOR {
-					// Logical OR: include if ANY condition passes
-					if !vol24hrPass && !vol1wkPass && !vol1moPass {
-						continue
-					}
-				} else {
-					// Logical AND: include if ALL conditions pass
-					if !vol24hrPass || !vol1wkPass || !vol1moPass {
-						continue
-					}
-				}
-			}
-
 			// Extract primary category from tags (first matching tag or first tag overall)
 			primaryCategory := ""
 			if len(pe.Tags) > 0 {
@@ -232,26 +439,57 @@ func (c *Client) FetchEvents(ctx context.Context, categories []string, vol24hrMi
 					primaryCategory = pe.Tags[0].Slug
 				}
 			}
+			// Untagged events would otherwise fail market validation and be dropped
+			if primaryCategory == "" {
+				primaryCategory = c.defaultCategory
+			}
+
+			// Apply volume filtering (logical OR or AND), with the category's
+			// own floors when it has them
+			thresholds, ok := c.categoryVolumeMin[primaryCategory]
+			if !ok {
+				thresholds = VolumeThresholds{Volume24hrMin: vol24hrMin, Volume1wkMin: vol1wkMin, Volume1moMin: vol1moMin}
+			}
+			if !thresholds.passes(pe, volumeFilterOR) {
+				continue
+			}
 
 			// Process each market individually
 			// An event can have multiple markets, and we track each one separately
 			for _, market := range pe.Markets {
-				yesProb, noProb, err := parseMarketProbabilities(market)
+				yesProb, noProb, renormalized, err := parseMarketProbabilities(market, c.residualMode, c.positionalFallback)
 				if err != nil {
-					continue // Skip invalid markets
+					logger.Debug("Skipping market %s of event %s: %v", market.ID, pe.ID, err)
+					continue
+				}
+				if renormalized {
+					logger.Debug("Renormalized Yes/No of market %s of event %s over residual outcomes %s", market.ID, pe.ID, market.Outcomes)
+					renormalizedMarkets++
 				}
 
-				// Skip markets with no valid probability data
+				// A market is one probability series, or one per outcome when it
+				// is categorical
+				series := []outcomeSeries{{index: -1, yes: yesProb, no: noProb}}
 				if yesProb == 0 && noProb == 0 {
+					series = nil
+					if c.multiOutcome {
+						series = categoricalSeries(market)
+					}
+				}
+				// Skip markets with no valid probability data
+				if len(series) == 0 {
 					continue
 				}
 
 				// Capture current time once to ensure CreatedAt <= LastUpdated
 				now := time.Now()
 
-				// Always use composite ID format for consistency
-				// This prevents data loss when events transition from single to multi-market
-				compositeID := pe.ID + ":" + market.ID
+				// Single-market events sometimes omit the question; the event title
+				// is the question in that case.
+				question := market.Question
+				if question == "" {
+					question = pe.Title
+				}
 
 				// Use market-level volume for scoring accuracy in multi-market events
 				// Markets have volume1wk/volume1mo but not volume24hr
@@ -260,34 +498,62 @@ func (c *Client) FetchEvents(ctx context.Context, categories []string, vol24hrMi
 				marketVolume1mo := market.Volume1mo
 				marketVolume24hr := pe.Volume24hr // fallback to event-level
 
-				// Proportionally estimate 24hr volume from market's share of weekly volume
-				if pe.Volume1wk > 0 && marketVolume1wk > 0 {
+				// Proportionally estimate 24hr volume from market's share of weekly volume.
+				// A lone market's share is the whole event, so take its volumes directly.
+				if c.singleMarketVolume && len(pe.Markets) == 1 {
+					marketVolume1wk = pe.Volume1wk
+					marketVolume1mo = pe.Volume1mo
+				} else if pe.Volume1wk > 0 && marketVolume1wk > 0 {
 					marketShare := marketVolume1wk / pe.Volume1wk
 					marketVolume24hr = pe.Volume24hr * marketShare
 				}
 
-				event := models.Market{
-					ID:             compositeID,
-					EventID:        pe.ID,
-					MarketID:       market.ID,
-					MarketQuestion: market.Question,
-					Title:          pe.Title,
-					EventURL:       "https://polymarket.com/event/" + pe.Slug,
-					Description:    pe.Description,
-					Category:       primaryCategory,
-					Subcategory:    pe.Subcategory,
-					YesProbability: yesProb,
-					NoProbability:  noProb,
-					Volume24hr:     marketVolume24hr,
-					Volume1wk:      marketVolume1wk,
-					Volume1mo:      marketVolume1mo,
-					Liquidity:      pe.Liquidity,
-					Active:         pe.Active && !pe.Closed,
-					LastUpdated:    now,
-					CreatedAt:      now,
-				}
+				for _, s := range series {
+					// Always use composite ID format for consistency
+					// This prevents data loss when events transition from single to multi-market
+					compositeID := pe.ID + ":" + market.ID
+					marketQuestion := question
+					if s.index >= 0 {
+						compositeID += ":" + strconv.Itoa(s.index)
+						marketQuestion = s.label
+					}
 
-				allEvents = append(allEvents, event)
+					event := models.Market{
+						ID:             compositeID,
+						EventID:        pe.ID,
+						MarketID:       market.ID,
+						MarketQuestion: marketQuestion,
+						Title:          pe.Title,
+						EventURL:       buildEventURL(c.eventURLTemplate, pe.Slug, market.ID),
+						Description:    pe.Description,
+						Category:       primaryCategory,
+						Subcategory:    pe.Subcategory,
+						YesProbability: s.yes,
+						NoProbability:  s.no,
+						Volume24hr:     marketVolume24hr,
+						Volume1wk:      marketVolume1wk,
+						Volume1mo:      marketVolume1mo,
+						Liquidity:      pe.Liquidity,
+						Active:         pe.Active && !pe.Closed,
+						Closed:         pe.Closed || market.Closed,
+						LastUpdated:    now,
+						CreatedAt:      now,
+					}
+
+					// Shifting offsets under concurrent upstream updates can return the
+					// same market on two pages; keep the higher-volume occurrence.
+					if idx, dup := seen[compositeID]; dup {
+						if event.Volume24hr > allEvents[idx].Volume24hr {
+							allEvents[idx] = event
+						}
+						continue
+					}
+					seen[compositeID] = len(allEvents)
+					allEvents = append(allEvents, event)
+					if c.depthBand > 0 {
+						tokens[compositeID] = clobToken(market.ClobTokenIds, max(s.index, 0))
+					}
+				}
 			}
 		}
 
@@ -302,38 +568,179 @@ func (c *Client) FetchEvents(ctx context.Context, categories []string, vol24hrMi
 		}
 	}
 
+	if renormalizedMarkets > 0 {
+		logger.Info("Renormalized Yes/No probabilities of %d markets listing extra outcomes", renormalizedMarkets)
+	}
+
 	// Return top K after filtering
 	if len(allEvents) > limit {
 		allEvents = allEvents[:limit]
 	}
-
+	if c.depthBand > 0 {
+		c.applyBookDepth(ctx, allEvents, tokens)
+	}
 	return allEvents, nil
 }
 
+// fetchPage requests one page of active events from the Gamma API,
+// ordered by 24hr volume descending. A non-empty tagSlug restricts the
+// page to events carrying that tag. With includeClosed, closed events are
+// requested as well.
+func (c *Client) fetchPage(ctx context.Context, offset, pageSize int, tagSlug string) ([]PolymarketEvent, error) {
+	// Build URL with query parameters
+	u, err := url.Parse(c.gammaAPIURL + "/events")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL: %w", err)
+	}
+
+	q := u.Query()
+	if !c.includeClosed {
+		q.Set("active", "true")
+		q.Set("closed", "false")
+	}
+	q.Set("limit", fmt.Sprintf("%d", pageSize))
+	q.Set("offset", fmt.Sprintf("%d", offset))
+	if tagSlug != "" {
+		q.Set("tag_slug", tagSlug)
+	}
+
+	// Sort by volume24hr descending (one of the volume metrics)
+	q.Set("order", "volume24hr")
+	q.Set("ascending", "false")
+
+	u.RawQuery = q.Encode()
+
+	resp, err := c.doRequest(ctx, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch events from %s: %w", u.String(), err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Validate content type
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && contentType != "application/json" && !containsJSON(contentType) {
+		return nil, fmt.Errorf("unexpected content type: %s (expected application/json)", contentType)
+	}
+
+	// Response is array directly, not wrapped
+	var pmEvents []PolymarketEvent
+	if err := json.NewDecoder(resp.Body).Decode(&pmEvents); err != nil {
+		return nil, fmt.Errorf("failed to decode events JSON: %w", err)
+	}
+	return pmEvents, nil
+}
+
+// pageFetcher serves fetchMarkets' pages in offset order, requesting up to
+// c.fetchConcurrency pages at a time. A batch past the last page costs at most
+// fetchConcurrency-1 wasted requests.
+type pageFetcher struct {
+	c                  *Client
+	ctx                context.Context
+	pageSize, maxFetch int
+	tagSlug            string
+
+	pages map[int][]PolymarketEvent // fetched, not yet served
+}
+
+// get returns the page at offset, fetching it together with the following
+// pages when it isn't already prefetched. Any failed page fails the batch:
+// the first error cancels the pages still in flight and is returned.
+func (p *pageFetcher) get(offset int) ([]PolymarketEvent, error) {
+	if page, ok := p.pages[offset]; ok {
+		delete(p.pages, offset)
+		return page, nil
+	}
+
+	var offsets []int
+	for o := offset; o < p.maxFetch && len(offsets) < max(p.c.fetchConcurrency, 1); o += p.pageSize {
+		offsets = append(offsets, o)
+	}
+	if len(offsets) == 1 {
+		return p.c.fetchPage(p.ctx, offset, p.pageSize, p.tagSlug)
+	}
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+	results := make([][]PolymarketEvent, len(offsets))
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i, o := range offsets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			page, err := p.c.fetchPage(ctx, o, p.pageSize, p.tagSlug)
+			if err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			results[i] = page
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	p.pages = make(map[int][]PolymarketEvent, len(offsets)-1)
+	for i, o := range offsets[1:] {
+		p.pages[o] = results[i+1]
+	}
+	return results[0], nil
+}
+
+// buildEventURL expands the {slug} and {marketID} placeholders in an event URL template.
+func buildEventURL(template, slug, marketID string) string {
+	return strings.NewReplacer("{slug}", slug, "{marketID}", marketID).Replace(template)
+}
+
+// minDriftSample is the smallest page considered for schema drift detection,
+// so a short trailing page cannot trip the check on its own.
+const minDriftSample = 10
+
+// suspiciousEventFraction returns the fraction of events that have all-zero volume
+// fields and no tags — the shape produced when the API renames those fields.
+// Pages smaller than minDriftSample return 0.
+func suspiciousEventFraction(events []PolymarketEvent) float64 {
+	if len(events) < minDriftSample {
+		return 0
+	}
+	suspicious := 0
+	for _, pe := range events {
+		if pe.Volume == 0 && pe.Volume24hr == 0 && pe.Volume1wk == 0 && pe.Volume1mo == 0 && len(pe.Tags) == 0 {
+			suspicious++
+		}
+	}
+	return float64(suspicious) / float64(len(events))
+}
+
 // parseMarketProbabilities extracts Yes/No probabilities from a market
-func parseMarketProbabilities(market PolymarketMarket) (float64, float64, error) {
+func parseMarketProbabilities(market PolymarketMarket, residualMode string, positional bool) (yesProb, noProb float64, renormalized bool, err error) {
 	// Parse outcomes JSON string
 	var outcomes []string
 	if err := json.Unmarshal([]byte(market.Outcomes), &outcomes); err != nil {
-		return 0, 0, fmt.Errorf("failed to parse outcomes: %w", err)
+		return 0, 0, false, fmt.Errorf("failed to parse outcomes: %w", err)
 	}
 
 	// Parse outcome prices JSON string
 	var outcomePrices []string
 	if err := json.Unmarshal([]byte(market.OutcomePrices), &outcomePrices); err != nil {
-		return 0, 0, fmt.Errorf("failed to parse outcome prices: %w", err)
+		return 0, 0, false, fmt.Errorf("failed to parse outcome prices: %w", err)
 	}
 
 	// Extract Yes/No probabilities
-	var yesProb, noProb float64
+	residual := false
 	for i, outcome := range outcomes {
 		if i >= len(outcomePrices) {
 			break
 		}
 
-		var price float64
-		if _, err := fmt.Sscanf(outcomePrices[i], "%f", &price); err != nil {
-			return 0, 0, fmt.Errorf("failed to parse price '%s': %w", outcomePrices[i], err)
+		price, err := parsePrice(outcomePrices[i])
+		if err != nil {
+			return 0, 0, false, err
 		}
 
 		switch outcome {
@@ -341,10 +748,107 @@ func parseMarketProbabilities(market PolymarketMarket) (float64, float64, error)
 			yesProb = price
 		case "No":
 			noProb = price
+		default:
+			residual = true
+		}
+	}
+
+	// Outcome labels other than Yes/No ("Up"/"Down", "yes"/"no") on a genuine
+	// two-price market: read the prices as Yes then No, their API order.
+	if yesProb == 0 && noProb == 0 && positional && len(outcomePrices) == 2 {
+		if yesProb, err = parsePrice(outcomePrices[0]); err != nil {
+			return 0, 0, false, err
+		}
+		if noProb, err = parsePrice(outcomePrices[1]); err != nil {
+			return 0, 0, false, err
 		}
+		logger.Debug("Market %s has outcomes %s; mapped prices to Yes/No by position", market.ID, market.Outcomes)
+		return yesProb, noProb, false, nil
+	}
+
+	// A residual outcome ("Other") holds part of the probability mass, leaving
+	// Yes + No short of 1. Rescale the pair so it still reads as binary.
+	sum := yesProb + noProb
+	if residual && residualMode != ResidualIgnore && yesProb > 0 && noProb > 0 && math.Abs(sum-1) > 1e-9 {
+		return yesProb / sum, noProb / sum, true, nil
 	}
+	return yesProb, noProb, false, nil
+}
+
+// Outcome is one labeled outcome of a market and its price.
+type Outcome struct {
+	Label string
+	Price float64
+}
+
+// parseOutcomes returns every outcome of a market with its price, in API
+// order. Labels without a price are dropped.
+func parseOutcomes(market PolymarketMarket) ([]Outcome, error) {
+	var labels, prices []string
+	if err := json.Unmarshal([]byte(market.Outcomes), &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse outcomes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(market.OutcomePrices), &prices); err != nil {
+		return nil, fmt.Errorf("failed to parse outcome prices: %w", err)
+	}
+	outcomes := make([]Outcome, 0, min(len(labels), len(prices)))
+	for i := 0; i < len(labels) && i < len(prices); i++ {
+		price, err := parsePrice(prices[i])
+		if err != nil {
+			return nil, err
+		}
+		outcomes = append(outcomes, Outcome{Label: labels[i], Price: price})
+	}
+	return outcomes, nil
+}
+
+// outcomeSeries is one probability series fetched from a market: the market
+// itself (index -1), or one outcome of a categorical market, read as
+// "this outcome" (yes) against "any other" (no).
+type outcomeSeries struct {
+	index   int
+	label   string
+	yes, no float64
+}
+
+// categoricalSeries returns one series per outcome of a market with more than
+// two outcomes and no Yes/No labels, or nil for any other market.
+func categoricalSeries(market PolymarketMarket) []outcomeSeries {
+	outcomes, err := parseOutcomes(market)
+	if err != nil || len(outcomes) <= 2 {
+		return nil
+	}
+	series := make([]outcomeSeries, 0, len(outcomes))
+	for i, o := range outcomes {
+		if o.Price < 0 || o.Price > 1 {
+			return nil
+		}
+		series = append(series, outcomeSeries{index: i, label: o.Label, yes: o.Price, no: 1 - o.Price})
+	}
+	return series
+}
 
-	return yesProb, noProb, nil
+// parsePrice parses one outcome price string. Surrounding whitespace is
+// ignored, scientific notation ("5e-3") is accepted, and a lone decimal comma
+// ("0,75") is read as a decimal point; with both separators present, commas
+// are taken as thousands separators. NaN and infinities are rejected.
+func parsePrice(s string) (float64, error) {
+	t := strings.TrimSpace(s)
+	if strings.Contains(t, ",") {
+		if strings.Contains(t, ".") {
+			t = strings.ReplaceAll(t, ",", "")
+		} else {
+			t = strings.Replace(t, ",", ".", 1)
+		}
+	}
+	price, err := strconv.ParseFloat(t, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse price %q: %w", s, err)
+	}
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, fmt.Errorf("failed to parse price %q: not a finite number", s)
+	}
+	return price, nil
 }
 
 // containsJSON checks if a content-type header indicates JSON
@@ -353,6 +857,35 @@ func containsJSON(contentType string) bool {
 		strings.HasPrefix(contentType, "application/json;")
 }
 
+// maxRetryAfter caps how long a rate-limited request waits before retrying,
+// whatever Retry-After asks for, so one response can't stall the cycle.
+const maxRetryAfter = time.Minute
+
+// parseRetryAfter reads a Retry-After header, in delta seconds or as an HTTP
+// date, as a delay from now. A date in the past is a zero delay.
+func parseRetryAfter(h string, now time.Time) (time.Duration, bool) {
+	if h == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(strings.TrimSpace(h)); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// backoff returns the exponential delay before retry attempt+1, with ±50%
+// jitter so parallel clients don't retry in lockstep.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := float64(c.retryDelayBase) * math.Pow(2, float64(attempt))
+	c.rngMu.Lock()
+	jitter := c.rng.Float64()
+	c.rngMu.Unlock()
+	return time.Duration(d * (0.5 + jitter))
+}
+
 // doRequest performs HTTP request with retry logic
 func (c *Client) doRequest(ctx context.Context, urlStr string) (*http.Response, error) {
 	var lastErr error
@@ -375,6 +908,7 @@ func (c *Client) doRequest(ctx context.Context, urlStr string) (*http.Response,
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
 			lastErr = err
+			metrics.APIErrors.Inc("transport")
 			// Exponential backoff with context check
 			select {
 			case <-ctx.Done():
@@ -385,9 +919,26 @@ func (c *Client) doRequest(ctx context.Context, urlStr string) (*http.Response,
 		}
 
 		// Handle various HTTP status codes
+		if resp.StatusCode == http.StatusTooManyRequests {
+			_ = resp.Body.Close()
+			lastErr = fmt.Errorf("rate limited (status %d): %s", resp.StatusCode, resp.Status)
+			metrics.APIErrors.Inc("rate_limited")
+			delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			if !ok {
+				delay = c.backoff(i)
+			}
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("request cancelled during retry: %w", ctx.Err())
+			case <-time.After(min(delay, maxRetryAfter)):
+				continue
+			}
+		}
+
 		if resp.StatusCode >= 500 {
 			_ = resp.Body.Close()
 			lastErr = fmt.Errorf("server error (status %d): %s", resp.StatusCode, resp.Status)
+			metrics.APIErrors.Inc("server")
 			select {
 			case <-ctx.Done():
 				return nil, fmt.Errorf("request cancelled during retry: %w", ctx.Err())
@@ -398,6 +949,7 @@ func (c *Client) doRequest(ctx context.Context, urlStr string) (*http.Response,
 
 		if resp.StatusCode >= 400 {
 			_ = resp.Body.Close()
+			metrics.APIErrors.Inc("client")
 			return nil, fmt.Errorf("client error (status %d): %s", resp.StatusCode, resp.Status)
 		}
 
@@ -3,10 +3,19 @@ package polymarket
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
 )
 
 func TestFetchEvents_RealAPIFormat(t *testing.T) {
@@ -443,6 +452,99 @@ func TestFetchEvents_MultiMarketVolumeAllocation(t *testing.T) {
 	}
 }
 
+func TestFetchEvents_SchemaDrift(t *testing.T) {
+	tests := []struct {
+		name        string
+		driftEvents int // events with zero volume and no tags, out of 20
+		wantDrift   bool
+	}{
+		{"healthy page", 0, false},
+		{"below fraction", 5, false},
+		{"renamed fields", 15, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				events := make([]PolymarketEvent, 20)
+				for i := range events {
+					events[i] = PolymarketEvent{ID: fmt.Sprintf("event-%d", i), Title: "Test", Active: true}
+					if i >= tt.driftEvents {
+						events[i].Volume24hr = 50000.0
+						events[i].Tags = []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}}
+					}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(events); err != nil {
+					t.Errorf("Failed to encode events: %v", err)
+				}
+			}))
+			defer mockServer.Close()
+
+			client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{SchemaDriftFraction: 0.5})
+			if _, err := client.FetchEvents(context.Background(), []string{"politics"}, 0, 0, 0, true, 10); err != nil {
+				t.Fatalf("FetchEvents failed: %v", err)
+			}
+			fraction, drifted := client.SchemaDrift()
+			if drifted != tt.wantDrift {
+				t.Errorf("SchemaDrift() drifted = %v (fraction %.2f), want %v", drifted, fraction, tt.wantDrift)
+			}
+		})
+	}
+}
+
+func TestFetchEvents_RetryOnEmpty(t *testing.T) {
+	tests := []struct {
+		name         string
+		retryOnEmpty bool
+		wantMarkets  int
+		wantRequests int
+	}{
+		{"retry recovers flaky empty page", true, 1, 3},
+		{"disabled treats empty as done", false, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				events := []PolymarketEvent{}
+				// First cycle succeeds, second cycle's first attempt is transiently empty.
+				if requests != 2 {
+					events = append(events, PolymarketEvent{
+						ID: "event-1", Title: "Test", Active: true, Volume24hr: 50000.0,
+						Markets: []PolymarketMarket{{ID: "market-1", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.6\", \"0.4\"]"}},
+						Tags:    []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}},
+					})
+				}
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(events); err != nil {
+					t.Errorf("Failed to encode events: %v", err)
+				}
+			}))
+			defer mockServer.Close()
+
+			client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second,
+				ClientConfig{RetryOnEmpty: tt.retryOnEmpty, RetryDelayBase: time.Millisecond})
+			ctx := context.Background()
+			if _, err := client.FetchEvents(ctx, []string{"politics"}, 0, 0, 0, true, 10); err != nil {
+				t.Fatalf("first FetchEvents failed: %v", err)
+			}
+			events, err := client.FetchEvents(ctx, []string{"politics"}, 0, 0, 0, true, 10)
+			if err != nil {
+				t.Fatalf("second FetchEvents failed: %v", err)
+			}
+			if len(events) != tt.wantMarkets {
+				t.Errorf("got %d markets, want %d", len(events), tt.wantMarkets)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
 func TestParseMarketProbabilities(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -507,7 +609,7 @@ func TestParseMarketProbabilities(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			yes, no, err := parseMarketProbabilities(tt.market)
+			yes, no, _, err := parseMarketProbabilities(tt.market, ResidualDistribute, false)
 			if tt.expectError {
 				if err == nil {
 					t.Error("Expected error, got nil")
@@ -527,6 +629,105 @@ func TestParseMarketProbabilities(t *testing.T) {
 	}
 }
 
+func TestParseMarketProbabilities_ResidualOutcome(t *testing.T) {
+	market := PolymarketMarket{
+		Outcomes:      `["Yes", "No", "Other"]`,
+		OutcomePrices: `["0.57", "0.38", "0.05"]`,
+	}
+
+	yes, no, renormalized, err := parseMarketProbabilities(market, ResidualDistribute, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !renormalized || math.Abs(yes-0.6) > 1e-9 || math.Abs(no-0.4) > 1e-9 {
+		t.Errorf("distribute: yes=%v no=%v renormalized=%v, want 0.6/0.4 renormalized", yes, no, renormalized)
+	}
+	m := models.Market{ID: "e:m", EventID: "e", Title: "t", Category: "c", YesProbability: yes, NoProbability: no}
+	if err := m.Validate(); err != nil {
+		t.Errorf("renormalized market fails validation: %v", err)
+	}
+
+	yes, no, renormalized, _ = parseMarketProbabilities(market, ResidualIgnore, false)
+	if renormalized || yes != 0.57 || no != 0.38 {
+		t.Errorf("ignore: yes=%v no=%v renormalized=%v, want raw 0.57/0.38", yes, no, renormalized)
+	}
+
+	// Two-outcome markets are never rescaled, even when slightly off 1
+	yes, _, renormalized, _ = parseMarketProbabilities(PolymarketMarket{
+		Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.505", "0.5"]`,
+	}, ResidualDistribute, false)
+	if renormalized || yes != 0.505 {
+		t.Errorf("binary market rescaled: yes=%v renormalized=%v", yes, renormalized)
+	}
+}
+
+func TestFetchEvents_DuplicateAcrossPages(t *testing.T) {
+	market := func(eventID string, vol float64) PolymarketEvent {
+		return PolymarketEvent{
+			ID: eventID, Title: "Test " + eventID, Active: true, Volume24hr: vol,
+			Markets: []PolymarketMarket{{ID: "m", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.5\", \"0.5\"]"}},
+			Tags:    []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}},
+		}
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var events []PolymarketEvent
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			// Full first page so pagination continues
+			for i := 0; i < 500; i++ {
+				events = append(events, market(fmt.Sprintf("event-%d", i), 50000.0))
+			}
+		case "500":
+			// event-499 shifted across the page boundary with a fresher volume figure
+			events = append(events, market("event-499", 60000.0), market("event-500", 40000.0))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(events); err != nil {
+			t.Errorf("Failed to encode events: %v", err)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second)
+	events, err := client.FetchEvents(context.Background(), []string{"politics"}, 0, 0, 0, true, 1000)
+	if err != nil {
+		t.Fatalf("FetchEvents failed: %v", err)
+	}
+
+	if len(events) != 501 {
+		t.Fatalf("got %d markets, want 501 (one duplicate dropped)", len(events))
+	}
+	count := 0
+	for _, e := range events {
+		if e.ID == "event-499:m" {
+			count++
+			if e.Volume24hr != 60000.0 {
+				t.Errorf("kept duplicate has volume %.0f, want the higher 60000", e.Volume24hr)
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("event-499:m appears %d times, want 1", count)
+	}
+}
+
+func TestBuildEventURL(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{DefaultEventURLTemplate, "https://polymarket.com/event/btc-targets"},
+		{"https://mirror.example/e/{slug}?tid={marketID}", "https://mirror.example/e/btc-targets?tid=m-42"},
+		{"https://mirror.example/m/{marketID}", "https://mirror.example/m/m-42"},
+	}
+	for _, tt := range tests {
+		if got := buildEventURL(tt.template, "btc-targets", "m-42"); got != tt.want {
+			t.Errorf("buildEventURL(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
 func TestContainsJSON(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -553,3 +754,612 @@ func TestContainsJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchEvents_PageSize(t *testing.T) {
+	const total = 23 // events available upstream
+	var requests []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		requests = append(requests, q.Get("offset")+"/"+q.Get("limit"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+
+		var events []PolymarketEvent
+		for i := offset; i < offset+limit && i < total; i++ {
+			events = append(events, PolymarketEvent{
+				ID: fmt.Sprintf("event-%d", i), Title: "Test", Active: true, Volume24hr: 50000.0,
+				Markets: []PolymarketMarket{{ID: "m", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.5\", \"0.5\"]"}},
+				Tags:    []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(events); err != nil {
+			t.Errorf("Failed to encode events: %v", err)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{PageSize: 10, FetchConcurrency: 1})
+	events, err := client.FetchEvents(context.Background(), []string{"politics"}, 0, 0, 0, true, 100)
+	if err != nil {
+		t.Fatalf("FetchEvents failed: %v", err)
+	}
+
+	if len(events) != total {
+		t.Errorf("got %d markets, want %d", len(events), total)
+	}
+	// Two full pages, then a short last page ends pagination.
+	want := []string{"0/10", "10/10", "20/10"}
+	if fmt.Sprint(requests) != fmt.Sprint(want) {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestFetchEvents_ConcurrentPages(t *testing.T) {
+	const total = 23
+	var mu sync.Mutex
+	var offsets []int
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		mu.Lock()
+		offsets = append(offsets, offset)
+		mu.Unlock()
+
+		var events []PolymarketEvent
+		for i := offset; i < offset+10 && i < total; i++ {
+			events = append(events, PolymarketEvent{
+				ID: fmt.Sprintf("event-%02d", i), Title: "Test", Active: true, Volume24hr: float64(1000 - i),
+				Markets: []PolymarketMarket{{ID: "m", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.5\", \"0.5\"]"}},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(events)
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{PageSize: 10, FetchConcurrency: 4})
+	events, err := client.FetchEvents(context.Background(), nil, 0, 0, 0, true, 100)
+	if err != nil {
+		t.Fatalf("FetchEvents failed: %v", err)
+	}
+
+	if len(events) != total {
+		t.Fatalf("got %d markets, want %d", len(events), total)
+	}
+	for i, e := range events {
+		if want := fmt.Sprintf("event-%02d", i); e.EventID != want {
+			t.Fatalf("events[%d] = %s, want %s: pages merged out of offset order", i, e.EventID, want)
+		}
+	}
+	// One batch of four pages; the short page at 20 ends pagination.
+	slices.Sort(offsets)
+	if fmt.Sprint(offsets) != fmt.Sprint([]int{0, 10, 20, 30}) {
+		t.Errorf("offsets = %v, want one batch [0 10 20 30]", offsets)
+	}
+}
+
+func TestFetchEvents_ConcurrentPageErrorCancelsBatch(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "10":
+			http.Error(w, "bad request", http.StatusBadRequest)
+		case "0":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte("[]"))
+		default:
+			<-r.Context().Done() // stalls until the failed page cancels it
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{PageSize: 10, FetchConcurrency: 4})
+	start := time.Now()
+	_, err := client.FetchEvents(context.Background(), nil, 0, 0, 0, true, 100)
+	if err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("err = %v, want the offset-10 client error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("FetchEvents took %v; in-flight pages were not cancelled", elapsed)
+	}
+}
+
+func TestFetchEvents_PerCategoryFetch(t *testing.T) {
+	// Upstream: the top event carries both tags, then 10 high-volume politics
+	// events, then 2 low-volume science events.
+	mkEvent := func(id string, vol float64, slugs ...string) PolymarketEvent {
+		e := PolymarketEvent{
+			ID: id, Title: "Test", Active: true, Volume24hr: vol,
+			Markets: []PolymarketMarket{{ID: "m", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.5\", \"0.5\"]"}},
+		}
+		for _, s := range slugs {
+			e.Tags = append(e.Tags, PolymarketTag{ID: s, Label: s, Slug: s})
+		}
+		return e
+	}
+	upstream := []PolymarketEvent{mkEvent("both", 200000, "politics", "science")}
+	for i := range 10 {
+		upstream = append(upstream, mkEvent(fmt.Sprintf("pol-%d", i), 100000, "politics"))
+	}
+	upstream = append(upstream, mkEvent("sci-1", 100, "science"), mkEvent("sci-2", 50, "science"))
+
+	var tagQueries []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		tag := q.Get("tag_slug")
+		tagQueries = append(tagQueries, tag)
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+
+		var matching []PolymarketEvent
+		for _, e := range upstream {
+			if tag == "" || slices.ContainsFunc(e.Tags, func(t PolymarketTag) bool { return t.Slug == tag }) {
+				matching = append(matching, e)
+			}
+		}
+		events := []PolymarketEvent{}
+		if offset < len(matching) {
+			events = matching[offset:min(offset+limit, len(matching))]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(events); err != nil {
+			t.Errorf("Failed to encode events: %v", err)
+		}
+	}))
+	defer mockServer.Close()
+
+	categories := []string{"politics", "science"}
+
+	tests := []struct {
+		name        string
+		perCategory bool
+		wantTags    []string
+		wantScience int
+	}{
+		// Global ordering: science events sort below the politics cutoff.
+		{"global", false, []string{""}, 0},
+		// Per category: up to ceil(4/2) each; "both" leads both queries but is kept once.
+		{"per category", true, []string{"politics", "science"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagQueries = nil
+			client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second,
+				ClientConfig{PageSize: 2, PerCategoryFetch: tt.perCategory})
+			markets, err := client.FetchEvents(context.Background(), categories, 0, 0, 0, true, 4)
+			if err != nil {
+				t.Fatalf("FetchEvents failed: %v", err)
+			}
+
+			seen := make(map[string]bool)
+			science := 0
+			for _, m := range markets {
+				if seen[m.ID] {
+					t.Errorf("market %s returned twice", m.ID)
+				}
+				seen[m.ID] = true
+				if strings.HasPrefix(m.EventID, "sci-") {
+					science++
+				}
+			}
+			if science != tt.wantScience {
+				t.Errorf("got %d science-only markets, want %d", science, tt.wantScience)
+			}
+			if got := slices.Compact(slices.Clone(tagQueries)); fmt.Sprint(got) != fmt.Sprint(tt.wantTags) {
+				t.Errorf("tag_slug queries = %v, want %v", got, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestFetchEvents_SingleMarketVolume(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events := []PolymarketEvent{
+			{
+				ID:         "single",
+				Title:      "Single-market event",
+				Active:     true,
+				Volume24hr: 1200,
+				Volume1wk:  0, // no weekly figure: estimation can't compute a share
+				Volume1mo:  30000,
+				Markets: []PolymarketMarket{
+					{ID: "m1", Question: "Yes?", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.4\", \"0.6\"]", Volume1wk: 5000, Volume1mo: 20000},
+				},
+				Tags: []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}},
+			},
+			{
+				ID:         "multi",
+				Title:      "Multi-market event",
+				Active:     true,
+				Volume24hr: 1000,
+				Volume1wk:  10000,
+				Volume1mo:  40000,
+				Markets: []PolymarketMarket{
+					{ID: "m2", Question: "A?", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.4\", \"0.6\"]", Volume1wk: 2500, Volume1mo: 10000},
+					{ID: "m3", Question: "B?", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.6\", \"0.4\"]", Volume1wk: 7500, Volume1mo: 30000},
+				},
+				Tags: []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(events)
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{SingleMarketVolume: true})
+	markets, err := client.FetchEvents(context.Background(), []string{"politics"}, 0, 0, 0, true, 10)
+	if err != nil {
+		t.Fatalf("FetchEvents failed: %v", err)
+	}
+	byID := make(map[string]models.Market, len(markets))
+	for _, m := range markets {
+		byID[m.ID] = m
+	}
+
+	single := byID["single:m1"]
+	if single.Volume24hr != 1200 || single.Volume1wk != 0 || single.Volume1mo != 30000 {
+		t.Errorf("single-market volumes = %v/%v/%v, want event volumes 1200/0/30000",
+			single.Volume24hr, single.Volume1wk, single.Volume1mo)
+	}
+	// Multi-market events keep the proportional estimate.
+	if got := byID["multi:m2"].Volume24hr; got != 250 {
+		t.Errorf("multi-market Volume24hr = %v, want 250 (25%% share)", got)
+	}
+}
+
+func TestFetchEvents_DefaultCategory(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events := []PolymarketEvent{
+			{
+				ID:         "event-1",
+				Title:      "Untagged event",
+				Active:     true,
+				Volume24hr: 1000,
+				Markets: []PolymarketMarket{
+					{ID: "market-1", Question: "Untagged?", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.4\", \"0.6\"]"},
+				},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(events)
+	}))
+	defer mockServer.Close()
+
+	tests := []struct {
+		name string
+		cfg  ClientConfig
+		want string
+	}{
+		{"built-in default", ClientConfig{}, DefaultCategory},
+		{"configured", ClientConfig{DefaultCategory: "misc"}, "misc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, tt.cfg)
+			markets, err := client.FetchEvents(context.Background(), nil, 0, 0, 0, true, 10)
+			if err != nil {
+				t.Fatalf("FetchEvents failed: %v", err)
+			}
+			if len(markets) != 1 {
+				t.Fatalf("Expected 1 market, got %d", len(markets))
+			}
+			if markets[0].Category != tt.want {
+				t.Errorf("Category = %q, want %q", markets[0].Category, tt.want)
+			}
+			if err := markets[0].Validate(); err != nil {
+				t.Errorf("untagged market fails validation: %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchEvents_IncludeClosed(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Has("active") || query.Has("closed") {
+			t.Errorf("Expected no active/closed filter, got active=%q closed=%q", query.Get("active"), query.Get("closed"))
+		}
+		events := []PolymarketEvent{
+			{
+				ID:         "event-1",
+				Title:      "Resolved event",
+				Closed:     true,
+				Volume24hr: 1000,
+				Markets: []PolymarketMarket{
+					{ID: "market-1", Question: "Resolved?", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"1\", \"0\"]", Closed: true},
+				},
+				Tags: []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}},
+			},
+			{
+				ID:         "event-2",
+				Title:      "Open event",
+				Active:     true,
+				Volume24hr: 500,
+				Markets: []PolymarketMarket{
+					{ID: "market-2", Question: "Open?", Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.4\", \"0.6\"]"},
+				},
+				Tags: []PolymarketTag{{ID: "1", Label: "Politics", Slug: "politics"}},
+			},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(events)
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{IncludeClosed: true})
+	markets, err := client.FetchEvents(context.Background(), []string{"politics"}, 0, 0, 0, true, 10)
+	if err != nil {
+		t.Fatalf("FetchEvents failed: %v", err)
+	}
+	if len(markets) != 2 {
+		t.Fatalf("Expected 2 markets, got %d", len(markets))
+	}
+	closed := map[string]bool{}
+	for _, m := range markets {
+		closed[m.EventID] = m.Closed
+	}
+	if !closed["event-1"] {
+		t.Error("Expected the resolved market to carry Closed=true")
+	}
+	if closed["event-2"] {
+		t.Error("Expected the open market to carry Closed=false")
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"0.75", 0.75, false},
+		{" 0.75\t", 0.75, false},
+		{"5e-3", 0.005, false},
+		{"1E-4", 0.0001, false},
+		{"0,75", 0.75, false},
+		{"1,000.5", 1000.5, false},
+		{"1", 1, false},
+		{"", 0, true},
+		{"   ", 0, true},
+		{"0.75abc", 0, true},
+		{"NaN", 0, true},
+		{"Inf", 0, true},
+		{"0,7,5", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parsePrice(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePrice(%q) = %v, want error", tt.input, got)
+				} else if !strings.Contains(err.Error(), strconv.Quote(tt.input)) {
+					t.Errorf("error %q should quote the offending string", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePrice(%q): %v", tt.input, err)
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("parsePrice(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchEvents_CategoryVolumeMin(t *testing.T) {
+	event := func(id, category string, vol24hr float64) PolymarketEvent {
+		return PolymarketEvent{
+			ID:         id,
+			Title:      id,
+			Active:     true,
+			Volume24hr: vol24hr,
+			Markets:    []PolymarketMarket{{Outcomes: "[\"Yes\", \"No\"]", OutcomePrices: "[\"0.5\", \"0.5\"]"}},
+			Tags:       []PolymarketTag{{ID: category, Label: category, Slug: category}},
+		}
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events := []PolymarketEvent{
+			event("crypto-thin", "crypto", 50000),     // below the crypto floor
+			event("crypto-deep", "crypto", 500000),    // above it
+			event("world-small", "world", 5000),       // above the world floor, below the global one
+			event("politics-small", "politics", 5000), // below the global floor
+			event("politics-big", "politics", 50000),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(events); err != nil {
+			t.Errorf("Failed to encode events: %v", err)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{
+		CategoryVolumeMin: map[string]VolumeThresholds{
+			"crypto": {Volume24hrMin: 250000},
+			"world":  {Volume24hrMin: 1000},
+		},
+	})
+	events, err := client.FetchEvents(context.Background(), nil, 25000, 0, 0, false, 10)
+	if err != nil {
+		t.Fatalf("FetchEvents failed: %v", err)
+	}
+
+	var got []string
+	for _, e := range events {
+		got = append(got, e.EventID)
+	}
+	slices.Sort(got)
+	want := []string{"crypto-deep", "politics-big", "world-small"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+func TestFetchEvents_MultiOutcome(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		events := []PolymarketEvent{{
+			ID: "e1", Title: "Who wins?", Active: true, Volume24hr: 50000,
+			Markets: []PolymarketMarket{
+				{ID: "cat", Question: "Who wins?", Outcomes: `["Alice", "Bob", "Carol"]`, OutcomePrices: `["0.5", "0.3", "0.2"]`},
+				{ID: "bin", Question: "Turnout above 60%?", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.4", "0.6"]`},
+			},
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(events)
+	}))
+	defer mockServer.Close()
+
+	for _, enabled := range []bool{true, false} {
+		client := NewClient(mockServer.URL, "https://clob.polymarket.com", 30*time.Second, ClientConfig{MultiOutcome: enabled})
+		markets, err := client.FetchEvents(context.Background(), nil, 0, 0, 0, false, 10)
+		if err != nil {
+			t.Fatalf("FetchEvents failed: %v", err)
+		}
+
+		var got []string
+		for _, m := range markets {
+			got = append(got, fmt.Sprintf("%s %q %.1f/%.1f", m.ID, m.MarketQuestion, m.YesProbability, m.NoProbability))
+			if err := m.Validate(); err != nil {
+				t.Errorf("%s: %v", m.ID, err)
+			}
+		}
+		slices.Sort(got)
+		want := []string{`e1:bin "Turnout above 60%?" 0.4/0.6`}
+		if enabled {
+			want = []string{
+				`e1:bin "Turnout above 60%?" 0.4/0.6`,
+				`e1:cat:0 "Alice" 0.5/0.5`,
+				`e1:cat:1 "Bob" 0.3/0.7`,
+				`e1:cat:2 "Carol" 0.2/0.8`,
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("MultiOutcome=%v: got\n%s\nwant\n%s", enabled, strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+}
+
+func TestParseMarketProbabilities_PositionalFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		market   PolymarketMarket
+		yes, no  float64
+		mapped   bool // expected non-zero with the fallback on
+		wantsErr bool
+	}{
+		{"mislabeled binary", PolymarketMarket{Outcomes: `["Up", "Down"]`, OutcomePrices: `["0.62", "0.38"]`}, 0.62, 0.38, true, false},
+		{"lowercase labels", PolymarketMarket{Outcomes: `["yes", "no"]`, OutcomePrices: `["0.1", "0.9"]`}, 0.1, 0.9, true, false},
+		{"labels missing", PolymarketMarket{Outcomes: `[]`, OutcomePrices: `["0.3", "0.7"]`}, 0.3, 0.7, true, false},
+		{"empty prices", PolymarketMarket{Outcomes: `["Up", "Down"]`, OutcomePrices: `[]`}, 0, 0, false, false},
+		{"three prices", PolymarketMarket{Outcomes: `["A", "B", "C"]`, OutcomePrices: `["0.2", "0.3", "0.5"]`}, 0, 0, false, false},
+		{"unparseable price", PolymarketMarket{Outcomes: `[]`, OutcomePrices: `["n/a", "0.5"]`}, 0, 0, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yes, no, _, err := parseMarketProbabilities(tt.market, ResidualDistribute, true)
+			if (err != nil) != tt.wantsErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantsErr)
+			}
+			if yes != tt.yes || no != tt.no {
+				t.Errorf("yes=%v no=%v, want %v/%v", yes, no, tt.yes, tt.no)
+			}
+			if !tt.mapped {
+				return
+			}
+			// With the fallback off the market is left at 0/0 and skipped
+			if yes, no, _, _ := parseMarketProbabilities(tt.market, ResidualDistribute, false); yes != 0 || no != 0 {
+				t.Errorf("fallback off: yes=%v no=%v, want 0/0", yes, no)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{" 0 ", 0, true},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+		{"-3", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDoRequest_RetriesRateLimit(t *testing.T) {
+	attempts := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests) // no header: jittered backoff
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, mockServer.URL, 30*time.Second, ClientConfig{RetryDelayBase: time.Millisecond})
+	resp, err := client.doRequest(context.Background(), mockServer.URL)
+	if err != nil {
+		t.Fatalf("doRequest: %v", err)
+	}
+	_ = resp.Body.Close()
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+
+	attempts = 0
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	if _, err := client.doRequest(context.Background(), limited.URL); err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("persistent 429: err = %v, want rate limited after max retries", err)
+	}
+	if attempts != 3 {
+		t.Errorf("persistent 429: attempts = %d, want maxRetries (3)", attempts)
+	}
+}
+
+func TestBackoff_SeededJitter(t *testing.T) {
+	delays := func(seed int64) []time.Duration {
+		client := NewClient("http://unused", "http://unused", time.Second,
+			ClientConfig{RetryDelayBase: time.Second, Rand: rand.New(rand.NewSource(seed))})
+		var out []time.Duration
+		for attempt := range 4 {
+			d := client.backoff(attempt)
+			base := time.Second << attempt
+			if d < base/2 || d > base*3/2 {
+				t.Errorf("attempt %d: delay %v outside ±50%% of %v", attempt, d, base)
+			}
+			out = append(out, d)
+		}
+		return out
+	}
+
+	first := delays(42)
+	if again := delays(42); !slices.Equal(first, again) {
+		t.Errorf("same seed gave %v then %v", first, again)
+	}
+	if other := delays(7); slices.Equal(first, other) {
+		t.Errorf("seeds 42 and 7 gave the same delays %v", first)
+	}
+}
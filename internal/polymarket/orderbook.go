@@ -0,0 +1,159 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/rewired-gh/polyoracle/internal/logger"
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+// bookFetchConcurrency bounds simultaneous CLOB /book requests per fetch.
+const bookFetchConcurrency = 8
+
+// BookLevel is one price level of an order book.
+type BookLevel struct {
+	Price float64
+	Size  float64 // shares
+}
+
+// OrderBook is a CLOB order book for one outcome token.
+type OrderBook struct {
+	Bids []BookLevel
+	Asks []BookLevel
+}
+
+// clobBook is the CLOB /book response; prices and sizes are decimal strings.
+type clobBook struct {
+	Bids []clobLevel `json:"bids"`
+	Asks []clobLevel `json:"asks"`
+}
+
+type clobLevel struct {
+	Price string `json:"price"`
+	Size  string `json:"size"`
+}
+
+// FetchOrderBook requests the order book of one outcome token from the CLOB API.
+func (c *Client) FetchOrderBook(ctx context.Context, clobTokenID string) (*OrderBook, error) {
+	resp, err := c.doRequest(ctx, c.clobAPIURL+"/book?token_id="+url.QueryEscape(clobTokenID))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw clobBook
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode order book: %w", err)
+	}
+	book := &OrderBook{}
+	if book.Bids, err = parseLevels(raw.Bids); err != nil {
+		return nil, fmt.Errorf("invalid bid: %w", err)
+	}
+	if book.Asks, err = parseLevels(raw.Asks); err != nil {
+		return nil, fmt.Errorf("invalid ask: %w", err)
+	}
+	return book, nil
+}
+
+func parseLevels(raw []clobLevel) ([]BookLevel, error) {
+	levels := make([]BookLevel, 0, len(raw))
+	for _, l := range raw {
+		price, err := strconv.ParseFloat(l.Price, 64)
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.ParseFloat(l.Size, 64)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, BookLevel{Price: price, Size: size})
+	}
+	return levels, nil
+}
+
+// Midpoint returns the mean of the best bid and best ask, and false when
+// either side is empty.
+func (b *OrderBook) Midpoint() (float64, bool) {
+	if len(b.Bids) == 0 || len(b.Asks) == 0 {
+		return 0, false
+	}
+	bestBid, bestAsk := b.Bids[0].Price, b.Asks[0].Price
+	for _, l := range b.Bids {
+		bestBid = max(bestBid, l.Price)
+	}
+	for _, l := range b.Asks {
+		bestAsk = min(bestAsk, l.Price)
+	}
+	return (bestBid + bestAsk) / 2, true
+}
+
+// Depth returns the dollar value (price × size) resting on both sides within
+// ±band of the midpoint: what it would take to move the price by band. 0 for
+// a one-sided or empty book.
+func (b *OrderBook) Depth(band float64) float64 {
+	mid, ok := b.Midpoint()
+	if !ok {
+		return 0
+	}
+	var depth float64
+	for _, side := range [][]BookLevel{b.Bids, b.Asks} {
+		for _, l := range side {
+			if l.Price >= mid-band && l.Price <= mid+band {
+				depth += l.Price * l.Size
+			}
+		}
+	}
+	return depth
+}
+
+// applyBookDepth replaces each market's Liquidity with its order-book depth
+// within c.depthBand of the midpoint. tokens maps composite IDs to a CLOB
+// token of the market; the two outcome books of a binary market mirror each
+// other, so either token measures the same depth. Markets without a token,
+// or whose book can't be fetched, keep the Gamma liquidity.
+func (c *Client) applyBookDepth(ctx context.Context, markets []models.Market, tokens map[string]string) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, bookFetchConcurrency)
+	var mu sync.Mutex
+	failed := 0
+	for i := range markets {
+		token := tokens[markets[i].ID]
+		if token == "" {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(m *models.Market) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			book, err := c.FetchOrderBook(ctx, token)
+			if err != nil {
+				logger.Debug("Order book for %s unavailable, keeping Gamma liquidity: %v", m.ID, err)
+				mu.Lock()
+				failed++
+				mu.Unlock()
+				return
+			}
+			m.Liquidity = book.Depth(c.depthBand)
+		}(&markets[i])
+	}
+	wg.Wait()
+	if failed > 0 {
+		logger.Warn("Order book depth unavailable for %d markets; kept their Gamma liquidity", failed)
+	}
+}
+
+// clobToken returns the token of outcome i from a market's clobTokenIds JSON
+// string, or "" when there is none.
+func clobToken(clobTokenIDs string, i int) string {
+	var ids []string
+	if err := json.Unmarshal([]byte(clobTokenIDs), &ids); err != nil || i >= len(ids) {
+		return ""
+	}
+	return ids[i]
+}
@@ -0,0 +1,77 @@
+package polymarket
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOrderBook_Depth(t *testing.T) {
+	book := &OrderBook{
+		Bids: []BookLevel{{Price: 0.40, Size: 1000}, {Price: 0.48, Size: 100}, {Price: 0.47, Size: 200}},
+		Asks: []BookLevel{{Price: 0.60, Size: 1000}, {Price: 0.52, Size: 100}, {Price: 0.53, Size: 300}},
+	}
+	mid, ok := book.Midpoint()
+	if !ok || math.Abs(mid-0.50) > 1e-9 {
+		t.Fatalf("Midpoint() = %v, %v; want 0.50", mid, ok)
+	}
+	// ±0.03 of 0.50 takes 0.47–0.53: 0.48×100 + 0.47×200 + 0.52×100 + 0.53×300
+	if got, want := book.Depth(0.03), 48+94+52+159.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Depth(0.03) = %v, want %v", got, want)
+	}
+	if got := (&OrderBook{Bids: book.Bids}).Depth(0.03); got != 0 {
+		t.Errorf("one-sided book: Depth = %v, want 0", got)
+	}
+}
+
+func TestFetchEvents_DepthBand(t *testing.T) {
+	events := []PolymarketEvent{{
+		ID: "e1", Title: "e1", Active: true, Volume24hr: 50000, Liquidity: 99999,
+		Markets: []PolymarketMarket{
+			{ID: "m1", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.5", "0.5"]`, ClobTokenIds: `["tok-yes", "tok-no"]`},
+			{ID: "m2", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.5", "0.5"]`, ClobTokenIds: `["tok-missing", "x"]`},
+			{ID: "m3", Outcomes: `["Yes", "No"]`, OutcomePrices: `["0.5", "0.5"]`},
+		},
+	}}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/events":
+			_ = json.NewEncoder(w).Encode(events)
+		case "/book":
+			if r.URL.Query().Get("token_id") != "tok-yes" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(`{"bids":[{"price":"0.49","size":"100"},{"price":"0.30","size":"5000"}],` +
+				`"asks":[{"price":"0.51","size":"200"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.URL, mockServer.URL, 30*time.Second, ClientConfig{DepthBand: 0.02})
+	markets, err := client.FetchEvents(context.Background(), nil, 0, 0, 0, false, 10)
+	if err != nil {
+		t.Fatalf("FetchEvents failed: %v", err)
+	}
+
+	want := map[string]float64{
+		"e1:m1": 0.49*100 + 0.51*200, // book depth within 0.48–0.52
+		"e1:m2": 99999,               // book unavailable: Gamma liquidity kept
+		"e1:m3": 99999,               // no token
+	}
+	if len(markets) != len(want) {
+		t.Fatalf("got %d markets, want %d", len(markets), len(want))
+	}
+	for _, m := range markets {
+		if math.Abs(m.Liquidity-want[m.ID]) > 1e-9 {
+			t.Errorf("%s: Liquidity = %v, want %v", m.ID, m.Liquidity, want[m.ID])
+		}
+	}
+}
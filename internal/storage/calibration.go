@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+// AddCalibration records a resolved market's forecast. Each market is scored
+// once; a second record for the same market is ignored and reported as not
+// added.
+func (s *Storage) AddCalibration(r models.CalibrationRecord) (bool, error) {
+	res, err := s.db.Exec(`
+		INSERT OR IGNORE INTO calibration
+			(market_id, category, predicted, outcome, lead_time, predicted_at, resolved_at)
+		VALUES (?,?,?,?,?,?,?)`,
+		r.MarketID, r.Category, r.Predicted, r.Outcome,
+		r.LeadTime.Nanoseconds(), r.PredictedAt.UnixNano(), r.ResolvedAt.UnixNano())
+	if err != nil {
+		return false, fmt.Errorf("failed to record calibration for %s: %w", r.MarketID, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// GetCalibration returns the calibration summary of every category with
+// resolved markets, ordered by category.
+func (s *Storage) GetCalibration() ([]models.CalibrationSummary, error) {
+	rows, err := s.reader().Query(`
+		SELECT category, COUNT(*),
+		       AVG((predicted - outcome) * (predicted - outcome)),
+		       AVG(predicted), AVG(outcome)
+		FROM calibration GROUP BY category ORDER BY category`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query calibration: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.CalibrationSummary{}
+	for rows.Next() {
+		var c models.CalibrationSummary
+		if err := rows.Scan(&c.Category, &c.Markets, &c.Brier, &c.MeanPredicted, &c.ResolvedYes); err != nil {
+			return nil, fmt.Errorf("failed to scan calibration: %w", err)
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
+
+// GetSnapshotBefore returns a market's most recent snapshot taken at or
+// before t, or nil when it has none that old.
+func (s *Storage) GetSnapshotBefore(marketID string, t time.Time) (*models.Snapshot, error) {
+	rows, err := s.db.Query(`
+		SELECT `+snapshotCols+`
+		FROM snapshots WHERE market_id = ? AND timestamp <= ?
+		ORDER BY timestamp DESC LIMIT 1`, marketID, t.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshot: %w", err)
+	}
+	defer rows.Close()
+	snapshots, err := scanSnapshots(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 {
+		return nil, nil
+	}
+	return &snapshots[0], nil
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+func TestStorage_Calibration(t *testing.T) {
+	s := newTestStorage(t)
+	resolved := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	records := []models.CalibrationRecord{
+		{MarketID: "a:1", Category: "politics", Predicted: 0.8, Outcome: 1},
+		{MarketID: "a:2", Category: "politics", Predicted: 0.4, Outcome: 0},
+		{MarketID: "b:1", Category: "crypto", Predicted: 0.3, Outcome: 1},
+	}
+	for _, r := range records {
+		r.LeadTime, r.ResolvedAt, r.PredictedAt = 24*time.Hour, resolved, resolved.Add(-24*time.Hour)
+		if added, err := s.AddCalibration(r); err != nil || !added {
+			t.Fatalf("AddCalibration(%s) = %v, %v", r.MarketID, added, err)
+		}
+	}
+	// A market is scored once
+	if added, err := s.AddCalibration(models.CalibrationRecord{MarketID: "a:1", Category: "politics", Predicted: 0}); err != nil || added {
+		t.Errorf("second AddCalibration(a:1) = %v, %v; want ignored", added, err)
+	}
+
+	got, err := s.GetCalibration()
+	if err != nil {
+		t.Fatalf("GetCalibration: %v", err)
+	}
+	if len(got) != 2 || got[0].Category != "crypto" || got[1].Category != "politics" {
+		t.Fatalf("got %+v, want crypto then politics", got)
+	}
+	if c := got[0]; c.Markets != 1 || math.Abs(c.Brier-0.49) > 1e-9 || c.ResolvedYes != 1 {
+		t.Errorf("crypto = %+v, want 1 market, Brier 0.49", c)
+	}
+	// ((0.8-1)² + (0.4-0)²) / 2 = (0.04 + 0.16) / 2
+	if p := got[1]; p.Markets != 2 || math.Abs(p.Brier-0.1) > 1e-9 || math.Abs(p.MeanPredicted-0.6) > 1e-9 || p.ResolvedYes != 0.5 {
+		t.Errorf("politics = %+v, want 2 markets, Brier 0.1", p)
+	}
+}
+
+func TestStorage_GetSnapshotBefore(t *testing.T) {
+	s := newTestStorage(t)
+	now := time.Now()
+	if err := s.AddMarket(testMarket("e:m", "e", "m", now)); err != nil {
+		t.Fatalf("AddMarket: %v", err)
+	}
+	for i, age := range []time.Duration{3 * time.Hour, 2 * time.Hour, time.Hour} {
+		if err := s.AddSnapshot(&models.Snapshot{
+			ID: "snap-" + age.String(), EventID: "e:m", YesProbability: 0.1 * float64(i+1), NoProbability: 1 - 0.1*float64(i+1),
+			Timestamp: now.Add(-age), Source: "test",
+		}); err != nil {
+			t.Fatalf("AddSnapshot: %v", err)
+		}
+	}
+
+	snap, err := s.GetSnapshotBefore("e:m", now.Add(-90*time.Minute))
+	if err != nil || snap == nil {
+		t.Fatalf("GetSnapshotBefore = %v, %v", snap, err)
+	}
+	if snap.ID != "snap-2h0m0s" {
+		t.Errorf("got snapshot %s, want the 2h-old one", snap.ID)
+	}
+	if snap, err := s.GetSnapshotBefore("e:m", now.Add(-4*time.Hour)); err != nil || snap != nil {
+		t.Errorf("GetSnapshotBefore past the history = %v, %v; want nil", snap, err)
+	}
+}
@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+// DumpVersion is the format version written by Export and accepted by Import.
+const DumpVersion = 1
+
+// importBatchSize is the number of records written per transaction by Import.
+const importBatchSize = 500
+
+// Dump is a portable copy of the database contents, used for backup and restore
+// between hosts without copying the SQLite file.
+type Dump struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Markets    []models.Market   `json:"markets"`
+	Snapshots  []models.Snapshot `json:"snapshots"`
+	Changes    []models.Change   `json:"changes"`
+}
+
+// ImportResult counts the records Import wrote and skipped. A record is skipped
+// when it fails validation, its ID already exists, or (for snapshots) its
+// market is not in the database.
+type ImportResult struct {
+	MarketsImported, MarketsSkipped     int
+	SnapshotsImported, SnapshotsSkipped int
+	ChangesImported, ChangesSkipped     int
+}
+
+// Export reads every market, snapshot and change into a Dump.
+func (s *Storage) Export() (*Dump, error) {
+	markets, err := s.GetAllMarkets()
+	if err != nil {
+		return nil, err
+	}
+	d := &Dump{
+		Version:    DumpVersion,
+		ExportedAt: time.Now(),
+		Markets:    make([]models.Market, len(markets)),
+	}
+	for i, m := range markets {
+		d.Markets[i] = *m
+	}
+
+	rows, err := s.db.Query(`
+		SELECT ` + snapshotCols + `
+		FROM snapshots ORDER BY market_id, timestamp ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshots: %w", err)
+	}
+	d.Snapshots, err = scanSnapshots(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.Query(`
+		SELECT id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+		       market_question, magnitude, direction, old_prob, new_prob, time_window,
+		       detected_at, notified, signal_score, components
+		FROM changes ORDER BY detected_at ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changes: %w", err)
+	}
+	d.Changes, err = scanChanges(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	if d.Changes == nil {
+		d.Changes = []models.Change{}
+	}
+	return d, nil
+}
+
+// Import bulk-loads a Dump, validating each record and writing in batched
+// transactions. Existing rows are never overwritten. Markets are loaded first so
+// snapshots can reference them; the market and snapshot caps are applied once
+// at the end.
+func (s *Storage) Import(d *Dump) (ImportResult, error) {
+	var res ImportResult
+	if d.Version != DumpVersion {
+		return res, fmt.Errorf("unsupported dump version %d (want %d)", d.Version, DumpVersion)
+	}
+
+	err := batchInsert(s.db, d.Markets, &res.MarketsImported, &res.MarketsSkipped,
+		func(tx *sql.Tx, m *models.Market) (bool, error) {
+			if m.Validate() != nil {
+				return false, nil
+			}
+			r, err := tx.Exec(`
+				INSERT OR IGNORE INTO markets
+					(id, event_id, market_id, market_question, title, event_url, description,
+					 category, subcategory, yes_prob, no_prob, volume_24hr, volume_1wk, volume_1mo,
+					 liquidity, active, closed, last_updated, created_at)
+				VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+				m.ID, m.EventID, m.MarketID, m.MarketQuestion, m.Title,
+				m.EventURL, m.Description, m.Category, m.Subcategory,
+				s.encodeProb(m.YesProbability), s.encodeProb(m.NoProbability),
+				m.Volume24hr, m.Volume1wk, m.Volume1mo, m.Liquidity,
+				boolToInt(m.Active), boolToInt(m.Closed),
+				m.LastUpdated.UnixNano(), m.CreatedAt.UnixNano(),
+			)
+			return inserted(r, err)
+		})
+	if err != nil {
+		return res, fmt.Errorf("failed to import markets: %w", err)
+	}
+
+	err = batchInsert(s.db, d.Snapshots, &res.SnapshotsImported, &res.SnapshotsSkipped,
+		func(tx *sql.Tx, snap *models.Snapshot) (bool, error) {
+			if snap.Validate() != nil {
+				return false, nil
+			}
+			// Selecting from markets skips snapshots of unknown markets instead of
+			// failing the foreign key check.
+			r, err := tx.Exec(`
+				INSERT OR IGNORE INTO snapshots (id, market_id, yes_prob, no_prob, volume_24hr, liquidity, timestamp, source)
+				SELECT ?,?,?,?,?,?,?,? WHERE EXISTS (SELECT 1 FROM markets WHERE id = ?)`,
+				snap.ID, snap.EventID,
+				s.encodeProb(snap.YesProbability), s.encodeProb(snap.NoProbability),
+				snap.Volume24hr, snap.Liquidity,
+				snap.Timestamp.UnixNano(), snap.Source,
+				snap.EventID,
+			)
+			return inserted(r, err)
+		})
+	if err != nil {
+		return res, fmt.Errorf("failed to import snapshots: %w", err)
+	}
+
+	err = batchInsert(s.db, d.Changes, &res.ChangesImported, &res.ChangesSkipped,
+		func(tx *sql.Tx, c *models.Change) (bool, error) {
+			if c.Validate() != nil {
+				return false, nil
+			}
+			components, err := encodeComponents(c.Components)
+			if err != nil {
+				return false, err
+			}
+			r, err := tx.Exec(`
+				INSERT OR IGNORE INTO changes
+					(id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+					 market_question, magnitude, direction, old_prob, new_prob, time_window,
+					 detected_at, notified, signal_score, components)
+				VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+				c.ID, c.EventID, c.OriginalEventID, c.EventTitle, c.EventURL,
+				c.MarketID, c.MarketQuestion,
+				c.Magnitude, c.Direction, c.OldProbability, c.NewProbability,
+				c.TimeWindow.Nanoseconds(), c.DetectedAt.UnixNano(),
+				boolToInt(c.Notified), c.SignalScore, components,
+			)
+			return inserted(r, err)
+		})
+	if err != nil {
+		return res, fmt.Errorf("failed to import changes: %w", err)
+	}
+
+	if err := s.RotateMarkets(); err != nil {
+		return res, err
+	}
+	if err := s.RotateSnapshots(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// batchInsert calls insert for each record, committing every importBatchSize
+// records. insert reports whether the record was written; unwritten records are
+// counted as skipped.
+func batchInsert[T any](db *sql.DB, records []T, imported, skipped *int, insert func(*sql.Tx, *T) (bool, error)) error {
+	for start := 0; start < len(records); start += importBatchSize {
+		end := min(start+importBatchSize, len(records))
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		ok, notOK := 0, 0
+		for i := start; i < end; i++ {
+			wrote, err := insert(tx, &records[i])
+			if err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+			if wrote {
+				ok++
+			} else {
+				notOK++
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit batch: %w", err)
+		}
+		*imported += ok
+		*skipped += notOK
+	}
+	return nil
+}
+
+// inserted reports whether an INSERT OR IGNORE wrote a row.
+func inserted(r sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := r.RowsAffected()
+	return n > 0, err
+}
+
+// MarketState is everything persisted about one market: its row, its most recent
+// snapshots (the history its volatility and trajectory scores are computed from)
+// and its stored changes.
+type MarketState struct {
+	Market          *models.Market    `json:"market"`
+	SnapshotCount   int               `json:"snapshot_count"`
+	RecentSnapshots []models.Snapshot `json:"recent_snapshots"` // oldest first
+	RecentChanges   []models.Change   `json:"recent_changes"`   // newest first
+}
+
+// GetMarketState reads a market's persisted state, keeping at most limit recent
+// snapshots and changes. It returns an error if the market is not stored.
+func (s *Storage) GetMarketState(id string, limit int) (*MarketState, error) {
+	market, err := s.GetMarket(id)
+	if err != nil {
+		return nil, err
+	}
+	snaps, err := s.GetSnapshots(id)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.GetChangesForMarket(id, limit)
+	if err != nil {
+		return nil, err
+	}
+	st := &MarketState{
+		Market:          market,
+		SnapshotCount:   len(snaps),
+		RecentSnapshots: snaps[max(0, len(snaps)-limit):],
+		RecentChanges:   changes,
+	}
+	if st.RecentSnapshots == nil {
+		st.RecentSnapshots = []models.Snapshot{}
+	}
+	if st.RecentChanges == nil {
+		st.RecentChanges = []models.Change{}
+	}
+	return st, nil
+}
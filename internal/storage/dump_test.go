@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+func TestStorage_ExportImportRoundTrip(t *testing.T) {
+	src := newTestStorage(t)
+	now := time.Now().Truncate(time.Second)
+
+	for _, id := range []string{"e1:m1", "e2:m1"} {
+		if err := src.AddMarket(testMarket(id, id[:2], "m1", now)); err != nil {
+			t.Fatalf("AddMarket: %v", err)
+		}
+		for i := range 3 {
+			snap := &models.Snapshot{
+				ID: id + "-" + string(rune('a'+i)), EventID: id,
+				YesProbability: 0.5, NoProbability: 0.5,
+				Timestamp: now.Add(time.Duration(i-3) * time.Minute), Source: "test",
+			}
+			if err := src.AddSnapshot(snap); err != nil {
+				t.Fatalf("AddSnapshot: %v", err)
+			}
+		}
+	}
+	change := &models.Change{
+		ID: "c1", EventID: "e1:m1", Magnitude: 0.1, Direction: "increase",
+		OldProbability: 0.4, NewProbability: 0.5, TimeWindow: time.Hour, DetectedAt: now,
+	}
+	if err := src.AddChange(change); err != nil {
+		t.Fatalf("AddChange: %v", err)
+	}
+
+	dump, err := src.Export()
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	// Round-trip through JSON as the CLI does.
+	data, err := json.Marshal(dump)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var loaded Dump
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	dst := newTestStorage(t)
+	res, err := dst.Import(&loaded)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	want := ImportResult{MarketsImported: 2, SnapshotsImported: 6, ChangesImported: 1}
+	if res != want {
+		t.Errorf("Import result = %+v, want %+v", res, want)
+	}
+
+	m, err := dst.GetMarket("e1:m1")
+	if err != nil {
+		t.Fatalf("GetMarket: %v", err)
+	}
+	if !m.LastUpdated.Equal(now) || m.YesProbability != 0.75 {
+		t.Errorf("imported market mismatch: %+v", m)
+	}
+	snaps, _ := dst.GetSnapshots("e2:m1")
+	if len(snaps) != 3 {
+		t.Errorf("got %d snapshots for e2:m1, want 3", len(snaps))
+	}
+	changes, _ := dst.GetTopChanges(10)
+	if len(changes) != 1 || changes[0].ID != "c1" {
+		t.Errorf("imported changes = %+v", changes)
+	}
+
+	// Importing again skips every record rather than duplicating or failing.
+	res, err = dst.Import(&loaded)
+	if err != nil {
+		t.Fatalf("re-Import: %v", err)
+	}
+	want = ImportResult{MarketsSkipped: 2, SnapshotsSkipped: 6, ChangesSkipped: 1}
+	if res != want {
+		t.Errorf("re-Import result = %+v, want %+v", res, want)
+	}
+}
+
+func TestStorage_ImportSkipsInvalidRecords(t *testing.T) {
+	s := newTestStorage(t)
+	now := time.Now()
+
+	bad := *testMarket("bad:m1", "bad", "m1", now)
+	bad.YesProbability = 1.5
+	dump := &Dump{
+		Version: DumpVersion,
+		Markets: []models.Market{*testMarket("ok:m1", "ok", "m1", now), bad},
+		Snapshots: []models.Snapshot{
+			{ID: "s1", EventID: "ok:m1", YesProbability: 0.5, NoProbability: 0.5, Timestamp: now, Source: "test"},
+			{ID: "s2", EventID: "missing:m1", YesProbability: 0.5, NoProbability: 0.5, Timestamp: now, Source: "test"},
+			{ID: "", EventID: "ok:m1", YesProbability: 0.5, NoProbability: 0.5, Timestamp: now, Source: "test"},
+		},
+	}
+
+	res, err := s.Import(dump)
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	want := ImportResult{MarketsImported: 1, MarketsSkipped: 1, SnapshotsImported: 1, SnapshotsSkipped: 2}
+	if res != want {
+		t.Errorf("Import result = %+v, want %+v", res, want)
+	}
+}
+
+func TestStorage_ImportRejectsUnknownVersion(t *testing.T) {
+	s := newTestStorage(t)
+	if _, err := s.Import(&Dump{Version: DumpVersion + 1}); err == nil {
+		t.Error("expected error for unsupported dump version")
+	}
+}
+
+func TestStorage_GetMarketState(t *testing.T) {
+	s := newTestStorage(t)
+	now := time.Now().Truncate(time.Second)
+	if err := s.AddMarket(testMarket("e1:m1", "e1", "m1", now)); err != nil {
+		t.Fatalf("AddMarket: %v", err)
+	}
+	for i := range 5 {
+		snap := &models.Snapshot{
+			ID: "s" + string(rune('0'+i)), EventID: "e1:m1", YesProbability: 0.5, NoProbability: 0.5,
+			Timestamp: now.Add(time.Duration(i-5) * time.Minute), Source: "test",
+		}
+		if err := s.AddSnapshot(snap); err != nil {
+			t.Fatalf("AddSnapshot: %v", err)
+		}
+	}
+
+	st, err := s.GetMarketState("e1:m1", 3)
+	if err != nil {
+		t.Fatalf("GetMarketState: %v", err)
+	}
+	if st.Market.ID != "e1:m1" || st.SnapshotCount != 5 || len(st.RecentSnapshots) != 3 {
+		t.Errorf("state = market %s, %d snapshots (%d recent); want e1:m1, 5 (3 recent)",
+			st.Market.ID, st.SnapshotCount, len(st.RecentSnapshots))
+	}
+	if st.RecentSnapshots[2].ID != "s4" {
+		t.Errorf("recent snapshots should end with the newest, got %s", st.RecentSnapshots[2].ID)
+	}
+	if st.RecentChanges == nil {
+		t.Error("RecentChanges should be empty, not nil")
+	}
+
+	if _, err := s.GetMarketState("missing:m1", 3); err == nil {
+		t.Error("expected error for unknown market")
+	}
+}
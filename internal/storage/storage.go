@@ -4,6 +4,8 @@ package storage
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,13 +18,39 @@ import (
 // Storage wraps a SQLite database for all persistence operations.
 type Storage struct {
 	db                   *sql.DB
+	readDB               *sql.DB // read-only pool for query APIs; nil = reads share db
 	maxMarkets           int
 	maxSnapshotsPerEvent int
+	basisPoints          bool
+	maxMarketAge         time.Duration
+}
+
+// Probability encodings accepted by Config.ProbabilityEncoding.
+const (
+	EncodingReal        = "real"         // full float64 precision (default)
+	EncodingBasisPoints = "basis_points" // quantized to 1bp so equal prices compare exactly
+)
+
+// Config holds optional storage configuration
+type Config struct {
+	// ProbabilityEncoding selects how probabilities are persisted. With
+	// EncodingBasisPoints, market and snapshot probabilities are rounded to the
+	// nearest basis point on write. Values stay in the REAL columns (as bp/10000)
+	// so databases remain readable in either mode.
+	ProbabilityEncoding string
+	// MaxMarketAge makes RotateMarkets also delete markets not updated within
+	// this duration, however far below the count cap. 0 = no age limit.
+	MaxMarketAge time.Duration
+	// ReadConnections opens a second, read-only handle with this many
+	// connections for the query APIs (alert history, stored changes), so they
+	// read alongside the single writer under WAL instead of queueing behind
+	// the monitoring loop's writes. 0 = all queries share the writer.
+	ReadConnections int
 }
 
 // New opens (or creates) the SQLite database at dbPath.
 // If dbPath is empty, defaults to $TMPDIR/polyoracle/data.db.
-func New(maxMarkets, maxSnapshotsPerEvent int, dbPath string) (*Storage, error) {
+func New(maxMarkets, maxSnapshotsPerEvent int, dbPath string, cfg ...Config) (*Storage, error) {
 	if dbPath == "" {
 		dbPath = filepath.Join(os.TempDir(), "polyoracle", "data.db")
 	}
@@ -35,6 +63,12 @@ func New(maxMarkets, maxSnapshotsPerEvent int, dbPath string) (*Storage, error)
 	}
 	// Single writer connection; WAL lets readers not block the writer.
 	db.SetMaxOpenConns(1)
+	// Incremental auto-vacuum lets Vacuum reclaim free pages without rewriting the
+	// whole file. SQLite only honors this on a database without tables yet, so
+	// older databases keep full VACUUM.
+	if _, err := db.Exec(`PRAGMA auto_vacuum=INCREMENTAL`); err != nil {
+		return nil, fmt.Errorf("failed to set auto_vacuum: %w", err)
+	}
 	if _, err := db.Exec(`PRAGMA journal_mode=WAL`); err != nil {
 		return nil, fmt.Errorf("failed to set WAL mode: %w", err)
 	}
@@ -42,15 +76,47 @@ func New(maxMarkets, maxSnapshotsPerEvent int, dbPath string) (*Storage, error)
 		return nil, fmt.Errorf("failed to enable foreign keys: %w", err)
 	}
 	s := &Storage{db: db, maxMarkets: maxMarkets, maxSnapshotsPerEvent: maxSnapshotsPerEvent}
+	if len(cfg) > 0 {
+		s.basisPoints = cfg[0].ProbabilityEncoding == EncodingBasisPoints
+		s.maxMarketAge = cfg[0].MaxMarketAge
+	}
 	if err := s.createTables(); err != nil {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
+	// An in-memory database is private to its connection, so it can't be shared.
+	if len(cfg) > 0 && cfg[0].ReadConnections > 0 && dbPath != ":memory:" {
+		readDB, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+		if err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to open read-only database: %w", err)
+		}
+		readDB.SetMaxOpenConns(cfg[0].ReadConnections)
+		if err := readDB.Ping(); err != nil {
+			_ = readDB.Close()
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to open read-only database: %w", err)
+		}
+		s.readDB = readDB
+	}
 	return s, nil
 }
 
-// Close closes the underlying database connection.
+// Close closes the underlying database connections.
 func (s *Storage) Close() error {
-	return s.db.Close()
+	var readErr error
+	if s.readDB != nil {
+		readErr = s.readDB.Close()
+	}
+	return errors.Join(readErr, s.db.Close())
+}
+
+// reader returns the handle for query APIs: the read-only pool when one is
+// open, otherwise the writer.
+func (s *Storage) reader() *sql.DB {
+	if s.readDB != nil {
+		return s.readDB
+	}
+	return s.db
 }
 
 // Save is a no-op: SQLite writes are immediate.
@@ -83,12 +149,14 @@ func (s *Storage) createTables() error {
 			created_at      INTEGER NOT NULL
 		)`,
 		`CREATE TABLE IF NOT EXISTS snapshots (
-			id        TEXT PRIMARY KEY,
-			market_id TEXT NOT NULL REFERENCES markets(id) ON DELETE CASCADE,
-			yes_prob  REAL NOT NULL,
-			no_prob   REAL NOT NULL,
-			timestamp INTEGER NOT NULL,
-			source    TEXT NOT NULL
+			id          TEXT PRIMARY KEY,
+			market_id   TEXT NOT NULL REFERENCES markets(id) ON DELETE CASCADE,
+			yes_prob    REAL NOT NULL,
+			no_prob     REAL NOT NULL,
+			volume_24hr REAL,
+			liquidity   REAL,
+			timestamp   INTEGER NOT NULL,
+			source      TEXT NOT NULL
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_snapshots_market_ts ON snapshots(market_id, timestamp)`,
 		`CREATE TABLE IF NOT EXISTS changes (
@@ -106,15 +174,91 @@ func (s *Storage) createTables() error {
 			time_window          INTEGER NOT NULL,
 			detected_at          INTEGER NOT NULL,
 			notified             INTEGER DEFAULT 0,
-			signal_score         REAL DEFAULT 0
+			signal_score         REAL DEFAULT 0,
+			components           TEXT
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_changes_detected_at ON changes(detected_at)`,
+		`CREATE TABLE IF NOT EXISTS alerts (
+			id                   TEXT PRIMARY KEY,
+			market_id            TEXT NOT NULL,
+			original_event_id    TEXT,
+			event_title          TEXT,
+			event_url            TEXT,
+			polymarket_market_id TEXT,
+			market_question      TEXT,
+			magnitude            REAL NOT NULL,
+			direction            TEXT NOT NULL,
+			old_prob             REAL NOT NULL,
+			new_prob             REAL NOT NULL,
+			time_window          INTEGER NOT NULL,
+			detected_at          INTEGER NOT NULL,
+			notified             INTEGER DEFAULT 1,
+			signal_score         REAL DEFAULT 0,
+			components           TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_alerts_market_detected ON alerts(market_id, detected_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_alerts_detected_at ON alerts(detected_at)`,
+		`CREATE TABLE IF NOT EXISTS alert_budget (
+			market_id    TEXT PRIMARY KEY,
+			window_start INTEGER NOT NULL,
+			count        INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS calibration (
+			market_id    TEXT PRIMARY KEY,
+			category     TEXT NOT NULL,
+			predicted    REAL NOT NULL,
+			outcome      INTEGER NOT NULL,
+			lead_time    INTEGER NOT NULL,
+			predicted_at INTEGER NOT NULL,
+			resolved_at  INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS announcements (
+			market_id    TEXT PRIMARY KEY,
+			announced_at INTEGER NOT NULL
+		)`,
 	}
 	for _, stmt := range stmts {
 		if _, err := s.db.Exec(stmt); err != nil {
 			return err
 		}
 	}
+	// Columns added after the initial schema; CREATE TABLE IF NOT EXISTS leaves
+	// existing databases without them.
+	for _, c := range []struct{ table, column, typ string }{
+		{"changes", "components", "TEXT"},
+		{"snapshots", "volume_24hr", "REAL"},
+		{"snapshots", "liquidity", "REAL"},
+	} {
+		if err := s.addColumnIfMissing(c.table, c.column, c.typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumnIfMissing adds column (with SQL type typ) to table unless it exists.
+func (s *Storage) addColumnIfMissing(table, column, typ string) error {
+	rows, err := s.db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s columns: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+	if _, err := s.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, typ)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
 	return nil
 }
 
@@ -138,7 +282,7 @@ func (s *Storage) AddMarket(market *models.Market) error {
 		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
 		market.ID, market.EventID, market.MarketID, market.MarketQuestion, market.Title,
 		market.EventURL, market.Description, market.Category, market.Subcategory,
-		market.YesProbability, market.NoProbability,
+		s.encodeProb(market.YesProbability), s.encodeProb(market.NoProbability),
 		market.Volume24hr, market.Volume1wk, market.Volume1mo, market.Liquidity,
 		boolToInt(market.Active), boolToInt(market.Closed),
 		market.LastUpdated.UnixNano(), market.CreatedAt.UnixNano(),
@@ -202,7 +346,7 @@ func (s *Storage) UpdateMarket(market *models.Market) error {
 		WHERE id=?`,
 		market.EventID, market.MarketID, market.MarketQuestion, market.Title,
 		market.EventURL, market.Description, market.Category, market.Subcategory,
-		market.YesProbability, market.NoProbability,
+		s.encodeProb(market.YesProbability), s.encodeProb(market.NoProbability),
 		market.Volume24hr, market.Volume1wk, market.Volume1mo, market.Liquidity,
 		boolToInt(market.Active), boolToInt(market.Closed),
 		market.LastUpdated.UnixNano(), market.CreatedAt.UnixNano(),
@@ -232,10 +376,11 @@ func (s *Storage) AddSnapshot(snapshot *models.Snapshot) error {
 		return fmt.Errorf("market not found: %s", snapshot.EventID)
 	}
 	_, err := s.db.Exec(`
-		INSERT INTO snapshots (id, market_id, yes_prob, no_prob, timestamp, source)
-		VALUES (?,?,?,?,?,?)`,
+		INSERT INTO snapshots (id, market_id, yes_prob, no_prob, volume_24hr, liquidity, timestamp, source)
+		VALUES (?,?,?,?,?,?,?,?)`,
 		snapshot.ID, snapshot.EventID,
-		snapshot.YesProbability, snapshot.NoProbability,
+		s.encodeProb(snapshot.YesProbability), s.encodeProb(snapshot.NoProbability),
+		snapshot.Volume24hr, snapshot.Liquidity,
 		snapshot.Timestamp.UnixNano(), snapshot.Source,
 	)
 	if err != nil {
@@ -246,7 +391,7 @@ func (s *Storage) AddSnapshot(snapshot *models.Snapshot) error {
 
 func (s *Storage) GetSnapshots(marketID string) ([]models.Snapshot, error) {
 	rows, err := s.db.Query(`
-		SELECT id, market_id, yes_prob, no_prob, timestamp, source
+		SELECT `+snapshotCols+`
 		FROM snapshots WHERE market_id = ? ORDER BY timestamp ASC`, marketID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query snapshots: %w", err)
@@ -258,7 +403,7 @@ func (s *Storage) GetSnapshots(marketID string) ([]models.Snapshot, error) {
 func (s *Storage) GetSnapshotsInWindow(marketID string, window time.Duration) ([]models.Snapshot, error) {
 	cutoff := time.Now().Add(-window).UnixNano()
 	rows, err := s.db.Query(`
-		SELECT id, market_id, yes_prob, no_prob, timestamp, source
+		SELECT `+snapshotCols+`
 		FROM snapshots WHERE market_id = ? AND timestamp >= ? ORDER BY timestamp ASC`,
 		marketID, cutoff)
 	if err != nil {
@@ -268,23 +413,41 @@ func (s *Storage) GetSnapshotsInWindow(marketID string, window time.Duration) ([
 	return scanSnapshots(rows)
 }
 
+// GetSnapshotsSince returns a market's snapshots taken at or after since,
+// oldest first: its recorded price, volume and liquidity path.
+func (s *Storage) GetSnapshotsSince(marketID string, since time.Time) ([]models.Snapshot, error) {
+	rows, err := s.reader().Query(`
+		SELECT `+snapshotCols+`
+		FROM snapshots WHERE market_id = ? AND timestamp >= ? ORDER BY timestamp ASC`,
+		marketID, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshots since %v: %w", since, err)
+	}
+	defer rows.Close()
+	return scanSnapshots(rows)
+}
+
 // --- Changes ---
 
 func (s *Storage) AddChange(change *models.Change) error {
 	if err := change.Validate(); err != nil {
 		return fmt.Errorf("invalid change: %w", err)
 	}
-	_, err := s.db.Exec(`
+	components, err := encodeComponents(change.Components)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
 		INSERT INTO changes
 			(id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
 			 market_question, magnitude, direction, old_prob, new_prob, time_window,
-			 detected_at, notified, signal_score)
-		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+			 detected_at, notified, signal_score, components)
+		VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
 		change.ID, change.EventID, change.OriginalEventID, change.EventTitle, change.EventURL,
 		change.MarketID, change.MarketQuestion,
 		change.Magnitude, change.Direction, change.OldProbability, change.NewProbability,
 		change.TimeWindow.Nanoseconds(), change.DetectedAt.UnixNano(),
-		boolToInt(change.Notified), change.SignalScore,
+		boolToInt(change.Notified), change.SignalScore, components,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert change: %w", err)
@@ -292,11 +455,34 @@ func (s *Storage) AddChange(change *models.Change) error {
 	return nil
 }
 
+// UpdateChangeScores records the signal score and score components of already
+// stored changes (matched by ID). Changes are stored before scoring, so this
+// makes alerted changes self-describing once ScoreAndRank has run.
+func (s *Storage) UpdateChangeScores(changes []models.Change) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, c := range changes {
+		components, err := encodeComponents(c.Components)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE changes SET signal_score = ?, components = ? WHERE id = ?`,
+			c.SignalScore, components, c.ID); err != nil {
+			return fmt.Errorf("failed to update change %s: %w", c.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
 func (s *Storage) GetTopChanges(k int) ([]models.Change, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.reader().Query(`
 		SELECT id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
 		       market_question, magnitude, direction, old_prob, new_prob, time_window,
-		       detected_at, notified, signal_score
+		       detected_at, notified, signal_score, components
 		FROM changes ORDER BY magnitude DESC LIMIT ?`, k)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query changes: %w", err)
@@ -305,6 +491,21 @@ func (s *Storage) GetTopChanges(k int) ([]models.Change, error) {
 	return scanChanges(rows)
 }
 
+// GetChangesForMarket returns up to limit stored changes for a market (composite
+// ID), most recent first.
+func (s *Storage) GetChangesForMarket(marketID string, limit int) ([]models.Change, error) {
+	rows, err := s.reader().Query(`
+		SELECT id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+		       market_question, magnitude, direction, old_prob, new_prob, time_window,
+		       detected_at, notified, signal_score, components
+		FROM changes WHERE market_id = ? ORDER BY detected_at DESC LIMIT ?`, marketID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changes: %w", err)
+	}
+	defer rows.Close()
+	return scanChanges(rows)
+}
+
 func (s *Storage) ClearChanges() error {
 	if _, err := s.db.Exec(`DELETE FROM changes`); err != nil {
 		return fmt.Errorf("failed to clear changes: %w", err)
@@ -312,6 +513,192 @@ func (s *Storage) ClearChanges() error {
 	return nil
 }
 
+// --- Alert history ---
+
+// AddAlerts records changes that were sent as notifications. Unlike the
+// changes table, which only holds the latest cycle, alerts are kept until
+// their market is rotated out.
+func (s *Storage) AddAlerts(changes []models.Change) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, c := range changes {
+		components, err := encodeComponents(c.Components)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			INSERT OR REPLACE INTO alerts
+				(id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+				 market_question, magnitude, direction, old_prob, new_prob, time_window,
+				 detected_at, notified, signal_score, components)
+			VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,1,?,?)`,
+			c.ID, c.EventID, c.OriginalEventID, c.EventTitle, c.EventURL,
+			c.MarketID, c.MarketQuestion,
+			c.Magnitude, c.Direction, c.OldProbability, c.NewProbability,
+			c.TimeWindow.Nanoseconds(), c.DetectedAt.UnixNano(),
+			c.SignalScore, components,
+		); err != nil {
+			return fmt.Errorf("failed to record alert %s: %w", c.ID, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAlertsForMarket returns up to limit sent alerts for a market, most recent
+// first. marketID may be the composite ID or the bare Polymarket market ID.
+func (s *Storage) GetAlertsForMarket(marketID string, limit int) ([]models.Change, error) {
+	rows, err := s.reader().Query(`
+		SELECT id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+		       market_question, magnitude, direction, old_prob, new_prob, time_window,
+		       detected_at, notified, signal_score, components
+		FROM alerts WHERE market_id = ? OR polymarket_market_id = ?
+		ORDER BY detected_at DESC LIMIT ?`, marketID, marketID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+	return scanChanges(rows)
+}
+
+// GetAlerts returns up to limit sent alerts across all markets, most recent first.
+func (s *Storage) GetAlerts(limit int) ([]models.Change, error) {
+	rows, err := s.reader().Query(`
+		SELECT id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+		       market_question, magnitude, direction, old_prob, new_prob, time_window,
+		       detected_at, notified, signal_score, components
+		FROM alerts ORDER BY detected_at DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+	return scanChanges(rows)
+}
+
+// GetAlertsSince returns every sent alert detected at or after since, most
+// recent first, for dashboards and audits. Never nil.
+func (s *Storage) GetAlertsSince(since time.Time) ([]models.Change, error) {
+	rows, err := s.reader().Query(`
+		SELECT id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+		       market_question, magnitude, direction, old_prob, new_prob, time_window,
+		       detected_at, notified, signal_score, components
+		FROM alerts WHERE detected_at >= ? ORDER BY detected_at DESC`, since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+	return scanChanges(rows)
+}
+
+// GetTopAlerts returns up to limit sent alerts detected at or after since,
+// highest signal score first.
+func (s *Storage) GetTopAlerts(since time.Time, limit int) ([]models.Change, error) {
+	rows, err := s.reader().Query(`
+		SELECT id, market_id, original_event_id, event_title, event_url, polymarket_market_id,
+		       market_question, magnitude, direction, old_prob, new_prob, time_window,
+		       detected_at, notified, signal_score, components
+		FROM alerts WHERE detected_at >= ?
+		ORDER BY signal_score DESC, detected_at DESC LIMIT ?`, since.UnixNano(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top alerts: %w", err)
+	}
+	defer rows.Close()
+	return scanChanges(rows)
+}
+
+// CountAlertsSince returns how many alerts were detected at or after since,
+// a cheap check before fetching them with GetAlertsSince.
+func (s *Storage) CountAlertsSince(since time.Time) (int, error) {
+	var n int
+	if err := s.reader().QueryRow(`SELECT COUNT(*) FROM alerts WHERE detected_at >= ?`, since.UnixNano()).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count alerts: %w", err)
+	}
+	return n, nil
+}
+
+// --- Alert budget ---
+
+// IncrementAlertCounts adds one sent alert to each market's count for the
+// window starting at windowStart. A count recorded for an earlier window is
+// reset first.
+func (s *Storage) IncrementAlertCounts(marketIDs []string, windowStart time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, id := range marketIDs {
+		if _, err := tx.Exec(`
+			INSERT INTO alert_budget (market_id, window_start, count) VALUES (?, ?, 1)
+			ON CONFLICT(market_id) DO UPDATE SET
+				count = CASE WHEN window_start = excluded.window_start THEN count + 1 ELSE 1 END,
+				window_start = excluded.window_start`,
+			id, windowStart.UnixNano()); err != nil {
+			return fmt.Errorf("failed to count alert for %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetAlertCount returns how many alerts were sent for a market in the window
+// starting at windowStart (0 if none, or only in an earlier window).
+func (s *Storage) GetAlertCount(marketID string, windowStart time.Time) (int, error) {
+	var count int
+	err := s.db.QueryRow(`SELECT count FROM alert_budget WHERE market_id = ? AND window_start = ?`,
+		marketID, windowStart.UnixNano()).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to query alert count: %w", err)
+	}
+	return count, nil
+}
+
+// --- New market announcements ---
+
+// announcementRetention is how long an announced market is remembered. It
+// outlives the market's own rotation, so a market rotated out and fetched again
+// is not announced twice.
+const announcementRetention = 90 * 24 * time.Hour
+
+// MarkAnnounced records that markets were announced as new at at. Markets
+// already recorded keep their first announcement time.
+func (s *Storage) MarkAnnounced(marketIDs []string, at time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, id := range marketIDs {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO announcements (market_id, announced_at) VALUES (?, ?)`,
+			id, at.UnixNano()); err != nil {
+			return fmt.Errorf("failed to record announcement of %s: %w", id, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// Announced returns which of marketIDs were already announced as new.
+func (s *Storage) Announced(marketIDs []string) (map[string]bool, error) {
+	announced := make(map[string]bool)
+	for _, id := range marketIDs {
+		var n int
+		if err := s.reader().QueryRow(`SELECT COUNT(*) FROM announcements WHERE market_id = ?`, id).Scan(&n); err != nil {
+			return nil, fmt.Errorf("failed to query announcement of %s: %w", id, err)
+		}
+		if n > 0 {
+			announced[id] = true
+		}
+	}
+	return announced, nil
+}
+
 // --- Rotation ---
 
 // RotateSnapshots keeps at most maxSnapshotsPerEvent newest snapshots per market,
@@ -350,21 +737,104 @@ func (s *Storage) RotateSnapshots() error {
 	return nil
 }
 
-// RotateMarkets keeps at most maxMarkets newest markets (by last_updated),
-// cascading delete removes their snapshots.
+// RotateMarkets keeps at most maxMarkets newest markets (by last_updated) and,
+// with a MaxMarketAge, drops markets not updated within it. Cascading delete
+// removes their snapshots; their stored changes, alert history and
+// alert-budget counts are removed too. New market announcements are kept for
+// announcementRetention regardless.
 func (s *Storage) RotateMarkets() error {
-	_, err := s.db.Exec(`
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.Exec(`
 		DELETE FROM markets WHERE id NOT IN (
 			SELECT id FROM markets ORDER BY last_updated DESC LIMIT ?
-		)`, s.maxMarkets)
-	if err != nil {
+		)`, s.maxMarkets); err != nil {
 		return fmt.Errorf("failed to rotate markets: %w", err)
 	}
-	return nil
+	if s.maxMarketAge > 0 {
+		cutoff := time.Now().Add(-s.maxMarketAge).UnixNano()
+		if _, err := tx.Exec(`DELETE FROM markets WHERE last_updated < ?`, cutoff); err != nil {
+			return fmt.Errorf("failed to expire old markets: %w", err)
+		}
+	}
+	for _, table := range []string{"changes", "alerts", "alert_budget"} {
+		if _, err := tx.Exec(`DELETE FROM ` + table + ` WHERE market_id NOT IN (SELECT id FROM markets)`); err != nil {
+			return fmt.Errorf("failed to prune %s of rotated markets: %w", table, err)
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM announcements WHERE announced_at < ?`,
+		time.Now().Add(-announcementRetention).UnixNano()); err != nil {
+		return fmt.Errorf("failed to expire announcements: %w", err)
+	}
+	return tx.Commit()
+}
+
+// --- Maintenance ---
+
+// autoVacuumIncremental is the PRAGMA auto_vacuum value for INCREMENTAL mode.
+const autoVacuumIncremental = 2
+
+// Vacuum returns free pages left by rotation and pruning to the filesystem and
+// reports the database size in bytes before and after. Databases created in
+// incremental auto-vacuum mode use PRAGMA incremental_vacuum; others fall back to
+// a full VACUUM, which rewrites the file and needs exclusive access, so callers
+// must not run it concurrently with a monitoring cycle.
+func (s *Storage) Vacuum() (before, after int64, err error) {
+	before, err = s.size()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var mode int
+	if err := s.db.QueryRow(`PRAGMA auto_vacuum`).Scan(&mode); err != nil {
+		return 0, 0, fmt.Errorf("failed to read auto_vacuum mode: %w", err)
+	}
+	if mode == autoVacuumIncremental {
+		_, err = s.db.Exec(`PRAGMA incremental_vacuum`)
+	} else {
+		_, err = s.db.Exec(`VACUUM`)
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	// Fold the WAL back into the main file so the reclaimed space shows on disk.
+	if _, err := s.db.Exec(`PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return 0, 0, fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+
+	after, err = s.size()
+	if err != nil {
+		return 0, 0, err
+	}
+	return before, after, nil
+}
+
+// size returns the database size in bytes (page_count × page_size).
+func (s *Storage) size() (int64, error) {
+	var pages, pageSize int64
+	if err := s.db.QueryRow(`PRAGMA page_count`).Scan(&pages); err != nil {
+		return 0, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := s.db.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read page size: %w", err)
+	}
+	return pages * pageSize, nil
 }
 
 // --- Helpers ---
 
+// encodeProb applies the configured probability encoding before a write.
+func (s *Storage) encodeProb(p float64) float64 {
+	if s.basisPoints {
+		return models.FromBasisPoints(models.ToBasisPoints(p))
+	}
+	return p
+}
+
 const marketCols = `id, event_id, market_id, market_question, title, event_url, description,
 	category, subcategory, yes_prob, no_prob, volume_24hr, volume_1wk, volume_1mo,
 	liquidity, active, closed, last_updated, created_at`
@@ -390,12 +860,16 @@ func scanMarket(scan func(...any) error) (*models.Market, error) {
 	return &m, nil
 }
 
+// snapshotCols are the columns scanSnapshots reads. Volume and liquidity are
+// NULL in rows written before they were recorded.
+const snapshotCols = `id, market_id, yes_prob, no_prob, COALESCE(volume_24hr, 0), COALESCE(liquidity, 0), timestamp, source`
+
 func scanSnapshots(rows *sql.Rows) ([]models.Snapshot, error) {
 	var result []models.Snapshot
 	for rows.Next() {
 		var s models.Snapshot
 		var tsNano int64
-		if err := rows.Scan(&s.ID, &s.EventID, &s.YesProbability, &s.NoProbability, &tsNano, &s.Source); err != nil {
+		if err := rows.Scan(&s.ID, &s.EventID, &s.YesProbability, &s.NoProbability, &s.Volume24hr, &s.Liquidity, &tsNano, &s.Source); err != nil {
 			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
 		}
 		s.Timestamp = time.Unix(0, tsNano)
@@ -413,23 +887,46 @@ func scanChanges(rows *sql.Rows) ([]models.Change, error) {
 		var c models.Change
 		var detectedAtNano, timeWindowNano int64
 		var notified int
+		var components sql.NullString
 		err := rows.Scan(
 			&c.ID, &c.EventID, &c.OriginalEventID, &c.EventTitle, &c.EventURL,
 			&c.MarketID, &c.MarketQuestion,
 			&c.Magnitude, &c.Direction, &c.OldProbability, &c.NewProbability,
-			&timeWindowNano, &detectedAtNano, &notified, &c.SignalScore,
+			&timeWindowNano, &detectedAtNano, &notified, &c.SignalScore, &components,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan change: %w", err)
 		}
+		if components.Valid && components.String != "" {
+			c.Components = &models.ScoreComponents{}
+			if err := json.Unmarshal([]byte(components.String), c.Components); err != nil {
+				return nil, fmt.Errorf("failed to decode components of change %s: %w", c.ID, err)
+			}
+		}
 		c.TimeWindow = time.Duration(timeWindowNano)
 		c.DetectedAt = time.Unix(0, detectedAtNano)
 		c.Notified = notified != 0
 		result = append(result, c)
 	}
+	if result == nil {
+		result = []models.Change{}
+	}
 	return result, rows.Err()
 }
 
+// encodeComponents returns the JSON stored in changes.components; nil for an
+// unscored change.
+func encodeComponents(c *models.ScoreComponents) (any, error) {
+	if c == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode score components: %w", err)
+	}
+	return string(data), nil
+}
+
 func boolToInt(b bool) int {
 	if b {
 		return 1
@@ -1,7 +1,11 @@
 package storage
 
 import (
+	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -170,6 +174,40 @@ func TestStorage_GetSnapshotsInWindow(t *testing.T) {
 	}
 }
 
+func TestStorage_GetSnapshotsSince(t *testing.T) {
+	s := newTestStorage(t)
+	now := time.Now()
+	if err := s.AddMarket(testMarket("e:m", "e", "m", now)); err != nil {
+		t.Fatalf("AddMarket: %v", err)
+	}
+	for i, d := range []time.Duration{-3 * time.Hour, -2 * time.Hour, -time.Hour} {
+		snap := &models.Snapshot{
+			ID:             fmt.Sprintf("s%d", i),
+			EventID:        "e:m",
+			YesProbability: 0.5,
+			NoProbability:  0.5,
+			Volume24hr:     float64(1000 * (i + 1)),
+			Liquidity:      float64(500 * (i + 1)),
+			Timestamp:      now.Add(d),
+			Source:         "test",
+		}
+		if err := s.AddSnapshot(snap); err != nil {
+			t.Fatalf("AddSnapshot: %v", err)
+		}
+	}
+
+	snaps, err := s.GetSnapshotsSince("e:m", now.Add(-2*time.Hour))
+	if err != nil {
+		t.Fatalf("GetSnapshotsSince: %v", err)
+	}
+	if len(snaps) != 2 || snaps[0].ID != "s1" || snaps[1].ID != "s2" {
+		t.Fatalf("got %+v, want s1 then s2", snaps)
+	}
+	if snaps[1].Volume24hr != 3000 || snaps[1].Liquidity != 1500 {
+		t.Errorf("volume/liquidity = %v/%v, want 3000/1500", snaps[1].Volume24hr, snaps[1].Liquidity)
+	}
+}
+
 func TestStorage_RotateSnapshots(t *testing.T) {
 	s, err := New(100, 3, ":memory:")
 	if err != nil {
@@ -375,6 +413,104 @@ func TestStorage_ClearChanges(t *testing.T) {
 	}
 }
 
+func TestStorage_UpdateChangeScores(t *testing.T) {
+	s := newTestStorage(t)
+	c := &models.Change{
+		ID: "c1", EventID: "e1", EventTitle: "T", Magnitude: 0.10,
+		Direction: "increase", OldProbability: 0.60, NewProbability: 0.70,
+		TimeWindow: time.Hour, DetectedAt: time.Now(),
+	}
+	if err := s.AddChange(c); err != nil {
+		t.Fatalf("AddChange: %v", err)
+	}
+
+	// Stored before scoring: no components yet.
+	got, _ := s.GetTopChanges(1)
+	if got[0].Components != nil {
+		t.Fatalf("unscored change has components: %+v", got[0].Components)
+	}
+
+	c.SignalScore = 0.042
+	c.Components = &models.ScoreComponents{
+		KL: 0.021, VolumeWeight: 1.0, SNR: 2.0, TC: 1.0, Sigma: 0.05,
+		HistorySnapshots: 40, WindowSnapshots: 5, Volume24hr: 25000, VolumeRef: 25000,
+		Liquidity: 120000, Threshold: 0.0125,
+	}
+	if err := s.UpdateChangeScores([]models.Change{*c}); err != nil {
+		t.Fatalf("UpdateChangeScores: %v", err)
+	}
+	got, _ = s.GetTopChanges(1)
+	if got[0].SignalScore != 0.042 || got[0].Components == nil || *got[0].Components != *c.Components {
+		t.Errorf("stored score = %v, components = %+v; want %v, %+v",
+			got[0].SignalScore, got[0].Components, c.SignalScore, c.Components)
+	}
+}
+
+func TestNew_AddsComponentsColumnToExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// changes table as created before the components column existed
+	_, err = db.Exec(`CREATE TABLE changes (
+		id TEXT PRIMARY KEY, market_id TEXT NOT NULL, original_event_id TEXT, event_title TEXT,
+		event_url TEXT, polymarket_market_id TEXT, market_question TEXT, magnitude REAL NOT NULL,
+		direction TEXT NOT NULL, old_prob REAL NOT NULL, new_prob REAL NOT NULL,
+		time_window INTEGER NOT NULL, detected_at INTEGER NOT NULL, notified INTEGER DEFAULT 0,
+		signal_score REAL DEFAULT 0)`)
+	if err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	_ = db.Close()
+
+	s, err := New(100, 50, path)
+	if err != nil {
+		t.Fatalf("New on legacy database: %v", err)
+	}
+	defer s.Close()
+	c := &models.Change{
+		ID: "c1", EventID: "e1", Magnitude: 0.10, Direction: "increase",
+		OldProbability: 0.60, NewProbability: 0.70, TimeWindow: time.Hour, DetectedAt: time.Now(),
+		Components: &models.ScoreComponents{KL: 0.02},
+	}
+	if err := s.AddChange(c); err != nil {
+		t.Fatalf("AddChange after migration: %v", err)
+	}
+}
+
+func TestNew_AddsSnapshotVolumeColumnsToExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// snapshots table as created before volume and liquidity were recorded
+	_, err = db.Exec(`CREATE TABLE snapshots (
+		id TEXT PRIMARY KEY, market_id TEXT NOT NULL, yes_prob REAL NOT NULL, no_prob REAL NOT NULL,
+		timestamp INTEGER NOT NULL, source TEXT NOT NULL)`)
+	if err == nil {
+		_, err = db.Exec(`INSERT INTO snapshots VALUES ('old', 'e:m', 0.5, 0.5, ?, 'test')`, time.Now().Add(-time.Hour).UnixNano())
+	}
+	if err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	_ = db.Close()
+
+	s, err := New(100, 50, path)
+	if err != nil {
+		t.Fatalf("New on legacy database: %v", err)
+	}
+	defer s.Close()
+	snaps, err := s.GetSnapshots("e:m")
+	if err != nil {
+		t.Fatalf("GetSnapshots after migration: %v", err)
+	}
+	if len(snaps) != 1 || snaps[0].Volume24hr != 0 || snaps[0].Liquidity != 0 {
+		t.Errorf("legacy snapshot = %+v, want zero volume and liquidity", snaps)
+	}
+}
+
 func TestStorage_AddMarket_EnforcesMaxEvents(t *testing.T) {
 	// max_events=3: adding a 4th should evict the oldest.
 	s, err := New(3, 50, ":memory:")
@@ -418,3 +554,334 @@ func TestStorage_DefaultPath(t *testing.T) {
 	}
 	defer s.Close()
 }
+
+func TestStorage_BasisPointEncoding(t *testing.T) {
+	tests := []struct {
+		encoding string
+		in       float64
+		want     float64
+	}{
+		{EncodingReal, 0.123456, 0.123456},
+		{EncodingBasisPoints, 0.123456, 0.1235},
+		{EncodingBasisPoints, 0.12344, 0.1234},
+		{EncodingBasisPoints, 1.0, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.encoding, tt.in), func(t *testing.T) {
+			s, err := New(100, 50, ":memory:", Config{ProbabilityEncoding: tt.encoding})
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			defer func() { _ = s.Close() }()
+
+			now := time.Now()
+			m := testMarket("e:m", "e", "m", now)
+			m.YesProbability = tt.in
+			m.NoProbability = 1 - tt.in
+			if err := s.AddMarket(m); err != nil {
+				t.Fatalf("AddMarket: %v", err)
+			}
+			snap := &models.Snapshot{
+				ID: "snap-1", EventID: "e:m",
+				YesProbability: tt.in, NoProbability: 1 - tt.in,
+				Timestamp: now.Add(-time.Minute), Source: "test",
+			}
+			if err := s.AddSnapshot(snap); err != nil {
+				t.Fatalf("AddSnapshot: %v", err)
+			}
+
+			got, err := s.GetMarket("e:m")
+			if err != nil {
+				t.Fatalf("GetMarket: %v", err)
+			}
+			snaps, err := s.GetSnapshots("e:m")
+			if err != nil {
+				t.Fatalf("GetSnapshots: %v", err)
+			}
+			// Exact equality is the point of basis-point encoding.
+			if got.YesProbability != tt.want {
+				t.Errorf("market yes_prob = %v, want %v", got.YesProbability, tt.want)
+			}
+			if len(snaps) != 1 || snaps[0].YesProbability != tt.want {
+				t.Errorf("snapshot yes_prob = %v, want %v", snaps, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorage_Vacuum(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		wantMode int
+		setup    func(t *testing.T, path string)
+	}{
+		{"incremental", autoVacuumIncremental, func(t *testing.T, path string) {}},
+		// A database created before auto_vacuum was enabled must fall back to VACUUM.
+		{"legacy full vacuum", 0, func(t *testing.T, path string) {
+			db, err := sql.Open("sqlite", path)
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			if _, err := db.Exec(`CREATE TABLE legacy (x INTEGER)`); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			_ = db.Close()
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.db")
+			tt.setup(t, path)
+			s, err := New(1000, 50, path)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			defer s.Close()
+
+			var mode int
+			if err := s.db.QueryRow(`PRAGMA auto_vacuum`).Scan(&mode); err != nil || mode != tt.wantMode {
+				t.Fatalf("auto_vacuum = %d (err %v), want %d", mode, err, tt.wantMode)
+			}
+
+			now := time.Now()
+			for i := 0; i < 500; i++ {
+				m := testMarket(fmt.Sprintf("e%d:m", i), fmt.Sprintf("e%d", i), "m", now)
+				m.Description = strings.Repeat("x", 1000)
+				if err := s.AddMarket(m); err != nil {
+					t.Fatalf("AddMarket: %v", err)
+				}
+			}
+			if _, err := s.db.Exec(`DELETE FROM markets`); err != nil {
+				t.Fatalf("delete: %v", err)
+			}
+
+			before, after, err := s.Vacuum()
+			if err != nil {
+				t.Fatalf("Vacuum: %v", err)
+			}
+			if after >= before {
+				t.Errorf("expected vacuum to shrink the database: before=%d after=%d", before, after)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("stat: %v", err)
+			}
+			if info.Size() != after {
+				t.Errorf("file size %d != reported size %d", info.Size(), after)
+			}
+		})
+	}
+}
+
+func TestAlertCounts_ResetPerWindow(t *testing.T) {
+	s := newTestStorage(t)
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	for range 2 {
+		if err := s.IncrementAlertCounts([]string{"e1:m1", "e2:m2"}, day1); err != nil {
+			t.Fatalf("IncrementAlertCounts: %v", err)
+		}
+	}
+	if got, _ := s.GetAlertCount("e1:m1", day1); got != 2 {
+		t.Errorf("day 1 count = %d, want 2", got)
+	}
+	if got, _ := s.GetAlertCount("e3:m3", day1); got != 0 {
+		t.Errorf("unknown market count = %d, want 0", got)
+	}
+	if got, _ := s.GetAlertCount("e1:m1", day2); got != 0 {
+		t.Errorf("day 2 count before any alert = %d, want 0", got)
+	}
+
+	if err := s.IncrementAlertCounts([]string{"e1:m1"}, day2); err != nil {
+		t.Fatalf("IncrementAlertCounts: %v", err)
+	}
+	if got, _ := s.GetAlertCount("e1:m1", day2); got != 1 {
+		t.Errorf("day 2 count = %d, want 1 (reset at the new window)", got)
+	}
+}
+
+func TestStorage_RotateMarkets_MaxAge(t *testing.T) {
+	s, err := New(100, 50, ":memory:", Config{MaxMarketAge: time.Hour})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	fresh := testMarket("e-1:m-1", "e-1", "m-1", now.Add(-time.Minute))
+	stale := testMarket("e-2:m-2", "e-2", "m-2", now.Add(-2*time.Hour))
+	stale.CreatedAt = stale.LastUpdated
+	for _, m := range []*models.Market{fresh, stale} {
+		if err := s.AddMarket(m); err != nil {
+			t.Fatalf("AddMarket %s: %v", m.ID, err)
+		}
+	}
+	change := &models.Change{
+		ID: "c-stale", EventID: stale.ID, Magnitude: 0.1, Direction: "increase",
+		OldProbability: 0.4, NewProbability: 0.5, TimeWindow: time.Hour, DetectedAt: now,
+	}
+	if err := s.AddChange(change); err != nil {
+		t.Fatalf("AddChange: %v", err)
+	}
+	if err := s.IncrementAlertCounts([]string{stale.ID}, now); err != nil {
+		t.Fatalf("IncrementAlertCounts: %v", err)
+	}
+
+	if err := s.RotateMarkets(); err != nil {
+		t.Fatalf("RotateMarkets: %v", err)
+	}
+	markets, _ := s.GetAllMarkets()
+	if len(markets) != 1 || markets[0].ID != fresh.ID {
+		t.Errorf("expected only the fresh market to remain, got %d markets", len(markets))
+	}
+	if changes, _ := s.GetChangesForMarket(stale.ID, 10); len(changes) != 0 {
+		t.Errorf("expected the stale market's changes to be removed, got %d", len(changes))
+	}
+	if count, _ := s.GetAlertCount(stale.ID, now); count != 0 {
+		t.Errorf("expected the stale market's alert count to be removed, got %d", count)
+	}
+}
+
+func TestAnnouncements_SurviveRotation(t *testing.T) {
+	s, err := New(100, 50, ":memory:", Config{MaxMarketAge: time.Hour})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer s.Close()
+
+	now := time.Now()
+	stale := testMarket("e-1:m-1", "e-1", "m-1", now.Add(-2*time.Hour))
+	stale.CreatedAt = stale.LastUpdated
+	if err := s.AddMarket(stale); err != nil {
+		t.Fatalf("AddMarket: %v", err)
+	}
+	if err := s.MarkAnnounced([]string{stale.ID, "e-2:m-2"}, now); err != nil {
+		t.Fatalf("MarkAnnounced: %v", err)
+	}
+	if err := s.MarkAnnounced([]string{"e-3:m-3"}, now.Add(-announcementRetention-time.Hour)); err != nil {
+		t.Fatalf("MarkAnnounced: %v", err)
+	}
+
+	if err := s.RotateMarkets(); err != nil {
+		t.Fatalf("RotateMarkets: %v", err)
+	}
+	got, err := s.Announced([]string{stale.ID, "e-2:m-2", "e-3:m-3", "e-4:m-4"})
+	if err != nil {
+		t.Fatalf("Announced: %v", err)
+	}
+	// The rotated-out market is still remembered; the expired one is not
+	if !got[stale.ID] || !got["e-2:m-2"] || len(got) != 2 {
+		t.Errorf("Announced = %v, want the rotated market and e-2:m-2 only", got)
+	}
+}
+
+func TestAlerts_HistoryByMarket(t *testing.T) {
+	s := newTestStorage(t)
+	now := time.Now()
+	mk := func(id, marketID string, at time.Time) models.Change {
+		return models.Change{
+			ID: id, EventID: "e-1:" + marketID, MarketID: marketID, Magnitude: 0.1, Direction: "increase",
+			OldProbability: 0.4, NewProbability: 0.5, TimeWindow: time.Hour, DetectedAt: at, SignalScore: 0.2,
+		}
+	}
+	if err := s.AddAlerts([]models.Change{
+		mk("a1", "m-1", now.Add(-2*time.Hour)),
+		mk("a2", "m-1", now.Add(-time.Hour)),
+		mk("a3", "m-2", now),
+	}); err != nil {
+		t.Fatalf("AddAlerts: %v", err)
+	}
+
+	got, err := s.GetAlertsForMarket("e-1:m-1", 10)
+	if err != nil {
+		t.Fatalf("GetAlertsForMarket: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "a2" || got[1].ID != "a1" || !got[0].Notified {
+		t.Errorf("expected [a2 a1] newest first and notified, got %+v", got)
+	}
+	if got, _ := s.GetAlertsForMarket("m-1", 1); len(got) != 1 || got[0].ID != "a2" {
+		t.Errorf("bare market ID with limit 1: got %+v, want [a2]", got)
+	}
+
+	all, err := s.GetAlerts(2)
+	if err != nil {
+		t.Fatalf("GetAlerts: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "a3" || all[1].ID != "a2" {
+		t.Errorf("GetAlerts(2): got %+v, want [a3 a2]", all)
+	}
+
+	since, err := s.GetAlertsSince(now.Add(-90 * time.Minute))
+	if err != nil {
+		t.Fatalf("GetAlertsSince: %v", err)
+	}
+	if len(since) != 2 || since[0].ID != "a3" || since[1].ID != "a2" {
+		t.Errorf("GetAlertsSince(-90m): got %+v, want [a3 a2]", since)
+	}
+	if n, err := s.CountAlertsSince(now.Add(-90 * time.Minute)); err != nil || n != 2 {
+		t.Errorf("CountAlertsSince(-90m) = %d, %v; want 2", n, err)
+	}
+	if none, err := s.GetAlertsSince(now.Add(time.Minute)); err != nil || none == nil || len(none) != 0 {
+		t.Errorf("GetAlertsSince(future) = %#v, %v; want an empty, non-nil slice", none, err)
+	}
+}
+
+func TestAlerts_TopBySignalScore(t *testing.T) {
+	s := newTestStorage(t)
+	now := time.Now()
+	mk := func(id string, at time.Time, score float64) models.Change {
+		return models.Change{
+			ID: id, EventID: "e-1:m-" + id, MarketID: "m-" + id, Magnitude: 0.1, Direction: "increase",
+			OldProbability: 0.4, NewProbability: 0.5, TimeWindow: time.Hour, DetectedAt: at, SignalScore: score,
+		}
+	}
+	if err := s.AddAlerts([]models.Change{
+		mk("old", now.Add(-48*time.Hour), 0.9), // outside the window despite the best score
+		mk("low", now.Add(-time.Hour), 0.1),
+		mk("high", now.Add(-2*time.Hour), 0.5),
+		mk("mid", now, 0.3),
+	}); err != nil {
+		t.Fatalf("AddAlerts: %v", err)
+	}
+
+	got, err := s.GetTopAlerts(now.Add(-24*time.Hour), 2)
+	if err != nil {
+		t.Fatalf("GetTopAlerts: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "high" || got[1].ID != "mid" {
+		t.Errorf("GetTopAlerts: got %+v, want [high mid]", got)
+	}
+}
+
+func TestReadConnections(t *testing.T) {
+	s, err := New(100, 50, filepath.Join(t.TempDir(), "data.db"), Config{ReadConnections: 2})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if s.readDB == nil {
+		t.Fatal("expected a read-only handle")
+	}
+
+	alert := models.Change{
+		ID: "a1", EventID: "e-1:m-1", MarketID: "m-1", Magnitude: 0.1, Direction: "increase",
+		OldProbability: 0.4, NewProbability: 0.5, TimeWindow: time.Hour, DetectedAt: time.Now(),
+	}
+	if err := s.AddAlerts([]models.Change{alert}); err != nil {
+		t.Fatalf("AddAlerts: %v", err)
+	}
+	// Writes through the writer are visible to the read pool.
+	if got, err := s.GetAlerts(10); err != nil || len(got) != 1 {
+		t.Fatalf("GetAlerts via read pool: %v, %+v", err, got)
+	}
+	if _, err := s.readDB.Exec(`DELETE FROM alerts`); err == nil {
+		t.Error("expected the read handle to reject writes")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if err := s.readDB.Ping(); err == nil {
+		t.Error("expected the read handle to be closed")
+	}
+}
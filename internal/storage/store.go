@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+// Store is the persistence API the monitor, notifiers and subcommands use.
+// *Storage (SQLite) implements it; another backend only has to satisfy this
+// interface, with its own dialect for upserts and UnixNano timestamps.
+type Store interface {
+	Close() error
+
+	AddMarket(market *models.Market) error
+	GetMarket(id string) (*models.Market, error)
+	GetAllMarkets() ([]*models.Market, error)
+	UpdateMarket(market *models.Market) error
+	RotateMarkets() error
+
+	AddSnapshot(snapshot *models.Snapshot) error
+	GetSnapshots(marketID string) ([]models.Snapshot, error)
+	GetSnapshotsInWindow(marketID string, window time.Duration) ([]models.Snapshot, error)
+	GetSnapshotsSince(marketID string, since time.Time) ([]models.Snapshot, error)
+	GetSnapshotBefore(marketID string, t time.Time) (*models.Snapshot, error)
+	RotateSnapshots() error
+
+	AddChange(change *models.Change) error
+	UpdateChangeScores(changes []models.Change) error
+	GetTopChanges(k int) ([]models.Change, error)
+	GetChangesForMarket(marketID string, limit int) ([]models.Change, error)
+	ClearChanges() error
+
+	AddAlerts(changes []models.Change) error
+	GetAlerts(limit int) ([]models.Change, error)
+	GetAlertsForMarket(marketID string, limit int) ([]models.Change, error)
+	GetAlertsSince(since time.Time) ([]models.Change, error)
+	GetTopAlerts(since time.Time, limit int) ([]models.Change, error)
+	CountAlertsSince(since time.Time) (int, error)
+	IncrementAlertCounts(marketIDs []string, windowStart time.Time) error
+	GetAlertCount(marketID string, windowStart time.Time) (int, error)
+
+	MarkAnnounced(marketIDs []string, at time.Time) error
+	Announced(marketIDs []string) (map[string]bool, error)
+
+	AddCalibration(r models.CalibrationRecord) (bool, error)
+	GetCalibration() ([]models.CalibrationSummary, error)
+
+	Export() (*Dump, error)
+	Import(d *Dump) (ImportResult, error)
+	GetMarketState(id string, limit int) (*MarketState, error)
+	Vacuum() (before, after int64, err error)
+}
+
+var _ Store = (*Storage)(nil)
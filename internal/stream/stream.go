@@ -0,0 +1,176 @@
+// Package stream fans out alerts to live subscribers over Server-Sent Events.
+//
+// The monitoring cycle publishes each cycle's alert groups to a Broker; every
+// connected client of GET /alerts/stream receives them as "alert" events whose
+// data is one JSON-encoded models.Event. Each client has a bounded queue: when a
+// slow client falls behind, its oldest queued alerts are dropped so publishing
+// never blocks the monitoring loop.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/logger"
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+// DefaultClientBuffer is the per-client queue length used when none is configured.
+const DefaultClientBuffer = 64
+
+// keepaliveInterval is how often an idle stream sends an SSE comment, so proxies
+// and clients don't time out the connection between alerts.
+const keepaliveInterval = 30 * time.Second
+
+// Broker is an in-process pub/sub hub for alert groups. A nil *Broker is a valid
+// no-op publisher.
+type Broker struct {
+	bufferSize int
+
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	ch      chan []byte
+	dropped int // guarded by Broker.mu
+}
+
+// NewBroker returns a Broker that queues up to bufferSize alerts per client
+// (DefaultClientBuffer when bufferSize ≤ 0).
+func NewBroker(bufferSize int) *Broker {
+	if bufferSize <= 0 {
+		bufferSize = DefaultClientBuffer
+	}
+	return &Broker{
+		bufferSize: bufferSize,
+		subs:       make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish sends each group to every connected client. It never blocks: a full
+// client queue drops its oldest entry to make room.
+func (b *Broker) Publish(groups []models.Event) {
+	if b == nil || len(groups) == 0 {
+		return
+	}
+
+	payloads := make([][]byte, 0, len(groups))
+	for _, g := range groups {
+		data, err := json.Marshal(g)
+		if err != nil {
+			logger.Warn("Failed to encode alert group %s for stream: %v", g.ID, err)
+			continue
+		}
+		payloads = append(payloads, data)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		for _, data := range payloads {
+			select {
+			case sub.ch <- data:
+				continue
+			default:
+			}
+			// Queue full: discard the oldest alert. The client may drain it first,
+			// which frees the slot just as well. Publish is the only sender (under
+			// b.mu), so the queue has room afterwards.
+			select {
+			case <-sub.ch:
+				sub.dropped++
+			default:
+			}
+			sub.ch <- data
+		}
+	}
+}
+
+// Subscribers returns the number of connected clients.
+func (b *Broker) Subscribers() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+func (b *Broker) subscribe() *subscriber {
+	sub := &subscriber{ch: make(chan []byte, b.bufferSize)}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// unsubscribe removes sub and returns how many alerts it dropped.
+func (b *Broker) unsubscribe(sub *subscriber) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, sub)
+	return sub.dropped
+}
+
+// ServeHTTP streams alerts to the client until it disconnects.
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := b.subscribe()
+	defer func() {
+		if dropped := b.unsubscribe(sub); dropped > 0 {
+			logger.Warn("Alert stream client %s disconnected after %d alerts were dropped (slow consumer)", r.RemoteAddr, dropped)
+		}
+	}()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // disable nginx response buffering
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(w, ": connected\n\n"); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	logger.Debug("Alert stream client connected: %s", r.RemoteAddr)
+	if err := b.stream(r.Context(), w, flusher, sub); err != nil {
+		logger.Debug("Alert stream client %s: %v", r.RemoteAddr, err)
+	}
+}
+
+func (b *Broker) stream(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, sub *subscriber) error {
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data := <-sub.ch:
+			if _, err := fmt.Fprintf(w, "event: alert\ndata: %s\n\n", data); err != nil {
+				return err
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+func TestBroker_NilIsNoop(t *testing.T) {
+	var b *Broker
+	b.Publish([]models.Event{{ID: "evt-1"}})
+	if n := b.Subscribers(); n != 0 {
+		t.Errorf("Subscribers() = %d, want 0", n)
+	}
+}
+
+func TestBroker_DropsOldestWhenFull(t *testing.T) {
+	b := NewBroker(2)
+	sub := b.subscribe()
+
+	b.Publish([]models.Event{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+
+	var got []string
+	for range 2 {
+		var g models.Event
+		if err := json.Unmarshal(<-sub.ch, &g); err != nil {
+			t.Fatalf("invalid payload: %v", err)
+		}
+		got = append(got, g.ID)
+	}
+	if strings.Join(got, ",") != "2,3" {
+		t.Errorf("queued alerts = %v, want [2 3]", got)
+	}
+	if dropped := b.unsubscribe(sub); dropped != 1 {
+		t.Errorf("dropped = %d, want 1", dropped)
+	}
+}
+
+func TestServeHTTP_StreamsAlerts(t *testing.T) {
+	b := NewBroker(0)
+	srv := httptest.NewServer(b)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	// The handler subscribes before writing the preamble, so once it is read
+	// the client is guaranteed to receive the next publish.
+	r := bufio.NewReader(resp.Body)
+	if line, _ := r.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("preamble = %q", line)
+	}
+	_, _ = r.ReadString('\n')
+
+	b.Publish([]models.Event{{ID: "evt-1", Title: "Election", Markets: []models.Change{{EventID: "evt-1:m1"}}}})
+
+	if line, _ := r.ReadString('\n'); line != "event: alert\n" {
+		t.Fatalf("event line = %q", line)
+	}
+	line, _ := r.ReadString('\n')
+	var g models.Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(strings.TrimSpace(line), "data: ")), &g); err != nil {
+		t.Fatalf("invalid data line %q: %v", line, err)
+	}
+	if g.ID != "evt-1" || g.Title != "Election" || len(g.Markets) != 1 {
+		t.Errorf("unexpected group: %+v", g)
+	}
+
+	// Disconnecting unsubscribes the client.
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for b.Subscribers() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := b.Subscribers(); n != 0 {
+		t.Errorf("Subscribers() after disconnect = %d, want 0", n)
+	}
+}
+
+func TestServeHTTP_RejectsNonGET(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewBroker(0).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/alerts/stream", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want 405", rec.Code)
+	}
+}
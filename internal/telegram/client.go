@@ -8,25 +8,106 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rewired-gh/polyoracle/internal/logger"
 	"github.com/rewired-gh/polyoracle/internal/models"
 )
 
 // Client handles Telegram notifications
 type Client struct {
-	bot            *tgbotapi.BotAPI
-	chatID         int64
-	maxRetries     int
-	retryDelayBase time.Duration
+	bot                *tgbotapi.BotAPI
+	chatID             int64
+	testChatID         int64 // non-zero mirrors every notification here first, prefixed
+	maxRetries         int
+	retryDelayBase     time.Duration
+	maxMarketsPerGroup int
+	deltaStyle         string
+	showMarketID       bool
+	showLiquidity      bool
+	showSparkline      bool
+	showMargin         bool
+	retryPermanent     bool // retry permanent API errors as well (classification escape hatch)
+	plainTextFallback  bool // resend as plain text when Telegram can't parse the MarkdownV2
+	showLastAlert      bool
+	descriptionLength  int               // > 0 shows each group's event description, truncated to this many characters
+	categoryEmoji      map[string]string // category → title prefix
+	dispatch           *dispatcher       // every outbound message goes through here
+	loop               LoopControl
+	history            AlertHistory
+	adminUserIDs       map[int64]bool  // empty = every command is open to everyone
+	publicCommands     map[string]bool // commands any user may run when admins are set
 }
 
+// ClientConfig holds optional formatting configuration for the Telegram client
+type ClientConfig struct {
+	MaxMarketsPerGroup int    // 0 = list every market in a group
+	DeltaStyle         string // DeltaPoints (default), DeltaRelative or DeltaBoth
+	ShowMarketID       bool   // tag each market with its Polymarket ID, even when the question repeats the title
+	ShowLiquidity      bool   // show each market's liquidity and 24h volume under its move
+	ShowSparkline      bool   // show a sparkline of each market's Trend under its move
+	ShowMargin         bool   // append each market's score as a multiple of its threshold to its move
+	RetryPermanent     bool   // retry permanent API errors (bad chat, unauthorized, parse error) like transient ones
+	PlainTextFallback  bool   // on a MarkdownV2 parse error, resend once as plain text instead of dropping the message
+	TestChatID         string // mirror each notification to this chat first, marked as a test copy; "" = off
+	ShowLastAlert      bool   // note how long ago each market last alerted (LastAlertAt), or that it is a first alert
+	ShowDescription    bool   // show each event's description, truncated to DescriptionLength, under its title
+	DescriptionLength  int    // characters of description shown; 0 = DefaultDescriptionLength
+	// CategoryEmoji prefixes each event group's title with the emoji (or label)
+	// mapped to its category. Unmapped categories get no prefix.
+	CategoryEmoji map[string]string
+	// AdminUserIDs restricts commands outside PublicCommands to these Telegram
+	// user IDs. Empty leaves every command open.
+	AdminUserIDs []int64
+	// PublicCommands may be run by anyone even when AdminUserIDs is set.
+	// nil = DefaultPublicCommands.
+	PublicCommands []string
+	// SendConcurrency bounds simultaneous outbound sends (default
+	// DefaultSendConcurrency) and SendInterval spaces their starts (default
+	// DefaultSendInterval; negative = no spacing).
+	SendConcurrency int
+	SendInterval    time.Duration
+	// History answers /history and /top; nil leaves them unavailable.
+	History AlertHistory
+}
+
+// DefaultPublicCommands are the read-only commands open to every chat member.
+var DefaultPublicCommands = []string{"ping", "status", "top"}
+
+// botCommands lists every command handled by commandReply.
+var botCommands = []string{"ping", "pause", "resume", "status", "history", "top"}
+
+// markdownCommands reply in MarkdownV2 rather than plain text.
+var markdownCommands = map[string]bool{"top": true}
+
+// Delta display styles for probability changes.
+const (
+	DeltaPoints   = "points"   // absolute change in percentage points, e.g. "4.0%"
+	DeltaRelative = "relative" // change relative to the old probability, e.g. "+200%"
+	DeltaBoth     = "both"     // "4.0% · +200%"
+)
+
+// relativeDeltaEpsilon floors the denominator of relative changes so moves off a
+// near-zero base don't produce absurd percentages.
+const relativeDeltaEpsilon = 0.01
+
+// maxRelativeDelta caps the displayed relative change; larger values render as ">+999%".
+const maxRelativeDelta = 9.99
+
+// DefaultDescriptionLength is used when ShowDescription is set without a length.
+const DefaultDescriptionLength = 200
+
 // NewClient creates a new Telegram client
-func NewClient(botToken, chatID string, maxRetries int, retryDelayBase time.Duration) (*Client, error) {
+func NewClient(botToken, chatID string, maxRetries int, retryDelayBase time.Duration, cfg ...ClientConfig) (*Client, error) {
 	bot, err := tgbotapi.NewBotAPI(botToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Telegram bot: %w", err)
@@ -44,17 +125,97 @@ func NewClient(botToken, chatID string, maxRetries int, retryDelayBase time.Dura
 		retryDelayBase = time.Second
 	}
 
-	return &Client{
+	c := &Client{
 		bot:            bot,
 		chatID:         chatIDInt,
 		maxRetries:     maxRetries,
 		retryDelayBase: retryDelayBase,
-	}, nil
+	}
+	publicCommands := DefaultPublicCommands
+	concurrency, interval := DefaultSendConcurrency, DefaultSendInterval
+	if len(cfg) > 0 {
+		c.maxMarketsPerGroup = cfg[0].MaxMarketsPerGroup
+		c.deltaStyle = cfg[0].DeltaStyle
+		c.showMarketID = cfg[0].ShowMarketID
+		c.showLiquidity = cfg[0].ShowLiquidity
+		c.showSparkline = cfg[0].ShowSparkline
+		c.showMargin = cfg[0].ShowMargin
+		c.showLastAlert = cfg[0].ShowLastAlert
+		c.retryPermanent = cfg[0].RetryPermanent
+		c.plainTextFallback = cfg[0].PlainTextFallback
+		if cfg[0].TestChatID != "" {
+			if c.testChatID, err = strconv.ParseInt(cfg[0].TestChatID, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid test chat ID: %w", err)
+			}
+		}
+		if cfg[0].ShowDescription {
+			c.descriptionLength = cfg[0].DescriptionLength
+			if c.descriptionLength <= 0 {
+				c.descriptionLength = DefaultDescriptionLength
+			}
+		}
+		c.categoryEmoji = cfg[0].CategoryEmoji
+		c.history = cfg[0].History
+		if cfg[0].SendConcurrency > 0 {
+			concurrency = cfg[0].SendConcurrency
+		}
+		if cfg[0].SendInterval != 0 {
+			interval = cfg[0].SendInterval
+		}
+		c.adminUserIDs = make(map[int64]bool, len(cfg[0].AdminUserIDs))
+		for _, id := range cfg[0].AdminUserIDs {
+			c.adminUserIDs[id] = true
+		}
+		if cfg[0].PublicCommands != nil {
+			publicCommands = cfg[0].PublicCommands
+		}
+	}
+	c.publicCommands = make(map[string]bool, len(publicCommands))
+	for _, cmd := range publicCommands {
+		c.publicCommands[strings.TrimPrefix(cmd, "/")] = true
+	}
+	c.dispatch = newDispatcher(func(msg tgbotapi.Chattable) error {
+		_, err := bot.Send(msg)
+		return err
+	}, concurrency, interval)
+	return c, nil
 }
 
+// LoopControl lets bot commands pause and resume the polling loop.
+// Pause and Resume return false when the loop was already in the requested state.
+type LoopControl interface {
+	Pause() bool
+	Resume() bool
+	Paused() bool
+}
+
+// AlertHistory looks up previously sent alerts: those of one market, most
+// recent first, and the highest scoring since a time. *storage.Storage
+// implements it.
+type AlertHistory interface {
+	GetAlertsForMarket(marketID string, limit int) ([]models.Change, error)
+	GetTopAlerts(since time.Time, limit int) ([]models.Change, error)
+}
+
+// Bounds on /history's optional count argument.
+const (
+	defaultHistoryAlerts = 5
+	maxHistoryAlerts     = 20
+)
+
+// Bounds on /top's optional count argument, and how far back it ranks alerts.
+const (
+	defaultTopAlerts = 5
+	maxTopAlerts     = 20
+	topAlertsWindow  = 24 * time.Hour
+)
+
 // ListenForCommands starts a goroutine that polls for Telegram updates and handles bot commands.
+// loop may be nil, in which case /pause and /resume are unavailable.
 // It returns immediately; the goroutine stops when ctx is cancelled.
-func (c *Client) ListenForCommands(ctx context.Context) {
+func (c *Client) ListenForCommands(ctx context.Context, loop LoopControl) {
+	c.loop = loop
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 	updates := c.bot.GetUpdatesChan(u)
@@ -78,71 +239,510 @@ func (c *Client) ListenForCommands(ctx context.Context) {
 }
 
 func (c *Client) handleCommand(msg *tgbotapi.Message) {
-	switch msg.Command() {
+	var userID int64
+	if msg.From != nil {
+		userID = msg.From.ID
+	}
+	if command := msg.Command(); markdownCommands[command] && c.authorized(command, userID) {
+		text := c.commandReply(command, msg.CommandArguments())
+		if err := c.sendMarkdownV2To(msg.Chat.ID, text, "/"+command+" reply"); err != nil {
+			logger.Warn("Failed to answer /%s: %v", command, err)
+		}
+		return
+	}
+	text := c.authorizedReply(msg.Command(), msg.CommandArguments(), userID)
+	if text == "" {
+		return
+	}
+	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
+	c.dispatch.do(reply) //nolint:errcheck
+}
+
+// authorizedReply runs command (with its argument text) on behalf of userID, or
+// refuses a known command the user may not run. Returns "" for unknown commands.
+func (c *Client) authorizedReply(command, args string, userID int64) string {
+	if c.authorized(command, userID) {
+		return c.commandReply(command, args)
+	}
+	if slices.Contains(botCommands, command) {
+		return "Not authorized"
+	}
+	return ""
+}
+
+// authorized reports whether userID may run command. Without an admin list every
+// command is allowed; otherwise only public commands are open to non-admins.
+func (c *Client) authorized(command string, userID int64) bool {
+	if len(c.adminUserIDs) == 0 || c.publicCommands[command] {
+		return true
+	}
+	return c.adminUserIDs[userID]
+}
+
+// commandReply executes a bot command and returns the reply, plain text except
+// for markdownCommands (MarkdownV2), or "" for unknown commands. args is the
+// text after the command.
+func (c *Client) commandReply(command, args string) string {
+	switch command {
 	case "ping":
-		reply := tgbotapi.NewMessage(msg.Chat.ID, "Pong")
-		c.bot.Send(reply) //nolint:errcheck
+		return "Pong"
+	case "pause":
+		if c.loop == nil {
+			return "Pause is not available"
+		}
+		if !c.loop.Pause() {
+			return "Monitoring is already paused"
+		}
+		return "Monitoring paused; no polling until /resume"
+	case "resume":
+		if c.loop == nil {
+			return "Resume is not available"
+		}
+		if !c.loop.Resume() {
+			return "Monitoring is already running"
+		}
+		return "Monitoring resumed"
+	case "status":
+		if c.loop != nil && c.loop.Paused() {
+			return "Monitoring: paused"
+		}
+		return "Monitoring: running"
+	case "history":
+		return c.historyReply(args)
+	case "top":
+		return c.topReply(args, time.Now())
 	}
+	return ""
+}
+
+// historyReply answers "/history <marketID> [n]" with the market's last n sent
+// alerts (default defaultHistoryAlerts, at most maxHistoryAlerts), one per line.
+func (c *Client) historyReply(args string) string {
+	if c.history == nil {
+		return "History is not available"
+	}
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 2 {
+		return "Usage: /history <marketID> [n]"
+	}
+	marketID := strings.TrimPrefix(fields[0], "#")
+	n := defaultHistoryAlerts
+	if len(fields) == 2 {
+		parsed, err := strconv.Atoi(fields[1])
+		if err != nil || parsed < 1 {
+			return "Usage: /history <marketID> [n]"
+		}
+		n = min(parsed, maxHistoryAlerts)
+	}
+
+	alerts, err := c.history.GetAlertsForMarket(marketID, n)
+	if err != nil {
+		return "Failed to load history: " + err.Error()
+	}
+	if len(alerts) == 0 {
+		return "No alerts recorded for " + marketID
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Last %d alert(s) for %s", len(alerts), marketID)
+	if q := alerts[0].MarketQuestion; q != "" {
+		fmt.Fprintf(&b, " (%s)", q)
+	}
+	for _, a := range alerts {
+		arrow := "↑"
+		if a.Direction == "decrease" {
+			arrow = "↓"
+		}
+		fmt.Fprintf(&b, "\n%s %s %.1f%% → %.1f%% score %.3f",
+			a.DetectedAt.UTC().Format("2006-01-02 15:04"), arrow,
+			a.OldProbability*100, a.NewProbability*100, a.SignalScore)
+	}
+	return b.String()
+}
+
+// topReply answers "/top [n]" with the n highest scoring alerts sent within
+// topAlertsWindow of now (default defaultTopAlerts, at most maxTopAlerts), as
+// MarkdownV2.
+func (c *Client) topReply(args string, now time.Time) string {
+	if c.history == nil {
+		return "Top alerts are not available"
+	}
+	fields := strings.Fields(args)
+	n := defaultTopAlerts
+	if len(fields) > 1 {
+		return escapeMarkdownV2("Usage: /top [n]")
+	}
+	if len(fields) == 1 {
+		parsed, err := strconv.Atoi(fields[0])
+		if err != nil || parsed < 1 {
+			return escapeMarkdownV2("Usage: /top [n]")
+		}
+		n = min(parsed, maxTopAlerts)
+	}
+
+	alerts, err := c.history.GetTopAlerts(now.Add(-topAlertsWindow), n)
+	if err != nil {
+		return escapeMarkdownV2("Failed to load top alerts: " + err.Error())
+	}
+	if len(alerts) == 0 {
+		return "No alerts yet in the last " + escapeMarkdownV2(formatDuration(topAlertsWindow))
+	}
+	return formatTopAlerts(alerts, topAlertsWindow)
+}
+
+// formatTopAlerts renders alerts, highest score first, as a numbered MarkdownV2
+// list headed with the window they were sent in.
+func formatTopAlerts(alerts []models.Change, window time.Duration) string {
+	message := fmt.Sprintf("🏆 *Top %d alert\\(s\\) in the last %s*\n", len(alerts), escapeMarkdownV2(formatDuration(window)))
+	for i, a := range alerts {
+		a = a.Sided()
+		arrow := "📈"
+		if a.Direction == "decrease" {
+			arrow = "📉"
+		}
+		title := escapeMarkdownV2(a.EventTitle)
+		if a.EventURL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, a.EventURL)
+		}
+		message += fmt.Sprintf("\n%d\\. %s\n", i+1, title)
+		if a.MarketQuestion != "" && a.MarketQuestion != a.EventTitle {
+			message += fmt.Sprintf("   🎯 %s\n", escapeMarkdownV2(a.MarketQuestion))
+		}
+		probStr := escapeMarkdownV2(fmt.Sprintf("%s%.1f%% → %.1f%%", sideLabel(a), a.OldProbability*100, a.NewProbability*100))
+		scoreStr := escapeMarkdownV2(fmt.Sprintf("%.3f", a.SignalScore))
+		dateStr := escapeMarkdownV2(a.DetectedAt.UTC().Format("2006-01-02 15:04"))
+		message += fmt.Sprintf("   %s %s · score %s · %s\n", arrow, probStr, scoreStr, dateStr)
+	}
+	return message
 }
 
 // SendError sends a monitoring error notification to Telegram.
 // Call this only on the first occurrence of a consecutive error sequence.
 func (c *Client) SendError(cycleErr error) error {
 	text := fmt.Sprintf("⚠️ *Monitoring error*\n`%s`", escapeMarkdownV2(cycleErr.Error()))
-	msg := tgbotapi.NewMessage(c.chatID, text)
-	msg.ParseMode = "MarkdownV2"
-
-	var lastErr error
-	for i := 0; i < c.maxRetries; i++ {
-		_, err := c.bot.Send(msg)
-		if err == nil {
-			return nil
-		}
-		lastErr = err
-		time.Sleep(c.retryDelayBase * time.Duration(i+1))
-	}
-	return fmt.Errorf("failed to send error message after %d retries: %w", c.maxRetries, lastErr)
+	return c.sendMarkdownV2(text, "error message")
 }
 
 // SendRecovery sends a recovery notification to Telegram after consecutive failures.
 func (c *Client) SendRecovery(failureCount int) error {
 	text := fmt.Sprintf("✅ *Monitoring recovered* after %d consecutive failure\\(s\\)", failureCount)
-	msg := tgbotapi.NewMessage(c.chatID, text)
-	msg.ParseMode = "MarkdownV2"
+	return c.sendMarkdownV2(text, "recovery message")
+}
 
-	var lastErr error
-	for i := 0; i < c.maxRetries; i++ {
-		_, err := c.bot.Send(msg)
-		if err == nil {
-			return nil
+// SendSchemaDriftWarning warns that the Polymarket API response shape may have
+// changed: a large fraction of fetched events decoded with zero volume and no tags.
+func (c *Client) SendSchemaDriftWarning(fraction float64) error {
+	pct := escapeMarkdownV2(fmt.Sprintf("%.0f%%", fraction*100))
+	text := fmt.Sprintf("⚠️ *Possible Polymarket API schema change*\n%s of fetched events have zero volume and no tags; coverage may be degraded", pct)
+	return c.sendMarkdownV2(text, "schema drift warning")
+}
+
+// SendCoverageDrop warns that a cycle processed far fewer markets than the recent average.
+func (c *Client) SendCoverageDrop(processed int, baseline float64) error {
+	avg := escapeMarkdownV2(fmt.Sprintf("%.0f", baseline))
+	text := fmt.Sprintf("⚠️ *Coverage drop*\nProcessed %d markets this cycle vs a recent average of %s", processed, avg)
+	return c.sendMarkdownV2(text, "coverage drop warning")
+}
+
+// SendSnapshotFailures warns that snapshot writes are failing, so price history
+// (and with it change detection) is being lost.
+func (c *Client) SendSnapshotFailures(failed, total int) error {
+	text := fmt.Sprintf("⚠️ *Snapshot writes failing*\n%d of %d snapshot writes failed this cycle; check disk and database health", failed, total)
+	return c.sendMarkdownV2(text, "snapshot failure warning")
+}
+
+// SendLiquidityDrops notifies about events whose liquidity collapsed versus their
+// recent baseline. These are sent separately from probability movements.
+func (c *Client) SendLiquidityDrops(changes []models.Change) error {
+	return c.sendMarkdownV2(formatLiquidityDrops(changes), "liquidity drop warning")
+}
+
+// formatLiquidityDrops formats KindLiquidityDrop changes, largest drop first.
+func formatLiquidityDrops(changes []models.Change) string {
+	sorted := make([]models.Change, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Magnitude > sorted[j].Magnitude
+	})
+
+	message := "💧 *Liquidity Drop*\n\n"
+	for i, ch := range sorted {
+		title := escapeMarkdownV2(ch.EventTitle)
+		if ch.EventURL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, ch.EventURL)
 		}
-		lastErr = err
-		time.Sleep(c.retryDelayBase * time.Duration(i+1))
+		detail := escapeMarkdownV2(fmt.Sprintf("$%.0f → $%.0f (-%.0f%%)", ch.OldLiquidity, ch.NewLiquidity, ch.Magnitude*100))
+		message += fmt.Sprintf("%d\\. %s\n   %s\n", i+1, title, detail)
+	}
+	return message
+}
+
+// maxUncertaintyPerMessage caps how many converging markets (or ladder
+// inconsistencies) one message lists.
+const maxUncertaintyPerMessage = 10
+
+// SendUncertainty notifies about markets converging toward a 50% coin flip.
+// changes must be KindUncertainty changes, sorted by uncertainty gain desc.
+func (c *Client) SendUncertainty(changes []models.Change) error {
+	return c.sendMarkdownV2(formatUncertainty(changes), "uncertainty alert")
+}
+
+// formatUncertainty lists converging markets with their probability path.
+func formatUncertainty(changes []models.Change) string {
+	message := "🎲 *Converging to a Coin Flip*\n\n"
+	shown := changes
+	if len(shown) > maxUncertaintyPerMessage {
+		shown = shown[:maxUncertaintyPerMessage]
 	}
-	return fmt.Errorf("failed to send recovery message after %d retries: %w", c.maxRetries, lastErr)
+	for i, ch := range shown {
+		title := escapeMarkdownV2(ch.EventTitle)
+		if ch.EventURL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, ch.EventURL)
+		}
+		message += fmt.Sprintf("%d\\. %s\n", i+1, title)
+		if ch.MarketQuestion != "" && ch.MarketQuestion != ch.EventTitle {
+			message += fmt.Sprintf("   🎯 %s\n", escapeMarkdownV2(ch.MarketQuestion))
+		}
+		ch = ch.Sided()
+		path := sideLabel(ch) + escapeMarkdownV2(fmt.Sprintf("%.1f%% → %.1f%%", ch.OldProbability*100, ch.NewProbability*100))
+		message += fmt.Sprintf("   %s ⏱ %s\n", path, escapeMarkdownV2(formatDuration(ch.TimeWindow)))
+	}
+	if hidden := len(changes) - len(shown); hidden > 0 {
+		message += fmt.Sprintf("\n\\+%d more\n", hidden)
+	}
+	return message
 }
 
 // Send sends a notification with the detected event groups
 func (c *Client) Send(groups []models.Event) error {
-	message := c.formatMessage(groups)
+	return c.sendMarkdownV2(c.formatMessage(groups), "message")
+}
+
+// sendMarkdownV2 sends text to the configured chat with MarkdownV2 parsing,
+// retrying with linear backoff (on top of any flood-wait the dispatcher
+// enforces). what names the message in the returned error.
+func (c *Client) sendMarkdownV2(text, what string) error {
+	c.mirrorToTestChat(text, what)
+	return c.sendMarkdownV2To(c.chatID, text, what)
+}
 
-	// Create message
-	msg := tgbotapi.NewMessage(c.chatID, message)
+// sendMarkdownV2To is sendMarkdownV2 for any chat, without the test-chat
+// mirror; command replies use it to answer the chat that asked.
+func (c *Client) sendMarkdownV2To(chatID int64, text, what string) error {
+	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "MarkdownV2" // Use MarkdownV2 for better escaping support
 
-	// Send with retry
 	var lastErr error
-
 	for i := 0; i < c.maxRetries; i++ {
-		_, err := c.bot.Send(msg)
+		err := c.dispatch.do(msg)
 		if err == nil {
 			return nil
 		}
+		// Retrying can't fix a bad chat, a blocked bot or malformed markup
+		if isParseError(err) {
+			logger.Error("Telegram could not parse %s as MarkdownV2 (%v); message was:\n%s", what, err, text)
+			if c.plainTextFallback {
+				return c.sendPlainFallback(chatID, text, what)
+			}
+		}
+		if isPermanent(err) && !c.retryPermanent {
+			return fmt.Errorf("failed to send %s (permanent error, not retried): %w", what, err)
+		}
 		lastErr = err
 		time.Sleep(c.retryDelayBase * time.Duration(i+1))
 	}
+	return fmt.Errorf("failed to send %s after %d retries: %w", what, c.maxRetries, lastErr)
+}
+
+// testChatPrefix marks the copies sent to the test chat.
+const testChatPrefix = "🧪 *Test copy*\n\n"
+
+// mirrorToTestChat sends one copy of a notification to the test chat, if
+// configured, so it can be checked in a real Telegram render. Failures are
+// logged only; they never hold up the real send.
+func (c *Client) mirrorToTestChat(text, what string) {
+	if c.testChatID == 0 {
+		return
+	}
+	msg := tgbotapi.NewMessage(c.testChatID, testChatPrefix+text)
+	msg.ParseMode = "MarkdownV2"
+	if err := c.dispatch.do(msg); err != nil {
+		logger.Warn("Failed to mirror %s to the test chat: %v", what, err)
+	}
+}
+
+// sendPlainFallback makes a single attempt to deliver a MarkdownV2 message
+// Telegram rejected as unparsable, stripped down to plain text.
+func (c *Client) sendPlainFallback(chatID int64, text, what string) error {
+	msg := tgbotapi.NewMessage(chatID, plainText(text))
+	if err := c.dispatch.do(msg); err != nil {
+		return fmt.Errorf("failed to send %s as plain text after a MarkdownV2 parse error: %w", what, err)
+	}
+	logger.Warn("Sent %s as plain text after a MarkdownV2 parse error", what)
+	return nil
+}
+
+// plainText renders a MarkdownV2 message as plain text: escapes are resolved,
+// emphasis and code markers dropped and links written as "text (url)".
+func plainText(md string) string {
+	var b strings.Builder
+	b.Grow(len(md))
+	runes := []rune(md)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			}
+		case '*', '_', '~', '`', '|', '[':
+			// Markup; a literal would have been escaped
+		case ']':
+			if i+1 >= len(runes) || runes[i+1] != '(' {
+				continue
+			}
+			end := i + 2
+			for end < len(runes) && runes[end] != ')' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			url := strings.ReplaceAll(string(runes[i+2:min(end, len(runes))]), "\\", "")
+			b.WriteString(" (" + url + ")")
+			i = end
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isPermanent reports whether err is a Telegram API rejection that retrying
+// cannot fix: a bad request (unknown chat, unparsable markup), an invalid
+// token or a bot blocked or removed from the chat. Network errors, 5xx and
+// 429 (flood wait) are transient.
+func isPermanent(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return true
+	}
+	return false
+}
+
+// isParseError reports whether Telegram rejected a message's formatting
+// entities, which points at an escaping bug in a formatter.
+func isParseError(err error) bool {
+	var apiErr *tgbotapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusBadRequest &&
+		strings.Contains(apiErr.Message, "can't parse entities")
+}
+
+// maxNewMarketsPerMessage bounds a new-market announcement so a listing burst
+// produces one readable message rather than a wall of text.
+const maxNewMarketsPerMessage = 10
+
+// SendNewMarkets announces newly listed markets, highest 24hr volume first.
+func (c *Client) SendNewMarkets(markets []models.Market) error {
+	return c.sendMarkdownV2(formatNewMarkets(markets), "new market message")
+}
+
+// formatNewMarkets renders newly listed markets as a MarkdownV2 message,
+// listing at most maxNewMarketsPerMessage and summarizing the rest.
+func formatNewMarkets(markets []models.Market) string {
+	sorted := make([]models.Market, len(markets))
+	copy(sorted, markets)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Volume24hr > sorted[j].Volume24hr
+	})
+
+	message := "🆕 *New Markets Listed*\n\n"
+	shown := sorted
+	if len(shown) > maxNewMarketsPerMessage {
+		shown = shown[:maxNewMarketsPerMessage]
+	}
+	for i, m := range shown {
+		name := m.Title
+		if m.MarketQuestion != "" {
+			name = m.MarketQuestion
+		}
+		title := escapeMarkdownV2(name)
+		if m.EventURL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, m.EventURL)
+		}
+		volStr := escapeMarkdownV2(fmt.Sprintf("$%.0f", m.Volume24hr))
+		probStr := escapeMarkdownV2(fmt.Sprintf("%.1f%%", m.YesProbability*100))
+		message += fmt.Sprintf("%d\\. %s\n   💰 %s 24h · Yes %s\n", i+1, title, volStr, probStr)
+	}
+	if hidden := len(sorted) - len(shown); hidden > 0 {
+		message += fmt.Sprintf("\n\\+%d more\n", hidden)
+	}
+	return message
+}
 
-	return fmt.Errorf("failed to send message after %d retries: %w", c.maxRetries, lastErr)
+// SendInconsistencies notifies about ladder rungs priced above a rung they
+// imply. changes must be KindInconsistency changes, largest gap first.
+func (c *Client) SendInconsistencies(changes []models.Change) error {
+	return c.sendMarkdownV2(formatInconsistencies(changes), "ladder inconsistency alert")
+}
+
+// formatInconsistencies lists each mispriced rung next to the rung it implies.
+func formatInconsistencies(changes []models.Change) string {
+	message := "⚖️ *Ladder Inconsistency*\n\n"
+	shown := changes
+	if len(shown) > maxUncertaintyPerMessage {
+		shown = shown[:maxUncertaintyPerMessage]
+	}
+	for i, ch := range shown {
+		title := escapeMarkdownV2(ch.EventTitle)
+		if ch.EventURL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, ch.EventURL)
+		}
+		message += fmt.Sprintf("%d\\. %s\n", i+1, title)
+		message += fmt.Sprintf("   🎯 %s %s\n", escapeMarkdownV2(ch.MarketQuestion),
+			escapeMarkdownV2(fmt.Sprintf("%.1f%%", ch.NewProbability*100)))
+		message += fmt.Sprintf("   ⬆️ above %s %s\n", escapeMarkdownV2(ch.RelatedQuestion),
+			escapeMarkdownV2(fmt.Sprintf("%.1f%%", ch.OldProbability*100)))
+	}
+	if hidden := len(changes) - len(shown); hidden > 0 {
+		message += fmt.Sprintf("\n\\+%d more\n", hidden)
+	}
+	return message
+}
+
+// SendResolving notes markets that have been pinned at 0% or 100% long enough to
+// be treated as awaiting resolution; they are excluded from alerts from now on.
+func (c *Client) SendResolving(markets []models.Market) error {
+	return c.sendMarkdownV2(formatResolving(markets), "resolving message")
+}
+
+// formatResolving renders markets awaiting resolution as a MarkdownV2 message,
+// listing at most maxNewMarketsPerMessage and summarizing the rest.
+func formatResolving(markets []models.Market) string {
+	message := "⏳ *Awaiting Resolution*\n\n"
+	shown := markets
+	if len(shown) > maxNewMarketsPerMessage {
+		shown = shown[:maxNewMarketsPerMessage]
+	}
+	for i, m := range shown {
+		name := m.Title
+		if m.MarketQuestion != "" {
+			name = m.MarketQuestion
+		}
+		title := escapeMarkdownV2(name)
+		if m.EventURL != "" {
+			title = fmt.Sprintf("[%s](%s)", title, m.EventURL)
+		}
+		probStr := escapeMarkdownV2(fmt.Sprintf("%.1f%%", m.YesProbability*100))
+		message += fmt.Sprintf("%d\\. %s\n   Yes %s · alerts paused until the price moves\n", i+1, title, probStr)
+	}
+	if hidden := len(markets) - len(shown); hidden > 0 {
+		message += fmt.Sprintf("\n\\+%d more\n", hidden)
+	}
+	return message
 }
 
 // formatMessage formats event groups into a Telegram MarkdownV2 message.
@@ -165,32 +765,82 @@ func (c *Client) formatMessage(groups []models.Event) string {
 		} else {
 			titleLink = escapeMarkdownV2(group.Title)
 		}
+		if emoji := c.categoryEmoji[strings.ToLower(group.Category)]; emoji != "" {
+			titleLink = escapeMarkdownV2(emoji) + " " + titleLink
+		}
 
 		message += fmt.Sprintf("%d\\. %s\n", i+1, titleLink)
+		if c.descriptionLength > 0 && len(group.Markets) > 0 {
+			if desc := truncateText(group.Markets[0].Description, c.descriptionLength); desc != "" {
+				message += fmt.Sprintf("   ℹ️ _%s_\n", escapeMarkdownV2(desc))
+			}
+		}
+
+		// Markets are sorted by score desc, so truncation keeps the strongest signals.
+		shown := group.Markets
+		if c.maxMarketsPerGroup > 0 && len(shown) > c.maxMarketsPerGroup {
+			shown = shown[:c.maxMarketsPerGroup]
+		}
 
-		for _, change := range group.Markets {
+		for _, change := range shown {
+			// Markets tracked on their No side read as No probabilities throughout
+			change = change.Sided()
 			directionEmoji := "📈"
 			if change.Direction == "decrease" {
 				directionEmoji = "📉"
 			}
 
-			magnitudePct := change.Magnitude * 100
 			oldPct := change.OldProbability * 100
 			newPct := change.NewProbability * 100
 
-			magnitudeStr := escapeMarkdownV2(fmt.Sprintf("%.1f%%", magnitudePct))
+			magnitudeStr := c.formatDelta(change)
 			oldPctStr := escapeMarkdownV2(fmt.Sprintf("%.1f%%", oldPct))
 			newPctStr := escapeMarkdownV2(fmt.Sprintf("%.1f%%", newPct))
 			windowStr := escapeMarkdownV2(formatDuration(change.TimeWindow))
 
-			// Show market question as sub-bullet when it differs from the event question
-			if change.MarketQuestion != "" && change.MarketQuestion != group.Title {
-				escapedMarketQ := escapeMarkdownV2(change.MarketQuestion)
-				message += fmt.Sprintf("   🎯 %s\n", escapedMarketQ)
+			if label := c.marketLabel(change, group.Title); label != "" {
+				message += fmt.Sprintf("   🎯 %s\n", label)
+			}
+			if slices.Contains(change.Kinds(), models.KindExtreme) {
+				message += "   🔥 *Extreme move* \\(score above ceiling\\)\n"
+			}
+			if change.Kinds()[0] == models.KindResolved {
+				message += fmt.Sprintf("   🏁 *%s* \\(%s%s → %s final\\)\n", resolvedLabel(change), sideLabel(change), oldPctStr, newPctStr)
+				continue
+			}
+
+			margin := ""
+			if c.showMargin && change.Components != nil && change.Components.Threshold > 0 {
+				multiple := change.SignalScore / change.Components.Threshold
+				margin = " · " + escapeMarkdownV2(fmt.Sprintf("%.1f× threshold", multiple))
 			}
+			message += fmt.Sprintf("   %s %s \\(%s%s → %s\\) ⏱ %s%s\n",
+				directionEmoji, magnitudeStr, sideLabel(change), oldPctStr, newPctStr, windowStr, margin)
 
-			message += fmt.Sprintf("   %s *%s* \\(%s → %s\\) ⏱ %s\n",
-				directionEmoji, magnitudeStr, oldPctStr, newPctStr, windowStr)
+			if c.showLiquidity {
+				liqStr := escapeMarkdownV2(fmt.Sprintf("$%.0f", change.Liquidity))
+				volStr := escapeMarkdownV2(fmt.Sprintf("$%.0f", change.Volume24hr))
+				message += fmt.Sprintf("   💧 Liq %s · Vol 24h %s\n", liqStr, volStr)
+			}
+			if c.showLastAlert {
+				if change.LastAlertAt.IsZero() {
+					message += "   🆕 First alert\n"
+				} else {
+					ago := escapeMarkdownV2(formatDuration(change.DetectedAt.Sub(change.LastAlertAt)))
+					message += fmt.Sprintf("   🕑 Last alerted %s ago\n", ago)
+				}
+			}
+			if c.showSparkline && len(change.Trend) >= 2 {
+				// Block elements are not MarkdownV2 specials; no escaping needed
+				message += fmt.Sprintf("   📊 %s\n", sparkline(change.Trend))
+			}
+		}
+
+		if hidden := len(group.Markets) - len(shown); hidden > 0 {
+			message += fmt.Sprintf("   \\+%d more\n", hidden)
+		}
+		if len(group.Related) > 0 {
+			message += fmt.Sprintf("   🔗 \\+%d related\n", len(group.Related))
 		}
 
 		message += "\n"
@@ -199,6 +849,96 @@ func (c *Client) formatMessage(groups []models.Event) string {
 	return message
 }
 
+// resolvedLabel names how a resolved change's market settled.
+func resolvedLabel(change models.Change) string {
+	switch change.Outcome {
+	case models.SideYes:
+		return "Resolved Yes"
+	case models.SideNo:
+		return "Resolved No"
+	}
+	return "Closed"
+}
+
+// sideLabel returns "No " for changes framed from the No side, so inverted
+// probabilities are not mistaken for Yes prices. Empty for the Yes side.
+func sideLabel(change models.Change) string {
+	if change.Side == models.SideNo {
+		return "No "
+	}
+	return ""
+}
+
+// truncateText collapses whitespace runs (including newlines) in s to single
+// spaces and cuts it to at most n characters, ending in "…" when shortened.
+func truncateText(s string, n int) string {
+	r := []rune(strings.Join(strings.Fields(s), " "))
+	if len(r) <= n {
+		return string(r)
+	}
+	return strings.TrimRight(string(r[:n-1]), " ") + "…"
+}
+
+// marketLabel returns the escaped sub-bullet naming the market that moved: its
+// question when it differs from the event title, plus its ID when showMarketID
+// is set. Empty when there is nothing to add to the title.
+func (c *Client) marketLabel(change models.Change, title string) string {
+	var label string
+	if change.MarketQuestion != "" && change.MarketQuestion != title {
+		label = escapeMarkdownV2(change.MarketQuestion)
+	}
+	if c.showMarketID && change.MarketID != "" {
+		id := "`#" + escapeMarkdownV2(change.MarketID) + "`"
+		if label == "" {
+			return id
+		}
+		return label + " " + id
+	}
+	return label
+}
+
+// formatDelta renders a change's size in the configured delta style as bold
+// MarkdownV2 text.
+func (c *Client) formatDelta(change models.Change) string {
+	points := "*" + escapeMarkdownV2(fmt.Sprintf("%.1f%%", change.Magnitude*100)) + "*"
+	switch c.deltaStyle {
+	case DeltaRelative:
+		return "*" + escapeMarkdownV2(relativeDelta(change.OldProbability, change.NewProbability)) + "*"
+	case DeltaBoth:
+		return points + " · *" + escapeMarkdownV2(relativeDelta(change.OldProbability, change.NewProbability)) + "*"
+	default:
+		return points
+	}
+}
+
+// relativeDelta formats (new-old)/max(old, ε) as a signed percentage, capped at
+// maxRelativeDelta. The result is unescaped.
+func relativeDelta(oldProb, newProb float64) string {
+	rel := (newProb - oldProb) / math.Max(oldProb, relativeDeltaEpsilon)
+	if rel > maxRelativeDelta {
+		return fmt.Sprintf(">+%.0f%%", maxRelativeDelta*100)
+	}
+	return fmt.Sprintf("%+.0f%%", rel*100)
+}
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline renders points as block characters scaled to their own min–max
+// range. A flat series renders at mid height.
+func sparkline(points []float64) string {
+	lo, hi := slices.Min(points), slices.Max(points)
+	var b strings.Builder
+	for _, p := range points {
+		level := len(sparkBlocks) / 2
+		if hi-lo > 1e-9 {
+			level = int(math.Round((p - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
 // escapeMarkdownV2 escapes special characters for Telegram MarkdownV2.
 // Characters that need escaping: _ * [ ] ( ) ~ ` > # + - = | { } . !
 func escapeMarkdownV2(text string) string {
@@ -1,8 +1,12 @@
 package telegram
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
 )
 
 func TestFormatDuration(t *testing.T) {
@@ -66,3 +70,612 @@ func TestNewClient_InvalidChatID(t *testing.T) {
 		t.Error("Expected error for invalid chat ID, got nil")
 	}
 }
+
+func TestFormatMessage_MaxMarketsPerGroup(t *testing.T) {
+	markets := make([]models.Change, 5)
+	for i := range markets {
+		markets[i] = models.Change{
+			EventID:        fmt.Sprintf("e:m%d", i),
+			MarketQuestion: fmt.Sprintf("Rung %d?", i),
+			Magnitude:      0.1,
+			Direction:      "increase",
+			OldProbability: 0.4,
+			NewProbability: 0.5,
+			TimeWindow:     time.Hour,
+			SignalScore:    float64(5 - i),
+		}
+	}
+	groups := []models.Event{{ID: "e", Title: "Ladder", Markets: markets}}
+
+	tests := []struct {
+		name      string
+		max       int
+		wantShown int
+		wantMore  string
+	}{
+		{"unlimited", 0, 5, ""},
+		{"truncated", 2, 2, "\\+3 more"},
+		{"cap above size", 10, 5, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{maxMarketsPerGroup: tt.max}
+			msg := c.formatMessage(groups)
+			if got := strings.Count(msg, "🎯"); got != tt.wantShown {
+				t.Errorf("shown markets = %d, want %d", got, tt.wantShown)
+			}
+			if tt.wantMore != "" && !strings.Contains(msg, tt.wantMore) {
+				t.Errorf("expected %q in message:\n%s", tt.wantMore, msg)
+			}
+			if tt.wantMore == "" && strings.Contains(msg, "more") {
+				t.Errorf("unexpected overflow line in message:\n%s", msg)
+			}
+			if tt.max > 0 && tt.max < 5 && strings.Contains(msg, "Rung 4") {
+				t.Errorf("lowest-scoring market should have been truncated")
+			}
+		})
+	}
+}
+
+type fakeLoop struct{ paused bool }
+
+func (f *fakeLoop) Pause() bool {
+	if f.paused {
+		return false
+	}
+	f.paused = true
+	return true
+}
+
+func (f *fakeLoop) Resume() bool {
+	if !f.paused {
+		return false
+	}
+	f.paused = false
+	return true
+}
+
+func (f *fakeLoop) Paused() bool { return f.paused }
+
+func TestCommandReply_PauseResume(t *testing.T) {
+	c := &Client{loop: &fakeLoop{}}
+
+	steps := []struct {
+		command string
+		want    string
+	}{
+		{"status", "Monitoring: running"},
+		{"pause", "Monitoring paused; no polling until /resume"},
+		{"pause", "Monitoring is already paused"},
+		{"status", "Monitoring: paused"},
+		{"resume", "Monitoring resumed"},
+		{"resume", "Monitoring is already running"},
+		{"status", "Monitoring: running"},
+		{"ping", "Pong"},
+		{"unknown", ""},
+	}
+	for _, st := range steps {
+		if got := c.commandReply(st.command, ""); got != st.want {
+			t.Errorf("/%s: got %q, want %q", st.command, got, st.want)
+		}
+	}
+}
+
+func TestAuthorizedReply(t *testing.T) {
+	const admin, member = int64(100), int64(200)
+	gated := &Client{
+		loop:           &fakeLoop{},
+		adminUserIDs:   map[int64]bool{admin: true},
+		publicCommands: map[string]bool{"ping": true, "status": true},
+	}
+	open := &Client{loop: &fakeLoop{}}
+
+	tests := []struct {
+		name    string
+		c       *Client
+		command string
+		userID  int64
+		want    string
+	}{
+		{"public command, member", gated, "status", member, "Monitoring: running"},
+		{"control command, member", gated, "pause", member, "Not authorized"},
+		{"control command, admin", gated, "pause", admin, "Monitoring paused; no polling until /resume"},
+		{"unknown command, member", gated, "foo", member, ""},
+		{"no admins configured", open, "pause", member, "Monitoring paused; no polling until /resume"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.authorizedReply(tt.command, "", tt.userID); got != tt.want {
+				t.Errorf("/%s by %d: got %q, want %q", tt.command, tt.userID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatNewMarkets(t *testing.T) {
+	var markets []models.Market
+	for i := 0; i < maxNewMarketsPerMessage+2; i++ {
+		markets = append(markets, models.Market{
+			Title:          "Event",
+			MarketQuestion: fmt.Sprintf("Market %d?", i),
+			EventURL:       "https://polymarket.com/event/e",
+			YesProbability: 0.42,
+			Volume24hr:     float64(1000 * (i + 1)),
+		})
+	}
+
+	msg := formatNewMarkets(markets)
+	if !strings.HasPrefix(msg, "🆕 *New Markets Listed*") {
+		t.Errorf("unexpected header:\n%s", msg)
+	}
+	// Highest volume first
+	if strings.Index(msg, "Market 11?") > strings.Index(msg, "Market 10?") {
+		t.Errorf("markets not sorted by volume desc:\n%s", msg)
+	}
+	if strings.Contains(msg, "Market 0?") || strings.Contains(msg, "Market 1?") {
+		t.Errorf("lowest-volume markets should be summarized:\n%s", msg)
+	}
+	if !strings.Contains(msg, "\\+2 more") {
+		t.Errorf("expected overflow line:\n%s", msg)
+	}
+	if !strings.Contains(msg, "42\\.0%") {
+		t.Errorf("expected escaped probability:\n%s", msg)
+	}
+}
+
+func TestFormatLiquidityDrops(t *testing.T) {
+	drops := []models.Change{
+		{EventTitle: "Small drop", Kind: models.KindLiquidityDrop, Magnitude: 0.5, OldLiquidity: 100000, NewLiquidity: 50000},
+		{EventTitle: "Big drop", EventURL: "https://polymarket.com/event/b", Kind: models.KindLiquidityDrop, Magnitude: 0.8, OldLiquidity: 200000, NewLiquidity: 40000},
+	}
+
+	msg := formatLiquidityDrops(drops)
+	if !strings.HasPrefix(msg, "💧 *Liquidity Drop*") {
+		t.Errorf("unexpected header:\n%s", msg)
+	}
+	// Largest drop first
+	if strings.Index(msg, "Big drop") > strings.Index(msg, "Small drop") {
+		t.Errorf("drops not sorted by magnitude desc:\n%s", msg)
+	}
+	if !strings.Contains(msg, "[Big drop](https://polymarket.com/event/b)") {
+		t.Errorf("expected linked title:\n%s", msg)
+	}
+	if !strings.Contains(msg, "$200000 → $40000 \\(\\-80%\\)") {
+		t.Errorf("expected escaped liquidity detail:\n%s", msg)
+	}
+}
+
+func TestRelativeDelta(t *testing.T) {
+	tests := []struct {
+		old, new float64
+		want     string
+	}{
+		{0.02, 0.06, "+200%"},
+		{0.60, 0.75, "+25%"},
+		{0.80, 0.60, "-25%"},
+		{0.50, 0.50, "+0%"},
+		{0.001, 0.05, "+490%"},  // near-zero base: denominator floored at ε
+		{0.005, 0.15, ">+999%"}, // capped
+		{0.0, 0.03, "+300%"},    // zero base uses ε = 1%
+	}
+	for _, tt := range tests {
+		if got := relativeDelta(tt.old, tt.new); got != tt.want {
+			t.Errorf("relativeDelta(%v, %v) = %q, want %q", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMessage_DeltaStyle(t *testing.T) {
+	groups := []models.Event{{ID: "e", Title: "Tail", Markets: []models.Change{{
+		EventID: "e:m", Magnitude: 0.04, Direction: "increase",
+		OldProbability: 0.02, NewProbability: 0.06, TimeWindow: time.Hour,
+	}}}}
+
+	tests := []struct {
+		style string
+		want  string
+		avoid string
+	}{
+		{"", "*4\\.0%*", "200"},
+		{DeltaPoints, "*4\\.0%*", "200"},
+		{DeltaRelative, "*\\+200%*", "4\\.0%*"},
+		{DeltaBoth, "*4\\.0%* · *\\+200%*", ""},
+	}
+	for _, tt := range tests {
+		c := &Client{deltaStyle: tt.style}
+		msg := c.formatMessage(groups)
+		if !strings.Contains(msg, tt.want) {
+			t.Errorf("style %q: expected %q in message:\n%s", tt.style, tt.want, msg)
+		}
+		if tt.avoid != "" && strings.Contains(msg, tt.avoid) {
+			t.Errorf("style %q: unexpected %q in message:\n%s", tt.style, tt.avoid, msg)
+		}
+	}
+}
+
+func TestFormatUncertainty(t *testing.T) {
+	var changes []models.Change
+	for i := 0; i < maxUncertaintyPerMessage+1; i++ {
+		changes = append(changes, models.Change{
+			EventTitle:     "Election",
+			MarketQuestion: fmt.Sprintf("Candidate %d?", i),
+			EventURL:       "https://polymarket.com/event/e",
+			Kind:           models.KindUncertainty,
+			OldProbability: 0.8,
+			NewProbability: 0.55,
+			TimeWindow:     time.Hour,
+		})
+	}
+
+	msg := formatUncertainty(changes)
+	if !strings.HasPrefix(msg, "🎲 *Converging to a Coin Flip*") {
+		t.Errorf("unexpected header:\n%s", msg)
+	}
+	if !strings.Contains(msg, "80\\.0% → 55\\.0%") {
+		t.Errorf("expected escaped probability path:\n%s", msg)
+	}
+	if strings.Contains(msg, "Candidate 10?") || !strings.Contains(msg, "\\+1 more") {
+		t.Errorf("expected overflow to be summarized:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_MarketContext(t *testing.T) {
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{{
+		EventID: "e:m1", MarketID: "512", MarketQuestion: "Fed cut in June?",
+		Magnitude: 0.05, Direction: "increase", OldProbability: 0.40, NewProbability: 0.45,
+		TimeWindow: time.Hour, Liquidity: 12500, Volume24hr: 3400.4,
+	}}}}
+
+	tests := []struct {
+		name          string
+		showMarketID  bool
+		showLiquidity bool
+		want          []string
+		avoid         []string
+	}{
+		{"default", false, false, nil, []string{"🎯", "💧"}},
+		{"market id", true, false, []string{"🎯 `#512`"}, []string{"💧"}},
+		{"liquidity", false, true, []string{"💧 Liq $12500 · Vol 24h $3400"}, []string{"🎯"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{showMarketID: tt.showMarketID, showLiquidity: tt.showLiquidity}
+			msg := c.formatMessage(groups)
+			for _, w := range tt.want {
+				if !strings.Contains(msg, w) {
+					t.Errorf("expected %q in message:\n%s", w, msg)
+				}
+			}
+			for _, a := range tt.avoid {
+				if strings.Contains(msg, a) {
+					t.Errorf("unexpected %q in message:\n%s", a, msg)
+				}
+			}
+		})
+	}
+
+	// A distinct question is shown with the ID appended.
+	groups[0].Markets[0].MarketQuestion = "25bp cut?"
+	msg := (&Client{showMarketID: true}).formatMessage(groups)
+	if !strings.Contains(msg, "🎯 25bp cut? `#512`") {
+		t.Errorf("expected question and ID in message:\n%s", msg)
+	}
+}
+
+func TestFormatResolving(t *testing.T) {
+	msg := formatResolving([]models.Market{{
+		Title: "Event", MarketQuestion: "Will it resolve?", EventURL: "https://polymarket.com/event/e",
+		YesProbability: 0.998,
+	}})
+	if !strings.HasPrefix(msg, "⏳ *Awaiting Resolution*") {
+		t.Errorf("unexpected header:\n%s", msg)
+	}
+	if !strings.Contains(msg, "[Will it resolve?](https://polymarket.com/event/e)") || !strings.Contains(msg, "99\\.8%") {
+		t.Errorf("expected linked question and escaped probability:\n%s", msg)
+	}
+}
+
+func TestFormatInconsistencies(t *testing.T) {
+	msg := formatInconsistencies([]models.Change{{
+		EventTitle: "Bitcoin price", EventURL: "https://polymarket.com/event/btc",
+		MarketQuestion: "Above $150k?", RelatedQuestion: "Above $100k?",
+		Kind: models.KindInconsistency, OldProbability: 0.30, NewProbability: 0.40, Magnitude: 0.10,
+	}})
+	if !strings.HasPrefix(msg, "⚖️ *Ladder Inconsistency*") {
+		t.Errorf("unexpected header:\n%s", msg)
+	}
+	for _, want := range []string{
+		"[Bitcoin price](https://polymarket.com/event/btc)",
+		"🎯 Above $150k? 40\\.0%",
+		"⬆️ above Above $100k? 30\\.0%",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in message:\n%s", want, msg)
+		}
+	}
+}
+
+func TestFormatMessage_CategoryEmoji(t *testing.T) {
+	groups := []models.Event{
+		{ID: "e1", Title: "BTC 100k?", Category: "crypto", Markets: []models.Change{{EventID: "e1:m", Direction: "increase"}}},
+		{ID: "e2", Title: "Rate cut?", Category: "finance", Markets: []models.Change{{EventID: "e2:m", Direction: "increase"}}},
+	}
+	c := &Client{categoryEmoji: map[string]string{"crypto": "₿"}}
+	msg := c.formatMessage(groups)
+	if !strings.Contains(msg, "1\\. ₿ BTC 100k?") {
+		t.Errorf("expected crypto prefix:\n%s", msg)
+	}
+	if !strings.Contains(msg, "2\\. Rate cut?") {
+		t.Errorf("unmapped category should have no prefix:\n%s", msg)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []float64
+		want   string
+	}{
+		{"rising", []float64{0.10, 0.20, 0.30, 0.40, 0.50, 0.60, 0.70, 0.80}, "▁▂▃▄▅▆▇█"},
+		{"spike", []float64{0.40, 0.60, 0.40}, "▁█▁"},
+		{"flat", []float64{0.5, 0.5, 0.5}, "▅▅▅"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sparkline(tt.points)
+			if got != tt.want {
+				t.Errorf("sparkline(%v) = %q, want %q", tt.points, got, tt.want)
+			}
+			if escapeMarkdownV2(got) != got {
+				t.Errorf("sparkline %q needs MarkdownV2 escaping", got)
+			}
+		})
+	}
+}
+
+func TestFormatMessage_Sparkline(t *testing.T) {
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{{
+		EventID: "e:m1", MarketQuestion: "Fed cut in June?",
+		Magnitude: 0.10, Direction: "increase", OldProbability: 0.40, NewProbability: 0.50,
+		TimeWindow: time.Hour, Trend: []float64{0.40, 0.45, 0.50},
+	}}}}
+
+	if msg := (&Client{}).formatMessage(groups); strings.Contains(msg, "📊") {
+		t.Errorf("unexpected sparkline with show_sparkline off:\n%s", msg)
+	}
+	if msg := (&Client{showSparkline: true}).formatMessage(groups); !strings.Contains(msg, "📊 ▁▅█") {
+		t.Errorf("expected sparkline in message:\n%s", msg)
+	}
+	groups[0].Markets[0].Trend = groups[0].Markets[0].Trend[:1]
+	if msg := (&Client{showSparkline: true}).formatMessage(groups); strings.Contains(msg, "📊") {
+		t.Errorf("unexpected sparkline for a single point:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_ThresholdMultiple(t *testing.T) {
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{{
+		EventID: "e:m1", MarketQuestion: "Fed cut in June?",
+		Magnitude: 0.10, Direction: "increase", OldProbability: 0.40, NewProbability: 0.50,
+		TimeWindow: time.Hour, SignalScore: 0.032,
+		Components: &models.ScoreComponents{Threshold: 0.01},
+	}}}}
+
+	if msg := (&Client{}).formatMessage(groups); strings.Contains(msg, "threshold") {
+		t.Errorf("unexpected threshold multiple with the option off:\n%s", msg)
+	}
+	if msg := (&Client{showMargin: true}).formatMessage(groups); !strings.Contains(msg, "⏱ 1h · 3\\.2× threshold\n") {
+		t.Errorf("expected threshold multiple on the move line:\n%s", msg)
+	}
+	groups[0].Markets[0].Components = nil
+	if msg := (&Client{showMargin: true}).formatMessage(groups); strings.Contains(msg, "threshold") {
+		t.Errorf("unexpected threshold multiple for an unscored change:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Related(t *testing.T) {
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{{
+		EventID: "e:m1", MarketQuestion: "Fed cut in June?",
+		Magnitude: 0.10, Direction: "increase", OldProbability: 0.40, NewProbability: 0.50,
+		TimeWindow: time.Hour,
+	}}}}
+	if msg := (&Client{}).formatMessage(groups); strings.Contains(msg, "related") {
+		t.Errorf("unexpected related note:\n%s", msg)
+	}
+	groups[0].Related = []string{"Fed cut in July?", "Fed cut by June 30?"}
+	if msg := (&Client{}).formatMessage(groups); !strings.Contains(msg, "🔗 \\+2 related\n") {
+		t.Errorf("expected related note in message:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Description(t *testing.T) {
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{{
+		EventID: "e:m1", MarketQuestion: "Fed cut in June?",
+		Magnitude: 0.10, Direction: "increase", OldProbability: 0.40, NewProbability: 0.50,
+		TimeWindow:  time.Hour,
+		Description: "Resolves YES if the FOMC\nlowers the target range (by 25bp or more) at its June meeting.",
+	}}}}
+
+	if msg := (&Client{}).formatMessage(groups); strings.Contains(msg, "ℹ️") {
+		t.Errorf("unexpected description with show_description off:\n%s", msg)
+	}
+	msg := (&Client{descriptionLength: 40}).formatMessage(groups)
+	if !strings.Contains(msg, "ℹ️ _Resolves YES if the FOMC lowers the tar…_\n") {
+		t.Errorf("expected truncated, single-line description:\n%s", msg)
+	}
+	msg = (&Client{descriptionLength: 200}).formatMessage(groups)
+	if !strings.Contains(msg, "\\(by 25bp or more\\) at its June meeting\\._") {
+		t.Errorf("expected escaped full description:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_ExtremeMarker(t *testing.T) {
+	change := models.Change{
+		EventID: "e:m1", MarketQuestion: "Fed cut in June?",
+		Magnitude: 0.60, Direction: "increase", OldProbability: 0.20, NewProbability: 0.80,
+		TimeWindow: time.Hour,
+	}
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{change}}}
+	if msg := (&Client{}).formatMessage(groups); strings.Contains(msg, "🔥") {
+		t.Errorf("unexpected extreme marker:\n%s", msg)
+	}
+
+	groups[0].Markets[0].Kind = models.KindProbability + "," + models.KindExtreme
+	if msg := (&Client{}).formatMessage(groups); !strings.Contains(msg, "🔥 *Extreme move* \\(score above ceiling\\)") {
+		t.Errorf("expected extreme marker in message:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_Resolved(t *testing.T) {
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{
+		{EventID: "e:m1", MarketQuestion: "25bp", Kind: models.KindResolved, Outcome: models.SideYes, Magnitude: 0.38,
+			Direction: "increase", OldProbability: 0.62, NewProbability: 1, DetectedAt: time.Now()},
+		{EventID: "e:m2", MarketQuestion: "50bp", Kind: models.KindResolved, Magnitude: 0.1,
+			Direction: "increase", OldProbability: 0.40, NewProbability: 0.50, DetectedAt: time.Now()},
+	}}}
+
+	msg := (&Client{}).formatMessage(groups)
+	for _, want := range []string{"🏁 *Resolved Yes* \\(62\\.0% → 100\\.0% final\\)\n", "🏁 *Closed* \\(40\\.0% → 50\\.0% final\\)\n"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in message:\n%s", want, msg)
+		}
+	}
+	if strings.Contains(msg, "⏱") {
+		t.Errorf("resolved markets should not show a move window:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_LastAlert(t *testing.T) {
+	detected := time.Date(2025, 3, 1, 14, 30, 0, 0, time.UTC)
+	groups := []models.Event{{ID: "e", Title: "Fed cut in June?", Markets: []models.Change{
+		{EventID: "e:m1", MarketQuestion: "25bp", Magnitude: 0.10, Direction: "increase", OldProbability: 0.40, NewProbability: 0.50,
+			TimeWindow: time.Hour, DetectedAt: detected, LastAlertAt: detected.Add(-3*time.Hour - 20*time.Minute)},
+		{EventID: "e:m2", MarketQuestion: "50bp", Magnitude: 0.10, Direction: "increase", OldProbability: 0.10, NewProbability: 0.20,
+			TimeWindow: time.Hour, DetectedAt: detected},
+	}}}
+
+	if msg := (&Client{}).formatMessage(groups); strings.Contains(msg, "🕑") || strings.Contains(msg, "🆕") {
+		t.Errorf("unexpected last-alert lines with show_last_alert off:\n%s", msg)
+	}
+	msg := (&Client{showLastAlert: true}).formatMessage(groups)
+	if !strings.Contains(msg, "🕑 Last alerted 3h ago\n") {
+		t.Errorf("expected time since last alert:\n%s", msg)
+	}
+	if !strings.Contains(msg, "🆕 First alert\n") {
+		t.Errorf("expected first-alert note:\n%s", msg)
+	}
+}
+
+func TestFormatMessage_NoSide(t *testing.T) {
+	change := models.Change{
+		EventID: "e:m1", MarketQuestion: "Will the ceasefire NOT hold?",
+		Magnitude: 0.14, Direction: "increase", OldProbability: 0.38, NewProbability: 0.52,
+		TimeWindow: time.Hour, Side: models.SideNo,
+	}
+	groups := []models.Event{{ID: "e", Title: "Ceasefire", Markets: []models.Change{change}}}
+	msg := (&Client{}).formatMessage(groups)
+	if !strings.Contains(msg, "📉 *14\\.0%* \\(No 62\\.0% → 48\\.0%\\)") {
+		t.Errorf("expected No-side probabilities and flipped direction:\n%s", msg)
+	}
+
+	groups[0].Markets[0].Side = ""
+	msg = (&Client{}).formatMessage(groups)
+	if !strings.Contains(msg, "📈 *14\\.0%* \\(38\\.0% → 52\\.0%\\)") {
+		t.Errorf("expected Yes-side probabilities:\n%s", msg)
+	}
+}
+
+// fakeHistory serves canned alerts and records the last query.
+type fakeHistory struct {
+	alerts   []models.Change
+	gotID    string
+	gotN     int
+	gotSince time.Time
+}
+
+func (f *fakeHistory) GetAlertsForMarket(marketID string, limit int) ([]models.Change, error) {
+	f.gotID, f.gotN = marketID, limit
+	return f.alerts[:min(limit, len(f.alerts))], nil
+}
+
+func (f *fakeHistory) GetTopAlerts(since time.Time, limit int) ([]models.Change, error) {
+	f.gotSince, f.gotN = since, limit
+	return f.alerts[:min(limit, len(f.alerts))], nil
+}
+
+func TestCommandReply_History(t *testing.T) {
+	at := time.Date(2025, 3, 1, 14, 30, 0, 0, time.UTC)
+	history := &fakeHistory{alerts: []models.Change{
+		{MarketQuestion: "Fed cut in June?", Direction: "increase", OldProbability: 0.40, NewProbability: 0.48, SignalScore: 0.1234, DetectedAt: at},
+		{Direction: "decrease", OldProbability: 0.45, NewProbability: 0.40, SignalScore: 0.05, DetectedAt: at.Add(-time.Hour)},
+	}}
+	c := &Client{history: history}
+
+	want := "Last 2 alert(s) for 512 (Fed cut in June?)\n" +
+		"2025-03-01 14:30 ↑ 40.0% → 48.0% score 0.123\n" +
+		"2025-03-01 13:30 ↓ 45.0% → 40.0% score 0.050"
+	if got := c.commandReply("history", "#512"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if history.gotID != "512" || history.gotN != defaultHistoryAlerts {
+		t.Errorf("queried (%q, %d), want (\"512\", %d)", history.gotID, history.gotN, defaultHistoryAlerts)
+	}
+
+	c.commandReply("history", "512 1000")
+	if history.gotN != maxHistoryAlerts {
+		t.Errorf("n = %d, want clamped to %d", history.gotN, maxHistoryAlerts)
+	}
+
+	usage := "Usage: /history <marketID> [n]"
+	for _, args := range []string{"", "512 zero", "512 0", "a b c"} {
+		if got := c.commandReply("history", args); got != usage {
+			t.Errorf("args %q: got %q, want usage", args, got)
+		}
+	}
+	if got := (&Client{history: &fakeHistory{}}).commandReply("history", "999"); got != "No alerts recorded for 999" {
+		t.Errorf("empty history: got %q", got)
+	}
+	if got := (&Client{}).commandReply("history", "512"); got != "History is not available" {
+		t.Errorf("no history source: got %q", got)
+	}
+}
+
+func TestTopReply(t *testing.T) {
+	now := time.Date(2025, 3, 1, 16, 0, 0, 0, time.UTC)
+	history := &fakeHistory{alerts: []models.Change{
+		{EventTitle: "Fed decision", MarketQuestion: "Fed cut in June?", EventURL: "https://polymarket.com/event/fed",
+			Direction: "increase", OldProbability: 0.40, NewProbability: 0.48, SignalScore: 0.1234, DetectedAt: now.Add(-90 * time.Minute)},
+		{EventTitle: "Election", MarketQuestion: "Election", Direction: "decrease",
+			OldProbability: 0.45, NewProbability: 0.40, SignalScore: 0.05, DetectedAt: now.Add(-3 * time.Hour)},
+	}}
+	c := &Client{history: history}
+
+	want := "🏆 *Top 2 alert\\(s\\) in the last 24h*\n\n" +
+		"1\\. [Fed decision](https://polymarket.com/event/fed)\n" +
+		"   🎯 Fed cut in June?\n" +
+		"   📈 40\\.0% → 48\\.0% · score 0\\.123 · 2025\\-03\\-01 14:30\n\n" +
+		"2\\. Election\n" +
+		"   📉 45\\.0% → 40\\.0% · score 0\\.050 · 2025\\-03\\-01 13:00\n"
+	if got := c.topReply("", now); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if history.gotN != defaultTopAlerts || !history.gotSince.Equal(now.Add(-topAlertsWindow)) {
+		t.Errorf("queried (%v, %d), want (%v, %d)", history.gotSince, history.gotN, now.Add(-topAlertsWindow), defaultTopAlerts)
+	}
+
+	c.topReply("1000", now)
+	if history.gotN != maxTopAlerts {
+		t.Errorf("n = %d, want clamped to %d", history.gotN, maxTopAlerts)
+	}
+	for _, args := range []string{"zero", "0", "1 2"} {
+		if got := c.topReply(args, now); got != "Usage: /top \\[n\\]" {
+			t.Errorf("args %q: got %q, want usage", args, got)
+		}
+	}
+	if got := (&Client{history: &fakeHistory{}}).topReply("", now); got != "No alerts yet in the last 24h" {
+		t.Errorf("empty: got %q", got)
+	}
+	if got := (&Client{}).commandReply("top", ""); got != "Top alerts are not available" {
+		t.Errorf("no history source: got %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// DefaultSendConcurrency and DefaultSendInterval are used when the client
+// configuration leaves them unset.
+const (
+	DefaultSendConcurrency = 1
+	DefaultSendInterval    = 100 * time.Millisecond
+)
+
+// dispatcher is the single path for outbound bot messages. It bounds how many
+// sends run at once, spaces send starts by at least interval, and honours
+// Telegram's retry_after: a 429 on any send holds back every later send (to any
+// chat) until the flood-wait has passed, so one throttled request doesn't turn
+// into a bot-wide ban.
+type dispatcher struct {
+	send     func(tgbotapi.Chattable) error
+	sem      chan struct{}
+	interval time.Duration
+	now      func() time.Time
+	sleep    func(time.Duration)
+
+	mu   sync.Mutex
+	next time.Time // earliest start of the next send
+}
+
+func newDispatcher(send func(tgbotapi.Chattable) error, concurrency int, interval time.Duration) *dispatcher {
+	if concurrency <= 0 {
+		concurrency = DefaultSendConcurrency
+	}
+	if interval < 0 {
+		interval = 0
+	}
+	return &dispatcher{
+		send:     send,
+		sem:      make(chan struct{}, concurrency),
+		interval: interval,
+		now:      time.Now,
+		sleep:    time.Sleep,
+	}
+}
+
+// do sends msg once, waiting for a concurrency slot and the next allowed start.
+func (d *dispatcher) do(msg tgbotapi.Chattable) error {
+	d.sem <- struct{}{}
+	defer func() { <-d.sem }()
+
+	d.mu.Lock()
+	now := d.now()
+	start := now
+	if d.next.After(start) {
+		start = d.next
+	}
+	d.next = start.Add(d.interval)
+	d.mu.Unlock()
+	if wait := start.Sub(now); wait > 0 {
+		d.sleep(wait)
+	}
+
+	err := d.send(msg)
+	if wait := retryAfter(err); wait > 0 {
+		d.mu.Lock()
+		if until := d.now().Add(wait); until.After(d.next) {
+			d.next = until
+		}
+		d.mu.Unlock()
+	}
+	return err
+}
+
+// retryAfter returns the flood-wait Telegram asked for in a 429 response, or 0.
+func retryAfter(err error) time.Duration {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return time.Duration(apiErr.RetryAfter) * time.Second
+	}
+	return 0
+}
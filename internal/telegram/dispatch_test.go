@@ -0,0 +1,191 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeTime is a manual clock whose sleep advances it.
+type fakeTime struct{ t time.Time }
+
+func (f *fakeTime) now() time.Time                        { return f.t }
+func (f *fakeTime) sleep(d time.Duration)                 { f.t = f.t.Add(d) }
+func (f *fakeTime) elapsed(since time.Time) time.Duration { return f.t.Sub(since) }
+
+func TestDispatcher_SpacesSends(t *testing.T) {
+	clock := &fakeTime{t: time.Unix(0, 0)}
+	var starts []time.Time
+	d := newDispatcher(func(tgbotapi.Chattable) error {
+		starts = append(starts, clock.now())
+		return nil
+	}, 1, 100*time.Millisecond)
+	d.now, d.sleep = clock.now, clock.sleep
+
+	for range 3 {
+		if err := d.do(tgbotapi.NewMessage(1, "x")); err != nil {
+			t.Fatalf("do: %v", err)
+		}
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < 100*time.Millisecond {
+			t.Errorf("send %d started %v after the previous one, want ≥ 100ms", i, gap)
+		}
+	}
+}
+
+func TestDispatcher_RetryAfterDelaysLaterSends(t *testing.T) {
+	clock := &fakeTime{t: time.Unix(0, 0)}
+	start := clock.now()
+	calls := 0
+	d := newDispatcher(func(tgbotapi.Chattable) error {
+		calls++
+		if calls == 1 {
+			return &tgbotapi.Error{Code: 429, Message: "Too Many Requests",
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 3}}
+		}
+		return nil
+	}, 1, 0)
+	d.now, d.sleep = clock.now, clock.sleep
+
+	// The throttled chat's 429 holds back a send to a different chat.
+	if err := d.do(tgbotapi.NewMessage(1, "x")); err == nil {
+		t.Fatal("expected the 429 to be returned")
+	}
+	if err := d.do(tgbotapi.NewMessage(2, "y")); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if waited := clock.elapsed(start); waited < 3*time.Second {
+		t.Errorf("second send started after %v, want ≥ 3s retry_after", waited)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if got := retryAfter(errors.New("network down")); got != 0 {
+		t.Errorf("plain error: retryAfter = %v, want 0", got)
+	}
+	err := &tgbotapi.Error{Code: 429, ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 5}}
+	if got := retryAfter(err); got != 5*time.Second {
+		t.Errorf("429: retryAfter = %v, want 5s", got)
+	}
+}
+
+func TestSendMarkdownV2_PermanentErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		permanent bool
+		parse     bool
+	}{
+		{"network", errors.New("connection reset"), false, false},
+		{"flood wait", &tgbotapi.Error{Code: 429, Message: "Too Many Requests"}, false, false},
+		{"server", &tgbotapi.Error{Code: 502, Message: "Bad Gateway"}, false, false},
+		{"chat not found", &tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"}, true, false},
+		{"blocked", &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}, true, false},
+		{"markup", &tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities: Character '.' is reserved"}, true, true},
+	}
+	for _, tt := range tests {
+		if got := isPermanent(tt.err); got != tt.permanent {
+			t.Errorf("%s: isPermanent = %v, want %v", tt.name, got, tt.permanent)
+		}
+		if got := isParseError(tt.err); got != tt.parse {
+			t.Errorf("%s: isParseError = %v, want %v", tt.name, got, tt.parse)
+		}
+	}
+
+	for _, retry := range []bool{false, true} {
+		attempts := 0
+		c := &Client{
+			maxRetries:     3,
+			retryDelayBase: time.Nanosecond,
+			retryPermanent: retry,
+			dispatch: newDispatcher(func(tgbotapi.Chattable) error {
+				attempts++
+				return &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was kicked"}
+			}, 1, 0),
+		}
+		if err := c.sendMarkdownV2("hi", "test message"); err == nil {
+			t.Fatal("expected an error")
+		}
+		want := 1
+		if retry {
+			want = 3
+		}
+		if attempts != want {
+			t.Errorf("retryPermanent=%v: %d attempts, want %d", retry, attempts, want)
+		}
+	}
+}
+
+func TestSendMarkdownV2_PlainTextFallback(t *testing.T) {
+	var sent []tgbotapi.MessageConfig
+	c := &Client{
+		maxRetries:        3,
+		retryDelayBase:    time.Nanosecond,
+		plainTextFallback: true,
+		dispatch: newDispatcher(func(m tgbotapi.Chattable) error {
+			msg := m.(tgbotapi.MessageConfig)
+			sent = append(sent, msg)
+			if msg.ParseMode == "MarkdownV2" {
+				return &tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities: unexpected end"}
+			}
+			return nil
+		}, 1, 0),
+	}
+	text := "🚨 *[Fed cuts 50bp?](https://polymarket.com/event/fed\\-cut)*\n   _Yes_ 40\\.0% → 55\\.5% `#12\\-a`"
+	if err := c.sendMarkdownV2(text, "alert"); err != nil {
+		t.Fatalf("sendMarkdownV2: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("got %d sends, want MarkdownV2 then plain text", len(sent))
+	}
+	want := "🚨 Fed cuts 50bp? (https://polymarket.com/event/fed-cut)\n   Yes 40.0% → 55.5% #12-a"
+	if sent[1].ParseMode != "" || sent[1].Text != want {
+		t.Errorf("fallback = %q (mode %q), want %q", sent[1].Text, sent[1].ParseMode, want)
+	}
+
+	// Without the fallback the parse error is final
+	sent = nil
+	c.plainTextFallback = false
+	if err := c.sendMarkdownV2(text, "alert"); err == nil || len(sent) != 1 {
+		t.Errorf("err = %v after %d sends, want an error after 1", err, len(sent))
+	}
+}
+
+func TestSendMarkdownV2_MirrorsToTestChat(t *testing.T) {
+	var sent []tgbotapi.MessageConfig
+	c := &Client{
+		chatID:         100,
+		testChatID:     200,
+		maxRetries:     3,
+		retryDelayBase: time.Nanosecond,
+		dispatch: newDispatcher(func(m tgbotapi.Chattable) error {
+			msg := m.(tgbotapi.MessageConfig)
+			sent = append(sent, msg)
+			if msg.ChatID == 200 {
+				return errors.New("test chat unreachable")
+			}
+			return nil
+		}, 1, 0),
+	}
+	if err := c.sendMarkdownV2("*alert*", "message"); err != nil {
+		t.Fatalf("a failed mirror must not fail the send: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("got %d sends, want the test copy then the real one", len(sent))
+	}
+	if sent[0].ChatID != 200 || sent[0].Text != testChatPrefix+"*alert*" || sent[0].ParseMode != "MarkdownV2" {
+		t.Errorf("test copy = %+v", sent[0])
+	}
+	if sent[1].ChatID != 100 || sent[1].Text != "*alert*" {
+		t.Errorf("real send = %+v", sent[1])
+	}
+
+	sent = nil
+	c.testChatID = 0
+	if err := c.sendMarkdownV2("*alert*", "message"); err != nil || len(sent) != 1 {
+		t.Errorf("without a test chat: err=%v, %d sends, want 1", err, len(sent))
+	}
+}
@@ -0,0 +1,376 @@
+// Package telemetry provides optional OpenTelemetry tracing for monitoring cycles.
+//
+// Spans are exported to an OpenTelemetry collector using OTLP over HTTP with the
+// JSON encoding (POST {endpoint}/v1/traces), which every OTLP/HTTP receiver accepts.
+// Like the metrics package, this avoids pulling the full SDK (and its gRPC
+// dependency tree) into this small service; only the features the monitoring loop
+// needs are implemented: nested spans, attributes, span events, and error status.
+//
+// Until Init is called, Start returns nil spans and every Span method is a no-op,
+// so call sites never need to check whether tracing is enabled.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+const (
+	serviceName = "polyoracle"
+	scopeName   = "github.com/rewired-gh/polyoracle"
+
+	// exportTimeout bounds a single OTLP export request.
+	exportTimeout = 10 * time.Second
+)
+
+// OTLP status codes and span kinds (see opentelemetry-proto trace.proto).
+const (
+	statusCodeError  = 2
+	spanKindInternal = 1
+)
+
+// exporter buffers ended spans and sends each finished trace to the collector.
+type exporter struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	pending []*Span
+	wg      sync.WaitGroup
+}
+
+var (
+	activeMu sync.RWMutex
+	active   *exporter // nil = tracing disabled
+)
+
+// Init enables span export to the OTLP/HTTP collector at endpoint
+// (e.g. "http://localhost:4318").
+func Init(endpoint string) error {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return fmt.Errorf("otel endpoint must be an http(s) URL, got %q", endpoint)
+	}
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active = &exporter{
+		url:    strings.TrimSuffix(endpoint, "/") + "/v1/traces",
+		client: &http.Client{Timeout: exportTimeout},
+	}
+	return nil
+}
+
+// Shutdown waits for in-flight exports, flushes any buffered spans and disables
+// tracing. Safe to call when tracing was never enabled.
+func Shutdown(ctx context.Context) error {
+	activeMu.Lock()
+	e := active
+	active = nil
+	activeMu.Unlock()
+	if e == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		e.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	e.mu.Lock()
+	spans := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+	if len(spans) == 0 {
+		return nil
+	}
+	return e.export(ctx, spans)
+}
+
+// Attr is a span or event attribute.
+type Attr struct {
+	Key   string
+	Value any // string, int, int64, float64 or bool
+}
+
+// String returns a string attribute.
+func String(key, v string) Attr { return Attr{Key: key, Value: v} }
+
+// Int returns an integer attribute.
+func Int(key string, v int) Attr { return Attr{Key: key, Value: int64(v)} }
+
+// Float returns a floating-point attribute.
+func Float(key string, v float64) Attr { return Attr{Key: key, Value: v} }
+
+type spanEvent struct {
+	name  string
+	time  time.Time
+	attrs []Attr
+}
+
+// Span is one timed operation within a trace. A nil *Span is a valid no-op span.
+type Span struct {
+	exp      *exporter
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	root     bool
+	name     string
+	start    time.Time
+
+	mu     sync.Mutex
+	end    time.Time
+	ended  bool
+	attrs  []Attr
+	events []spanEvent
+	errMsg string
+}
+
+type spanKey struct{}
+
+// Start begins a span named name, as a child of the span in ctx if any. It
+// returns a context carrying the new span. When tracing is disabled it returns
+// ctx unchanged and a nil span.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	activeMu.RLock()
+	e := active
+	activeMu.RUnlock()
+	if e == nil {
+		return ctx, nil
+	}
+
+	s := &Span{exp: e, name: name, start: time.Now()}
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID = parent.traceID
+		s.parentID = parent.spanID
+	} else {
+		s.root = true
+		_, _ = rand.Read(s.traceID[:])
+	}
+	_, _ = rand.Read(s.spanID[:])
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// AddEvent records a named point-in-time event on the span.
+func (s *Span) AddEvent(name string, attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.events = append(s.events, spanEvent{name: name, time: time.Now(), attrs: attrs})
+	s.mu.Unlock()
+}
+
+// RecordError marks the span as failed with err's message.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.errMsg = err.Error()
+	s.mu.Unlock()
+}
+
+// End finishes the span. Ending a root span exports its whole trace in the
+// background. Calling End more than once has no effect.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	e := s.exp
+	e.mu.Lock()
+	e.pending = append(e.pending, s)
+	var batch []*Span
+	if s.root {
+		batch = e.pending
+		e.pending = nil
+	}
+	e.mu.Unlock()
+
+	if batch != nil {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			defer cancel()
+			// Tracing is best-effort; a collector outage must not affect monitoring.
+			_ = e.export(ctx, batch)
+		}()
+	}
+}
+
+// RecordAlerts adds one "alert" event per market in groups to span.
+func RecordAlerts(span *Span, groups []models.Event) {
+	for _, g := range groups {
+		for _, change := range g.Markets {
+			span.AddEvent("alert",
+				String("market.id", change.EventID),
+				String("event.title", g.Title),
+				String("alert.direction", change.Direction),
+				Float("alert.old_probability", change.OldProbability),
+				Float("alert.new_probability", change.NewProbability),
+				Float("alert.score", change.SignalScore),
+			)
+		}
+	}
+}
+
+// --- OTLP/JSON encoding ---
+
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+func (e *exporter) export(ctx context.Context, spans []*Span) error {
+	body, err := json.Marshal(encodeTraces(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("OTLP collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// encodeTraces builds an OTLP ExportTraceServiceRequest in its JSON mapping.
+func encodeTraces(spans []*Span) map[string]any {
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.mu.Lock()
+		out := otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Attributes:        encodeAttrs(s.attrs),
+		}
+		if !s.root {
+			out.ParentSpanID = hex.EncodeToString(s.parentID[:])
+		}
+		for _, ev := range s.events {
+			out.Events = append(out.Events, otlpEvent{
+				TimeUnixNano: unixNano(ev.time),
+				Name:         ev.name,
+				Attributes:   encodeAttrs(ev.attrs),
+			})
+		}
+		if s.errMsg != "" {
+			out.Status = otlpStatus{Code: statusCodeError, Message: s.errMsg}
+		}
+		s.mu.Unlock()
+		encoded = append(encoded, out)
+	}
+
+	return map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{
+				"attributes": encodeAttrs([]Attr{String("service.name", serviceName)}),
+			},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]any{"name": scopeName},
+				"spans": encoded,
+			}},
+		}},
+	}
+}
+
+func encodeAttrs(attrs []Attr) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]any
+		switch val := a.Value.(type) {
+		case string:
+			v = map[string]any{"stringValue": val}
+		case int:
+			v = map[string]any{"intValue": strconv.Itoa(val)}
+		case int64:
+			v = map[string]any{"intValue": strconv.FormatInt(val, 10)} // int64 is a JSON string in OTLP
+		case float64:
+			v = map[string]any{"doubleValue": val}
+		case bool:
+			v = map[string]any{"boolValue": val}
+		default:
+			v = map[string]any{"stringValue": fmt.Sprint(val)}
+		}
+		kvs = append(kvs, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return kvs
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
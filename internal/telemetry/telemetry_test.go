@@ -0,0 +1,128 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+func TestStart_DisabledIsNoop(t *testing.T) {
+	ctx, span := Start(context.Background(), "cycle")
+	if span != nil {
+		t.Fatal("expected nil span when tracing is disabled")
+	}
+	// Every method must be safe on the nil span.
+	span.SetAttributes(Int("n", 1))
+	span.AddEvent("alert")
+	span.RecordError(errors.New("boom"))
+	span.End()
+	if ctx == nil {
+		t.Fatal("Start returned nil context")
+	}
+	if err := Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown without Init: %v", err)
+	}
+}
+
+func TestInit_RejectsNonHTTPEndpoint(t *testing.T) {
+	if err := Init("localhost:4318"); err == nil {
+		t.Error("expected error for endpoint without scheme")
+	}
+}
+
+// otlpRequest mirrors the parts of the OTLP/JSON payload the test inspects.
+type otlpRequest struct {
+	ResourceSpans []struct {
+		ScopeSpans []struct {
+			Spans []struct {
+				TraceID      string `json:"traceId"`
+				SpanID       string `json:"spanId"`
+				ParentSpanID string `json:"parentSpanId"`
+				Name         string `json:"name"`
+				Attributes   []struct {
+					Key   string         `json:"key"`
+					Value map[string]any `json:"value"`
+				} `json:"attributes"`
+				Events []struct {
+					Name string `json:"name"`
+				} `json:"events"`
+				Status struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"status"`
+			} `json:"spans"`
+		} `json:"scopeSpans"`
+	} `json:"resourceSpans"`
+}
+
+func TestExport_CycleTrace(t *testing.T) {
+	received := make(chan otlpRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("path = %s, want /v1/traces", r.URL.Path)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req otlpRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("invalid OTLP JSON: %v\n%s", err, body)
+		}
+		received <- req
+	}))
+	defer srv.Close()
+
+	if err := Init(srv.URL); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	ctx, cycle := Start(context.Background(), "monitoring_cycle")
+	_, fetch := Start(ctx, "fetch")
+	fetch.End()
+	cycle.SetAttributes(Int("markets.fetched", 42))
+	RecordAlerts(cycle, []models.Event{{Title: "Event", Markets: []models.Change{
+		{EventID: "e:1", Direction: "increase"},
+		{EventID: "e:2", Direction: "decrease"},
+	}}})
+	cycle.RecordError(errors.New("notify failed"))
+	cycle.End()
+	cycle.End() // second End is ignored
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	req := <-received
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	child, root := spans[0], spans[1]
+	if child.Name != "fetch" || root.Name != "monitoring_cycle" {
+		t.Fatalf("span names = %q, %q", child.Name, root.Name)
+	}
+	if child.TraceID != root.TraceID || child.ParentSpanID != root.SpanID || root.ParentSpanID != "" {
+		t.Errorf("fetch span is not a child of the cycle span")
+	}
+	if len(root.Attributes) != 1 || root.Attributes[0].Value["intValue"] != "42" {
+		t.Errorf("unexpected root attributes: %+v", root.Attributes)
+	}
+	if len(root.Events) != 2 || root.Events[0].Name != "alert" {
+		t.Errorf("expected 2 alert events, got %+v", root.Events)
+	}
+	if root.Status.Code != statusCodeError || root.Status.Message != "notify failed" {
+		t.Errorf("unexpected status: %+v", root.Status)
+	}
+	select {
+	case extra := <-received:
+		t.Errorf("unexpected second export: %+v", extra)
+	default:
+	}
+}
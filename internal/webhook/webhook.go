@@ -0,0 +1,143 @@
+// Package webhook delivers alerts to an HTTP endpoint as JSON, for chat bridges
+// (Slack, Discord) and internal services that don't use Telegram.
+//
+// Every notification is one POST of a Payload. Server errors, 429s and transport
+// failures are retried with linear backoff; other 4xx responses fail fast,
+// since resending the same body can't fix them.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+// Payload types.
+const (
+	TypeAlerts     = "alerts"
+	TypeError      = "error"
+	TypeRecovery   = "recovery"
+	TypeNewMarkets = "new_markets"
+)
+
+// Payload is the JSON body of every webhook request. Only the fields of its
+// Type are set.
+type Payload struct {
+	Type     string          `json:"type"`
+	SentAt   time.Time       `json:"sent_at"`
+	Groups   []models.Event  `json:"groups,omitempty"`   // alerts: ranked event groups
+	Error    string          `json:"error,omitempty"`    // error: the failed cycle's error
+	Failures int             `json:"failures,omitempty"` // recovery: failed cycles it ends
+	Markets  []models.Market `json:"markets,omitempty"`  // new_markets: first-seen markets
+}
+
+// Client posts notifications to one webhook URL.
+type Client struct {
+	url            string
+	httpClient     *http.Client
+	maxRetries     int
+	retryDelayBase time.Duration
+}
+
+// NewClient creates a webhook client for rawURL, which must be an absolute
+// http or https URL. Non-positive retry settings fall back to 3 attempts and
+// a 1s backoff base; timeout bounds each attempt (0 = 10s).
+func NewClient(rawURL string, maxRetries int, retryDelayBase, timeout time.Duration) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q", rawURL)
+	}
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	if retryDelayBase <= 0 {
+		retryDelayBase = time.Second
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Client{
+		url:            rawURL,
+		httpClient:     &http.Client{Timeout: timeout},
+		maxRetries:     maxRetries,
+		retryDelayBase: retryDelayBase,
+	}, nil
+}
+
+// Send posts alert groups.
+func (c *Client) Send(groups []models.Event) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	return c.post(Payload{Type: TypeAlerts, SentAt: time.Now(), Groups: groups})
+}
+
+// SendError posts a monitoring cycle failure.
+func (c *Client) SendError(cycleErr error) error {
+	return c.post(Payload{Type: TypeError, SentAt: time.Now(), Error: cycleErr.Error()})
+}
+
+// SendRecovery posts that monitoring recovered after failureCount failed cycles.
+func (c *Client) SendRecovery(failureCount int) error {
+	return c.post(Payload{Type: TypeRecovery, SentAt: time.Now(), Failures: failureCount})
+}
+
+// SendNewMarkets posts newly listed markets.
+func (c *Client) SendNewMarkets(markets []models.Market) error {
+	if len(markets) == 0 {
+		return nil
+	}
+	return c.post(Payload{Type: TypeNewMarkets, SentAt: time.Now(), Markets: markets})
+}
+
+func (c *Client) post(p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook %s payload: %w", p.Type, err)
+	}
+
+	var lastErr error
+	for i := 0; i < c.maxRetries; i++ {
+		retry, err := c.attempt(body)
+		if err == nil {
+			return nil
+		}
+		if !retry {
+			return fmt.Errorf("failed to send webhook %s (permanent error, not retried): %w", p.Type, err)
+		}
+		lastErr = err
+		time.Sleep(c.retryDelayBase * time.Duration(i+1))
+	}
+	return fmt.Errorf("failed to send webhook %s after %d retries: %w", p.Type, c.maxRetries, lastErr)
+}
+
+// attempt makes one POST and reports whether a failure is worth retrying.
+func (c *Client) attempt(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("server responded %s", resp.Status)
+	default:
+		return false, fmt.Errorf("server responded %s", resp.Status)
+	}
+}
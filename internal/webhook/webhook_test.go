@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rewired-gh/polyoracle/internal/models"
+)
+
+// testServer answers with statuses in turn (the last repeats) and records the
+// decoded payloads.
+func testServer(t *testing.T, statuses ...int) (*httptest.Server, *[]Payload) {
+	t.Helper()
+	var got []Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" || r.Method != http.MethodPost {
+			t.Errorf("got %s with Content-Type %q", r.Method, ct)
+		}
+		var p Payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		got = append(got, p)
+		w.WriteHeader(statuses[min(len(got), len(statuses))-1])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &got
+}
+
+func TestSend_PostsGroups(t *testing.T) {
+	srv, got := testServer(t, http.StatusNoContent)
+	c, err := NewClient(srv.URL, 3, time.Millisecond, 0)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	groups := []models.Event{{ID: "e-1", Title: "Fed decision", Markets: []models.Change{{ID: "c-1", NewProbability: 0.6}}}}
+	if err := c.Send(groups); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*got) != 1 {
+		t.Fatalf("got %d requests, want 1", len(*got))
+	}
+	p := (*got)[0]
+	if p.Type != TypeAlerts || len(p.Groups) != 1 || p.Groups[0].Title != "Fed decision" || p.Groups[0].Markets[0].ID != "c-1" {
+		t.Errorf("unexpected payload: %+v", p)
+	}
+
+	if err := c.SendError(errors.New("fetch failed")); err != nil {
+		t.Fatalf("SendError: %v", err)
+	}
+	if err := c.SendRecovery(4); err != nil {
+		t.Fatalf("SendRecovery: %v", err)
+	}
+	if e, r := (*got)[1], (*got)[2]; e.Type != TypeError || e.Error != "fetch failed" || r.Type != TypeRecovery || r.Failures != 4 {
+		t.Errorf("unexpected error/recovery payloads: %+v, %+v", e, r)
+	}
+
+	if err := c.SendNewMarkets([]models.Market{{ID: "e-2:m-1", Title: "New listing"}}); err != nil {
+		t.Fatalf("SendNewMarkets: %v", err)
+	}
+	if p := (*got)[3]; p.Type != TypeNewMarkets || len(p.Markets) != 1 || p.Markets[0].ID != "e-2:m-1" {
+		t.Errorf("unexpected new_markets payload: %+v", p)
+	}
+}
+
+func TestSend_RetriesServerErrors(t *testing.T) {
+	srv, got := testServer(t, http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK)
+	c, _ := NewClient(srv.URL, 3, time.Millisecond, 0)
+
+	if err := c.SendRecovery(1); err != nil {
+		t.Fatalf("SendRecovery: %v", err)
+	}
+	if len(*got) != 3 {
+		t.Errorf("got %d attempts, want 3", len(*got))
+	}
+}
+
+func TestSend_ClientErrorsFailFast(t *testing.T) {
+	srv, got := testServer(t, http.StatusBadRequest)
+	c, _ := NewClient(srv.URL, 3, time.Millisecond, 0)
+
+	if err := c.SendRecovery(1); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(*got) != 1 {
+		t.Errorf("got %d attempts, want 1", len(*got))
+	}
+}
+
+func TestNewClient_RejectsInvalidURL(t *testing.T) {
+	for _, u := range []string{"", "example.com/hook", "ftp://example.com", "http://"} {
+		if _, err := NewClient(u, 0, 0, 0); err == nil {
+			t.Errorf("NewClient(%q): expected an error", u)
+		}
+	}
+}